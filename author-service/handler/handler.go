@@ -43,6 +43,19 @@ func (h *AuthorHandler) Get(ctx context.Context, body *author.Author) (*author.A
 	return h.as.GetAuthor(ctx, body)
 }
 
+func (h *AuthorHandler) BatchGet(ctx context.Context, body *author.BatchGetAuthorsRequest) (*author.BatchGetAuthorsResponse, error) {
+	return h.as.BatchGetAuthors(ctx, body)
+}
+
+func (h *AuthorHandler) Resolve(ctx context.Context, body *author.ResolveAuthorsRequest) (*author.ResolveAuthorsResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.as.ResolveAuthors(ctx, body)
+}
+
 func (h *AuthorHandler) Update(ctx context.Context, body *author.Author) (*author.CommonAuthorResponse, error) {
 	_, err := getUserIDFromContext(ctx)
 	if err != nil {
@@ -70,6 +83,24 @@ func (h *AuthorHandler) Delete(ctx context.Context, body *author.Author) (*autho
 	return h.as.DeleteAuthor(ctx, body)
 }
 
+func (h *AuthorHandler) ListDeleted(ctx context.Context, body *author.AuthorRequest) (*author.AuthorsResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.as.ListDeletedAuthors(ctx, body)
+}
+
+func (h *AuthorHandler) Restore(ctx context.Context, body *author.Author) (*author.CommonAuthorResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.as.RestoreAuthor(ctx, body)
+}
+
 func getUserIDFromContext(ctx context.Context) (string, error) {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
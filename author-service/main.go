@@ -1,10 +1,12 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net"
 	"os"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/shafaalafghany/author-service/handler"
@@ -12,7 +14,9 @@ import (
 	"github.com/shafaalafghany/author-service/model"
 	"github.com/shafaalafghany/author-service/repository"
 	"github.com/shafaalafghany/author-service/service"
+	"gitlab.com/shafaalafghany/synapsis-common/purge"
 	"gitlab.com/shafaalafghany/synapsis-proto/go/author"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/book"
 	"gitlab.com/shafaalafghany/synapsis-proto/go/user"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -32,6 +36,10 @@ type Config struct {
 	JwtSecret   string
 	AppPort     string
 	UserService string
+	BookService string
+
+	PurgeRetention string
+	PurgeInterval  string
 }
 
 func main() {
@@ -46,6 +54,10 @@ func main() {
 		DBPassword:  os.Getenv("DB_PASS"),
 		DBName:      os.Getenv("DB_NAME"),
 		UserService: os.Getenv("USER_SERVICE"),
+		BookService: os.Getenv("BOOK_SERVICE"),
+
+		PurgeRetention: os.Getenv("PURGE_RETENTION"),
+		PurgeInterval:  os.Getenv("PURGE_INTERVAL"),
 	}
 
 	logConfig := zap.NewDevelopmentConfig()
@@ -76,6 +88,32 @@ func main() {
 	authorService := service.NewAuthorService(authorRepo, logger, userClient)
 	authorHandler := handler.NewAuthorHandler(authorService, logger)
 
+	if config.PurgeRetention != "" {
+		retention, err := time.ParseDuration(config.PurgeRetention)
+		if err != nil {
+			log.Fatalf("invalid PURGE_RETENTION %v", err)
+		}
+
+		interval := 24 * time.Hour
+		if config.PurgeInterval != "" {
+			if interval, err = time.ParseDuration(config.PurgeInterval); err != nil {
+				log.Fatalf("invalid PURGE_INTERVAL %v", err)
+			}
+		}
+
+		if config.BookService == "" {
+			log.Fatalf("PURGE_RETENTION requires BOOK_SERVICE to check which authors books still use")
+		}
+		bookConn, err := grpc.NewClient(config.BookService, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			log.Fatalf("failed to connect book service %v", err)
+		}
+		defer bookConn.Close()
+
+		referenced := service.ReferencedByBooks(book.NewBookServiceClient(bookConn), config.JwtSecret)
+		go purge.NewJob(purge.SkipReferenced(authorRepo, referenced), "authors", logger, retention, interval).Run(context.Background())
+	}
+
 	server := grpc.NewServer(
 		grpc.UnaryInterceptor(middleware.JWTAuthInterceptor(config.JwtSecret)),
 	)
@@ -1,19 +1,29 @@
 package repository
 
 import (
+	"strings"
 	"time"
 
 	"github.com/shafaalafghany/author-service/model"
+	"gitlab.com/shafaalafghany/synapsis-common/pagination"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
 type AuthorRepositoryInterface interface {
 	Create(*model.Author) error
+	CreateBatch([]*model.Author) error
 	GetById(string) (*model.Author, error)
-	Get(string) ([]*model.Author, error)
+	GetByIds([]string) ([]*model.Author, error)
+	GetByNames([]string) ([]*model.Author, error)
+	Get(pagination.ListOptions) (*AuthorPage, error)
 	Update(*model.Author, string) error
 	Delete(string) error
+	GetDeleted(pagination.ListOptions) (*AuthorPage, error)
+	GetDeletedById(string) (*model.Author, error)
+	Restore(string) error
+	Purgeable(time.Time, string, int) ([]string, error)
+	PurgeIds(time.Time, []string) (int64, error)
 }
 
 type AuthorRepository struct {
@@ -21,6 +31,25 @@ type AuthorRepository struct {
 	log *zap.Logger
 }
 
+type AuthorPage struct {
+	Authors       []*model.Author
+	NextPageToken string
+	TotalCount    int64
+}
+
+var authorSortColumns = map[string]pagination.SortKind{
+	"name":       pagination.SortString,
+	"created_at": pagination.SortTime,
+	"updated_at": pagination.SortTime,
+}
+
+var deletedAuthorSortColumns = map[string]pagination.SortKind{
+	"name":       pagination.SortString,
+	"created_at": pagination.SortTime,
+	"updated_at": pagination.SortTime,
+	"deleted_at": pagination.SortTime,
+}
+
 func NewAuthorRepository(db *gorm.DB, log *zap.Logger) AuthorRepositoryInterface {
 	return &AuthorRepository{
 		db:  db,
@@ -35,6 +64,16 @@ func (r *AuthorRepository) Create(data *model.Author) error {
 	return nil
 }
 
+// CreateBatch inserts the authors in one statement, so either all of them
+// are created or none are.
+func (r *AuthorRepository) CreateBatch(data []*model.Author) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	return r.db.Create(&data).Error
+}
+
 func (r *AuthorRepository) GetById(id string) (*model.Author, error) {
 	var author model.Author
 	if err := r.db.Where("id = ? AND deleted_at IS NULL", id).First(&author).Error; err != nil {
@@ -44,21 +83,43 @@ func (r *AuthorRepository) GetById(id string) (*model.Author, error) {
 	return &author, nil
 }
 
-func (r *AuthorRepository) Get(search string) ([]*model.Author, error) {
+func (r *AuthorRepository) GetByIds(ids []string) ([]*model.Author, error) {
 	var authors []*model.Author
-	base := r.db.Model(&model.Author{}).Where("deleted_at IS NULL")
+	if err := r.db.Where("id IN ? AND deleted_at IS NULL", ids).Find(&authors).Error; err != nil {
+		return nil, err
+	}
 
-	if search != "" {
-		base.Where("name ILIKE ?", "%"+search+"%")
+	return authors, nil
+}
+
+func (r *AuthorRepository) GetByNames(names []string) ([]*model.Author, error) {
+	lowered := make([]string, 0, len(names))
+	for _, name := range names {
+		lowered = append(lowered, strings.ToLower(name))
 	}
 
-	if err := base.Find(&authors).Error; err != nil {
+	var authors []*model.Author
+	if err := r.db.Where("LOWER(name) IN ? AND deleted_at IS NULL", lowered).Order("created_at ASC").Find(&authors).Error; err != nil {
 		return nil, err
 	}
 
 	return authors, nil
 }
 
+func (r *AuthorRepository) Get(opts pagination.ListOptions) (*AuthorPage, error) {
+	if err := opts.Normalize(authorSortColumns, "created_at", "asc"); err != nil {
+		return nil, err
+	}
+
+	base := r.db.Model(&model.Author{}).Where("deleted_at IS NULL")
+
+	if opts.Search != "" {
+		base.Where("name ILIKE ?", "%"+opts.Search+"%")
+	}
+
+	return r.page(base, opts, authorSortColumns)
+}
+
 func (r *AuthorRepository) Update(data *model.Author, id string) error {
 	if err := r.db.Model(&model.Author{}).Where("id = ? AND deleted_at IS NULL", id).Update("name", data.Name).Error; err != nil {
 		return err
@@ -72,3 +133,100 @@ func (r *AuthorRepository) Delete(id string) error {
 	}
 	return nil
 }
+
+func (r *AuthorRepository) GetDeleted(opts pagination.ListOptions) (*AuthorPage, error) {
+	if err := opts.Normalize(deletedAuthorSortColumns, "deleted_at", "desc"); err != nil {
+		return nil, err
+	}
+
+	base := r.db.Model(&model.Author{}).Where("deleted_at IS NOT NULL")
+
+	if opts.Search != "" {
+		base.Where("name ILIKE ?", "%"+opts.Search+"%")
+	}
+
+	return r.page(base, opts, deletedAuthorSortColumns)
+}
+
+func (r *AuthorRepository) GetDeletedById(id string) (*model.Author, error) {
+	var author model.Author
+	if err := r.db.Where("id = ? AND deleted_at IS NOT NULL", id).First(&author).Error; err != nil {
+		return nil, err
+	}
+
+	return &author, nil
+}
+
+func (r *AuthorRepository) Restore(id string) error {
+	if err := r.db.Model(&model.Author{}).Where("id = ? AND deleted_at IS NOT NULL", id).Update("deleted_at", nil).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// Purgeable lists authors that have been in the trash since before the
+// given time, for the purge job to check against book-service.
+func (r *AuthorRepository) Purgeable(before time.Time, after string, limit int) ([]string, error) {
+	var ids []string
+	if err := r.db.Model(&model.Author{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ? AND id::text > ?", before, after).
+		Order("id::text ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (r *AuthorRepository) PurgeIds(before time.Time, ids []string) (int64, error) {
+	result := r.db.Where("id IN ? AND deleted_at IS NOT NULL AND deleted_at < ?", ids, before).Delete(&model.Author{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+func (r *AuthorRepository) page(base *gorm.DB, opts pagination.ListOptions, columns map[string]pagination.SortKind) (*AuthorPage, error) {
+	page := &AuthorPage{}
+
+	if opts.IncludeTotal {
+		if err := base.Session(&gorm.Session{}).Count(&page.TotalCount).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	query, err := pagination.Paginate(base.Session(&gorm.Session{}), opts, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := query.Find(&page.Authors).Error; err != nil {
+		return nil, err
+	}
+
+	if len(page.Authors) > opts.PageSize {
+		page.Authors = page.Authors[:opts.PageSize]
+		last := page.Authors[len(page.Authors)-1]
+		page.NextPageToken = pagination.EncodePageToken(opts, authorSortValue(last, opts.SortBy), last.ID)
+	}
+
+	return page, nil
+}
+
+func authorSortValue(data *model.Author, sortBy string) interface{} {
+	switch sortBy {
+	case "name":
+		return data.Name
+	case "updated_at":
+		return data.UpdatedAt
+	case "deleted_at":
+		if data.DeletedAt != nil {
+			return *data.DeletedAt
+		}
+		return nil
+	default:
+		return data.CreatedAt
+	}
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"gitlab.com/shafaalafghany/synapsis-common/auth"
+	"gitlab.com/shafaalafghany/synapsis-common/purge"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/book"
+)
+
+const referenceTimeout = 30 * time.Second
+
+// ReferencedByBooks asks book-service which of the given authors books still
+// point at. The purge job keeps those in the trash.
+func ReferencedByBooks(books book.BookServiceClient, secret string) purge.ReferencedFunc {
+	return func(ids []string) ([]string, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), referenceTimeout)
+		defer cancel()
+
+		ctx, err := auth.ServiceContext(ctx, secret, "author-service")
+		if err != nil {
+			return nil, err
+		}
+
+		res, err := books.ReferencedIds(ctx, &book.ReferencedIdsRequest{AuthorIds: ids})
+		if err != nil {
+			return nil, err
+		}
+
+		return res.GetAuthorIds(), nil
+	}
+}
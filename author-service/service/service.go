@@ -2,11 +2,16 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/shafaalafghany/author-service/model"
 	"github.com/shafaalafghany/author-service/repository"
+	"gitlab.com/shafaalafghany/synapsis-common/auth"
+	"gitlab.com/shafaalafghany/synapsis-common/pagination"
 	"gitlab.com/shafaalafghany/synapsis-proto/go/author"
 	"gitlab.com/shafaalafghany/synapsis-proto/go/user"
 	"go.uber.org/zap"
@@ -14,14 +19,24 @@ import (
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"gorm.io/gorm"
+)
+
+const (
+	roleAdmin    = "admin"
+	maxBatchSize = 100
 )
 
 type AuthorServiceInterface interface {
 	CreateAuthor(context.Context, *author.Author) (*author.CommonAuthorResponse, error)
 	GetAuthor(context.Context, *author.Author) (*author.Author, error)
 	GetAuthors(context.Context, *author.AuthorRequest) (*author.AuthorsResponse, error)
+	BatchGetAuthors(context.Context, *author.BatchGetAuthorsRequest) (*author.BatchGetAuthorsResponse, error)
+	ResolveAuthors(context.Context, *author.ResolveAuthorsRequest) (*author.ResolveAuthorsResponse, error)
 	UpdateAuthor(context.Context, *author.Author) (*author.CommonAuthorResponse, error)
 	DeleteAuthor(context.Context, *author.Author) (*author.CommonAuthorResponse, error)
+	ListDeletedAuthors(context.Context, *author.AuthorRequest) (*author.AuthorsResponse, error)
+	RestoreAuthor(context.Context, *author.Author) (*author.CommonAuthorResponse, error)
 }
 
 type AuthorService struct {
@@ -63,7 +78,7 @@ func (s *AuthorService) CreateAuthor(ctx context.Context, body *author.Author) (
 
 	response := fmt.Sprintf("create new author successfully with id %v", id)
 
-	return &author.CommonAuthorResponse{Message: response}, nil
+	return &author.CommonAuthorResponse{Message: response, Id: id}, nil
 }
 
 func (s *AuthorService) GetAuthor(ctx context.Context, body *author.Author) (*author.Author, error) {
@@ -87,6 +102,146 @@ func (s *AuthorService) GetAuthor(ctx context.Context, body *author.Author) (*au
 	return res, nil
 }
 
+func (s *AuthorService) BatchGetAuthors(ctx context.Context, body *author.BatchGetAuthorsRequest) (*author.BatchGetAuthorsResponse, error) {
+	ids, invalid, err := batchIds(body.GetIds())
+	if err != nil {
+		return nil, err
+	}
+
+	// book-service refreshes the author names it keeps on books with a
+	// service token, which has no user behind it.
+	if !auth.IsService(ctx, os.Getenv("SECRET_KEY")) {
+		md, ok := metadata.FromIncomingContext(ctx)
+		if !ok {
+			return nil, status.Error(codes.Internal, "missing outgoing metadata")
+		}
+		outbondCtx := metadata.NewOutgoingContext(ctx, md)
+
+		_, err = s.userService.GetUser(outbondCtx, &emptypb.Empty{})
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "invalid user")
+		}
+	}
+
+	if len(ids) == 0 {
+		return &author.BatchGetAuthorsResponse{Authors: []*author.Author{}, MissingIds: invalid}, nil
+	}
+
+	data, err := s.repo.GetByIds(ids)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	found := map[string]*model.Author{}
+	for _, v := range data {
+		found[v.ID] = v
+	}
+
+	authors := []*author.Author{}
+	missing := invalid
+	for _, id := range ids {
+		v, ok := found[id]
+		if !ok {
+			missing = append(missing, id)
+			continue
+		}
+
+		authors = append(authors, &author.Author{
+			Id:        v.ID,
+			Name:      v.Name,
+			CreatedBy: v.CreatedBy,
+			CreatedAt: v.CreatedAt.String(),
+			UpdatedAt: v.UpdatedAt.String(),
+		})
+	}
+
+	return &author.BatchGetAuthorsResponse{
+		Authors:    authors,
+		MissingIds: missing,
+	}, nil
+}
+
+// ResolveAuthors matches names case-insensitively, preferring the oldest author
+// when several share a name, and optionally creates the ones that are missing.
+func (s *AuthorService) ResolveAuthors(ctx context.Context, body *author.ResolveAuthorsRequest) (*author.ResolveAuthorsResponse, error) {
+	names, err := batchNames(body.GetNames())
+	if err != nil {
+		return nil, err
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Internal, "missing outgoing metadata")
+	}
+	outbondCtx := metadata.NewOutgoingContext(ctx, md)
+
+	user, err := s.userService.GetUser(outbondCtx, &emptypb.Empty{})
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid user")
+	}
+
+	if len(names) == 0 {
+		return &author.ResolveAuthorsResponse{Authors: []*author.Author{}, MissingNames: []string{}}, nil
+	}
+
+	data, err := s.repo.GetByNames(names)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	found := map[string]*model.Author{}
+	for _, v := range data {
+		if _, ok := found[strings.ToLower(v.Name)]; !ok {
+			found[strings.ToLower(v.Name)] = v
+		}
+	}
+
+	missing := []string{}
+	created := []*model.Author{}
+	for _, name := range names {
+		if _, ok := found[strings.ToLower(name)]; ok {
+			continue
+		}
+		if !body.GetCreateMissing() {
+			missing = append(missing, name)
+			continue
+		}
+
+		v := &model.Author{
+			ID:        uuid.NewString(),
+			Name:      name,
+			CreatedBy: user.Id,
+		}
+		created = append(created, v)
+		found[strings.ToLower(name)] = v
+	}
+
+	if err := s.repo.CreateBatch(created); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	authors := []*author.Author{}
+	for _, name := range names {
+		v, ok := found[strings.ToLower(name)]
+		if !ok {
+			continue
+		}
+
+		authors = append(authors, &author.Author{
+			Id:        v.ID,
+			Name:      v.Name,
+			CreatedBy: v.CreatedBy,
+			CreatedAt: v.CreatedAt.String(),
+			UpdatedAt: v.UpdatedAt.String(),
+		})
+	}
+
+	return &author.ResolveAuthorsResponse{
+		Authors:      authors,
+		MissingNames: missing,
+	}, nil
+}
+
 func (s *AuthorService) GetAuthors(ctx context.Context, body *author.AuthorRequest) (*author.AuthorsResponse, error) {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
@@ -99,15 +254,15 @@ func (s *AuthorService) GetAuthors(ctx context.Context, body *author.AuthorReque
 		return nil, status.Error(codes.Unauthenticated, "invalid user")
 	}
 
-	data, err := s.repo.Get(body.Search)
+	data, err := s.repo.Get(listOptions(body))
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, listError(err)
 	}
 
 	authors := []*author.Author{}
 
-	if len(data) > 0 {
-		for _, v := range data {
+	if len(data.Authors) > 0 {
+		for _, v := range data.Authors {
 			temp := &author.Author{
 				Id:        v.ID,
 				Name:      v.Name,
@@ -120,7 +275,11 @@ func (s *AuthorService) GetAuthors(ctx context.Context, body *author.AuthorReque
 		}
 	}
 
-	return &author.AuthorsResponse{Authors: authors}, nil
+	return &author.AuthorsResponse{
+		Authors:       authors,
+		NextPageToken: data.NextPageToken,
+		TotalCount:    data.TotalCount,
+	}, nil
 }
 
 func (s *AuthorService) UpdateAuthor(ctx context.Context, body *author.Author) (*author.CommonAuthorResponse, error) {
@@ -179,3 +338,138 @@ func (s *AuthorService) DeleteAuthor(ctx context.Context, body *author.Author) (
 
 	return &author.CommonAuthorResponse{Message: "delete author successfully"}, nil
 }
+
+func (s *AuthorService) ListDeletedAuthors(ctx context.Context, body *author.AuthorRequest) (*author.AuthorsResponse, error) {
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	data, err := s.repo.GetDeleted(listOptions(body))
+	if err != nil {
+		return nil, listError(err)
+	}
+
+	authors := []*author.Author{}
+	for _, v := range data.Authors {
+		authors = append(authors, &author.Author{
+			Id:        v.ID,
+			Name:      v.Name,
+			CreatedBy: v.CreatedBy,
+			CreatedAt: v.CreatedAt.String(),
+			UpdatedAt: v.UpdatedAt.String(),
+			DeletedAt: v.DeletedAt.String(),
+		})
+	}
+
+	return &author.AuthorsResponse{
+		Authors:       authors,
+		NextPageToken: data.NextPageToken,
+		TotalCount:    data.TotalCount,
+	}, nil
+}
+
+func (s *AuthorService) RestoreAuthor(ctx context.Context, body *author.Author) (*author.CommonAuthorResponse, error) {
+	if body.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id cannot be empty")
+	}
+
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.GetDeletedById(body.Id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Error(codes.NotFound, "deleted author not found")
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if err := s.repo.Restore(body.Id); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &author.CommonAuthorResponse{Message: "restore author successfully"}, nil
+}
+
+func (s *AuthorService) requireAdmin(ctx context.Context) error {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return status.Error(codes.Internal, "missing outgoing metadata")
+	}
+	outbondCtx := metadata.NewOutgoingContext(ctx, md)
+
+	user, err := s.userService.GetUser(outbondCtx, &emptypb.Empty{})
+	if err != nil {
+		return status.Error(codes.Unauthenticated, "invalid user")
+	}
+
+	if user.GetRole() != roleAdmin {
+		return status.Error(codes.PermissionDenied, "admin role required")
+	}
+
+	return nil
+}
+
+// batchIds dedupes the requested ids and splits off the ones that are not
+// UUIDs, which cannot match a row and would fail the query on the uuid column.
+func batchIds(ids []string) ([]string, []string, error) {
+	seen := map[string]bool{}
+	result := []string{}
+	invalid := []string{}
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		if _, err := uuid.Parse(id); err != nil {
+			invalid = append(invalid, id)
+			continue
+		}
+		result = append(result, id)
+	}
+
+	if len(result)+len(invalid) > maxBatchSize {
+		return nil, nil, status.Error(codes.InvalidArgument, fmt.Sprintf("at most %d ids can be requested at once", maxBatchSize))
+	}
+
+	return result, invalid, nil
+}
+
+func batchNames(names []string) ([]string, error) {
+	seen := map[string]bool{}
+	result := []string{}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[strings.ToLower(name)] {
+			continue
+		}
+		seen[strings.ToLower(name)] = true
+		result = append(result, name)
+	}
+
+	if len(result) > maxBatchSize {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("at most %d names can be resolved at once", maxBatchSize))
+	}
+
+	return result, nil
+}
+
+func listOptions(body *author.AuthorRequest) pagination.ListOptions {
+	return pagination.ListOptions{
+		Search:       body.GetSearch(),
+		PageSize:     int(body.GetPageSize()),
+		PageToken:    body.GetPageToken(),
+		SortBy:       body.GetSortBy(),
+		SortOrder:    body.GetSortOrder(),
+		IncludeTotal: body.GetIncludeTotal(),
+	}
+}
+
+func listError(err error) error {
+	if errors.Is(err, pagination.ErrInvalidPageToken) || errors.Is(err, pagination.ErrInvalidSort) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return status.Error(codes.Internal, err.Error())
+}
@@ -0,0 +1,110 @@
+// Command export-books downloads books from BookService.ExportBooks as CSV,
+// MARC21 or MARCXML.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gitlab.com/shafaalafghany/synapsis-proto/go/book"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:6000", "book-service address")
+	token := flag.String("token", os.Getenv("BOOK_TOKEN"), "JWT, defaults to $BOOK_TOKEN")
+	out := flag.String("out", "", "file to write, standard output by default")
+	format := flag.String("format", "", "csv, marc21 or marcxml, guessed from the -out extension by default")
+	ids := flag.String("ids", "", "comma-separated book ids, the whole catalog by default")
+	category := flag.String("category", "", "only export books in this category id")
+	timeout := flag.Duration("timeout", 10*time.Minute, "time limit for the whole export")
+	flag.Parse()
+
+	if *token == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	catalogFormat, err := parseFormat(*format, *out)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	req := &book.ExportBooksRequest{Format: catalogFormat}
+	if *ids != "" {
+		req.Ids = strings.Split(*ids, ",")
+	}
+	if *category != "" {
+		req.Filter = &book.BookFilter{CategoryIds: []string{*category}}
+	}
+
+	conn, err := grpc.NewClient(*addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatalf("failed to connect to book-service: %v", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+*token)
+
+	stream, err := book.NewBookServiceClient(conn).ExportBooks(ctx, req)
+	if err != nil {
+		log.Fatalf("failed to start export: %v", err)
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			log.Fatalf("failed to create %s: %v", *out, err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			log.Fatalf("export failed: %v", err)
+		}
+		if _, err := w.Write(chunk.GetData()); err != nil {
+			log.Fatalf("failed to write export: %v", err)
+		}
+	}
+}
+
+func parseFormat(format, file string) (book.CatalogFormat, error) {
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(file)) {
+		case ".mrc", ".marc":
+			format = "marc21"
+		case ".xml":
+			format = "marcxml"
+		default:
+			format = "csv"
+		}
+	}
+
+	switch strings.ToLower(format) {
+	case "csv":
+		return book.CatalogFormat_FORMAT_CSV, nil
+	case "marc21":
+		return book.CatalogFormat_FORMAT_MARC21, nil
+	case "marcxml":
+		return book.CatalogFormat_FORMAT_MARCXML, nil
+	}
+	return 0, fmt.Errorf("unknown format %q, expected csv, marc21 or marcxml", format)
+}
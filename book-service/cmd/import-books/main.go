@@ -0,0 +1,142 @@
+// Command import-books streams a CSV, MARC21 or MARCXML file to
+// BookService.ImportBooks and prints the per-row report. Without -commit the
+// import is a dry run.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gitlab.com/shafaalafghany/synapsis-proto/go/book"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+const chunkSize = 64 << 10
+
+func main() {
+	addr := flag.String("addr", "localhost:6000", "book-service address")
+	token := flag.String("token", os.Getenv("BOOK_TOKEN"), "JWT of a librarian or admin, defaults to $BOOK_TOKEN")
+	file := flag.String("file", "", "file to import")
+	format := flag.String("format", "", "csv, marc21 or marcxml, guessed from the file extension by default")
+	commit := flag.Bool("commit", false, "create the books instead of only validating them")
+	verbose := flag.Bool("v", false, "print every row, not only failed ones")
+	timeout := flag.Duration("timeout", 5*time.Minute, "time limit for the whole import")
+	flag.Parse()
+
+	if *file == "" || *token == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	catalogFormat, err := parseFormat(*format, *file)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		log.Fatalf("failed to open %s: %v", *file, err)
+	}
+	defer f.Close()
+
+	conn, err := grpc.NewClient(*addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatalf("failed to connect to book-service: %v", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+*token)
+
+	stream, err := book.NewBookServiceClient(conn).ImportBooks(ctx)
+	if err != nil {
+		log.Fatalf("failed to start import: %v", err)
+	}
+
+	buf := make([]byte, chunkSize)
+	first := true
+	for {
+		n, err := f.Read(buf)
+		if n > 0 || first {
+			if err := stream.Send(&book.ImportBooksRequest{Commit: first && *commit, Format: catalogFormat, Data: buf[:n]}); err != nil {
+				log.Fatalf("failed to send %s: %v", *file, err)
+			}
+			first = false
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			log.Fatalf("failed to read %s: %v", *file, err)
+		}
+	}
+
+	res, err := stream.CloseAndRecv()
+	if err != nil {
+		log.Fatalf("import failed: %v", err)
+	}
+
+	for _, row := range res.GetRows() {
+		if row.GetStatus() == "failed" {
+			fmt.Printf("row %d %q: %s\n", row.GetRow(), row.GetName(), strings.Join(row.GetErrors(), "; "))
+		} else if *verbose {
+			fmt.Printf("row %d %q: %s %s\n", row.GetRow(), row.GetName(), row.GetStatus(), row.GetBookId())
+		}
+	}
+
+	if len(res.GetCreatedAuthors()) > 0 {
+		fmt.Printf("new authors: %s\n", strings.Join(res.GetCreatedAuthors(), ", "))
+	}
+	if len(res.GetCreatedCategories()) > 0 {
+		fmt.Printf("new categories: %s\n", strings.Join(res.GetCreatedCategories(), ", "))
+	}
+	if len(res.GetUnusedAuthors()) > 0 {
+		fmt.Printf("authors created but not used: %s\n", strings.Join(res.GetUnusedAuthors(), ", "))
+	}
+	if len(res.GetUnusedCategories()) > 0 {
+		fmt.Printf("categories created but not used: %s\n", strings.Join(res.GetUnusedCategories(), ", "))
+	}
+
+	mode := "dry run"
+	if res.GetCommitted() {
+		mode = "committed"
+	}
+	fmt.Printf("%s: %d rows, %d valid, %d failed, %d books created\n", mode, res.GetTotalRows(), res.GetValidRows(), res.GetFailedRows(), res.GetCreatedBooks())
+
+	if res.GetFailedRows() > 0 {
+		os.Exit(1)
+	}
+}
+
+func parseFormat(format, file string) (book.CatalogFormat, error) {
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(file)) {
+		case ".mrc", ".marc":
+			format = "marc21"
+		case ".xml":
+			format = "marcxml"
+		default:
+			format = "csv"
+		}
+	}
+
+	switch strings.ToLower(format) {
+	case "csv":
+		return book.CatalogFormat_FORMAT_CSV, nil
+	case "marc21":
+		return book.CatalogFormat_FORMAT_MARC21, nil
+	case "marcxml":
+		return book.CatalogFormat_FORMAT_MARCXML, nil
+	}
+	return 0, fmt.Errorf("unknown format %q, expected csv, marc21 or marcxml", format)
+}
@@ -0,0 +1,150 @@
+package enrichment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const DefaultOpenLibraryURL = "https://openlibrary.org"
+
+// OpenLibraryProvider queries the Open Library Books API
+// (/api/books?jscmd=data), which returns author and subject names inline.
+type OpenLibraryProvider struct {
+	baseURL string
+	client  *http.Client
+}
+
+func NewOpenLibraryProvider(baseURL string) Provider {
+	if baseURL == "" {
+		baseURL = DefaultOpenLibraryURL
+	}
+
+	return &OpenLibraryProvider{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (p *OpenLibraryProvider) Name() string {
+	return "openlibrary"
+}
+
+func (p *OpenLibraryProvider) Lookup(ctx context.Context, isbn string) (*Metadata, error) {
+	query := url.Values{
+		"bibkeys": {"ISBN:" + isbn},
+		"format":  {"json"},
+		"jscmd":   {"data"},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"/api/books?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "book-service (library catalog enrichment)")
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("open library returned %s", res.Status)
+	}
+
+	return decodeOpenLibrary(io.LimitReader(res.Body, 1<<20), isbn)
+}
+
+type openLibraryName struct {
+	Name string `json:"name"`
+}
+
+type openLibraryBook struct {
+	Title         string            `json:"title"`
+	Subtitle      string            `json:"subtitle"`
+	Authors       []openLibraryName `json:"authors"`
+	Publishers    []openLibraryName `json:"publishers"`
+	Subjects      []openLibraryName `json:"subjects"`
+	PublishDate   string            `json:"publish_date"`
+	NumberOfPages int               `json:"number_of_pages"`
+	Notes         json.RawMessage   `json:"notes"`
+	Cover         struct {
+		Large string `json:"large"`
+	} `json:"cover"`
+}
+
+func decodeOpenLibrary(r io.Reader, isbn string) (*Metadata, error) {
+	var body map[string]openLibraryBook
+	if err := json.NewDecoder(r).Decode(&body); err != nil {
+		return nil, fmt.Errorf("invalid open library response: %w", err)
+	}
+
+	data, ok := body["ISBN:"+isbn]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	res := &Metadata{
+		ISBN:          isbn,
+		Title:         strings.TrimSpace(data.Title),
+		PublishedYear: parseYear(data.PublishDate),
+		PageCount:     data.NumberOfPages,
+		Description:   openLibraryText(data.Notes),
+		CoverURL:      data.Cover.Large,
+	}
+	if data.Subtitle != "" {
+		res.Title += ": " + strings.TrimSpace(data.Subtitle)
+	}
+	if len(data.Publishers) > 0 {
+		res.Publisher = strings.TrimSpace(data.Publishers[0].Name)
+	}
+	res.Authors = names(data.Authors)
+	res.Subjects = names(data.Subjects)
+
+	return res, nil
+}
+
+// openLibraryText reads text fields, which are either a plain string or a
+// {"type": "/type/text", "value": ...} object.
+func openLibraryText(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return strings.TrimSpace(text)
+	}
+
+	var typed struct {
+		Value string `json:"value"`
+	}
+	if err := json.Unmarshal(raw, &typed); err == nil {
+		return strings.TrimSpace(typed.Value)
+	}
+
+	return ""
+}
+
+func names(values []openLibraryName) []string {
+	seen := map[string]bool{}
+	result := []string{}
+	for _, v := range values {
+		name := strings.TrimSpace(v.Name)
+		key := strings.ToLower(name)
+		if name == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, name)
+	}
+
+	return result
+}
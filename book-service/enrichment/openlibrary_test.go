@@ -0,0 +1,93 @@
+package enrichment
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecodeOpenLibrary(t *testing.T) {
+	tests := []struct {
+		name    string
+		fixture string
+		isbn    string
+		want    *Metadata
+	}{
+		{
+			name:    "subtitle and typed notes",
+			fixture: "fixtures/9780261103344.json",
+			isbn:    "9780261103344",
+			want: &Metadata{
+				ISBN:          "9780261103344",
+				Title:         "The Hobbit: or There and Back Again",
+				Authors:       []string{"J.R.R. Tolkien"},
+				Subjects:      []string{"Fantasy fiction", "Fiction", "Middle Earth (Imaginary place)"},
+				Publisher:     "HarperCollins",
+				PublishedYear: 1999,
+				PageCount:     310,
+				Description:   "Bilbo Baggins is swept into a quest to reclaim the dwarves' treasure from the dragon Smaug.",
+				CoverURL:      "https://covers.openlibrary.org/b/id/6979861-L.jpg",
+			},
+		},
+		{
+			name:    "plain notes and a full publish date",
+			fixture: "fixtures/9780141439518.json",
+			isbn:    "9780141439518",
+			want: &Metadata{
+				ISBN:          "9780141439518",
+				Title:         "Pride and Prejudice",
+				Authors:       []string{"Jane Austen"},
+				Subjects:      []string{"Fiction", "Courtship", "Romance"},
+				Publisher:     "Penguin Classics",
+				PublishedYear: 2003,
+				PageCount:     480,
+				Description:   "Edited with an introduction and notes by Vivien Jones.",
+				CoverURL:      "https://covers.openlibrary.org/b/id/8091016-L.jpg",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.Open(tt.fixture)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			got, err := decodeOpenLibrary(f, tt.isbn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got  %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeOpenLibraryErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		notFound bool
+	}{
+		{name: "unknown isbn", body: `{}`, notFound: true},
+		{name: "other isbn", body: `{"ISBN:9780141439518": {"title": "Pride and Prejudice"}}`, notFound: true},
+		{name: "invalid json", body: `{"ISBN:9780261103344":`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodeOpenLibrary(strings.NewReader(tt.body), "9780261103344")
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if got := errors.Is(err, ErrNotFound); got != tt.notFound {
+				t.Errorf("errors.Is(err, ErrNotFound) = %v, want %v (err: %v)", got, tt.notFound, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package enrichment
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strconv"
+)
+
+var ErrNotFound = errors.New("isbn not found")
+
+// Metadata is what a provider knows about one edition. Authors and subjects
+// are plain names; matching them to catalog records is up to the caller.
+type Metadata struct {
+	ISBN          string
+	Title         string
+	Authors       []string
+	Subjects      []string
+	Publisher     string
+	PublishedYear int
+	PageCount     int
+	Language      string
+	Description   string
+	CoverURL      string
+}
+
+// Provider looks up bibliographic metadata by ISBN-13. Implementations return
+// ErrNotFound when the ISBN is unknown and must be safe for concurrent use.
+type Provider interface {
+	Name() string
+	Lookup(ctx context.Context, isbn string) (*Metadata, error)
+}
+
+var yearPattern = regexp.MustCompile(`\b(1[5-9]|20)\d{2}\b`)
+
+func parseYear(date string) int {
+	year, _ := strconv.Atoi(yearPattern.FindString(date))
+	return year
+}
@@ -0,0 +1,36 @@
+package enrichment
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// StubProvider answers lookups from recorded Open Library responses stored
+// as <isbn>.json in a directory, so development and tests need no network.
+type StubProvider struct {
+	dir string
+}
+
+func NewStubProvider(dir string) Provider {
+	return &StubProvider{dir: dir}
+}
+
+func (p *StubProvider) Name() string {
+	return "stub"
+}
+
+func (p *StubProvider) Lookup(ctx context.Context, isbn string) (*Metadata, error) {
+	f, err := os.Open(filepath.Join(p.dir, filepath.Base(isbn)+".json"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return decodeOpenLibrary(f, isbn)
+}
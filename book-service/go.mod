@@ -30,8 +30,13 @@ require (
 	github.com/google/uuid v1.6.0
 	github.com/jinzhu/inflection v1.0.0 // indirect
 	github.com/jinzhu/now v1.1.5 // indirect
+	gitlab.com/shafaalafghany/synapsis-common v0.0.0-00010101000000-000000000000
 	go.uber.org/zap v1.27.0
 	golang.org/x/text v0.18.0 // indirect
 	google.golang.org/grpc v1.68.1
 	gorm.io/driver/postgres v1.5.11
 )
+
+replace gitlab.com/shafaalafghany/synapsis-proto => ../proto
+
+replace gitlab.com/shafaalafghany/synapsis-common => ../common
@@ -18,12 +18,26 @@ import (
 type BookHandler struct {
 	book.UnimplementedBookServiceServer
 	s   service.BookServiceInterface
+	r   service.ReviewServiceInterface
+	l   service.ReadingListServiceInterface
+	w   service.WorkServiceInterface
+	c   service.CoverServiceInterface
+	e   service.EnrichmentServiceInterface
+	b   service.BranchServiceInterface
+	t   service.TransferServiceInterface
 	log *zap.Logger
 }
 
-func NewBookHandler(s service.BookServiceInterface, log *zap.Logger) *BookHandler {
+func NewBookHandler(s service.BookServiceInterface, r service.ReviewServiceInterface, l service.ReadingListServiceInterface, w service.WorkServiceInterface, c service.CoverServiceInterface, e service.EnrichmentServiceInterface, b service.BranchServiceInterface, t service.TransferServiceInterface, log *zap.Logger) *BookHandler {
 	return &BookHandler{
 		s:   s,
+		r:   r,
+		l:   l,
+		w:   w,
+		c:   c,
+		e:   e,
+		b:   b,
+		t:   t,
 		log: log,
 	}
 }
@@ -43,10 +57,26 @@ func (h *BookHandler) Get(ctx context.Context, body *book.Book) (*book.Book, err
 	return h.s.GetBook(ctx, body)
 }
 
+func (h *BookHandler) GetBookByISBN(ctx context.Context, body *book.Book) (*book.Book, error) {
+	return h.s.GetBookByISBN(ctx, body)
+}
+
 func (h *BookHandler) Getlist(ctx context.Context, body *book.BookRequest) (*book.BooksResponse, error) {
 	return h.s.GetBooks(ctx, body)
 }
 
+func (h *BookHandler) BatchGet(ctx context.Context, body *book.BatchGetBooksRequest) (*book.BatchGetBooksResponse, error) {
+	return h.s.BatchGetBooks(ctx, body)
+}
+
+func (h *BookHandler) ReferencedIds(ctx context.Context, body *book.ReferencedIdsRequest) (*book.ReferencedIdsResponse, error) {
+	if err := requireService(ctx); err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.s.ReferencedIds(ctx, body)
+}
+
 func (h *BookHandler) Update(ctx context.Context, body *book.Book) (*book.CommonBookResponse, error) {
 	_, err := getUserIDFromContext(ctx)
 	if err != nil {
@@ -65,6 +95,24 @@ func (h *BookHandler) Delete(ctx context.Context, body *book.Book) (*book.Common
 	return h.s.DeleteBook(ctx, body)
 }
 
+func (h *BookHandler) ListDeleted(ctx context.Context, body *book.BookRequest) (*book.BooksResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.s.ListDeletedBooks(ctx, body)
+}
+
+func (h *BookHandler) Restore(ctx context.Context, body *book.Book) (*book.CommonBookResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.s.RestoreBook(ctx, body)
+}
+
 func (h *BookHandler) BorrowBook(ctx context.Context, body *book.BorrowRecord) (*book.CommonBorrowRecordResponse, error) {
 	_, err := getUserIDFromContext(ctx)
 	if err != nil {
@@ -83,6 +131,15 @@ func (h *BookHandler) ReturnBook(ctx context.Context, body *book.BorrowRecord) (
 	return h.s.ReturnBook(ctx, body)
 }
 
+func (h *BookHandler) SearchBooks(ctx context.Context, body *book.SearchBooksRequest) (*book.SearchBooksResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.s.SearchBooks(ctx, body)
+}
+
 func (h *BookHandler) GetRecommendation(ctx context.Context, body *book.BookRequest) (*book.BooksResponse, error) {
 	_, err := getUserIDFromContext(ctx)
 	if err != nil {
@@ -92,15 +149,494 @@ func (h *BookHandler) GetRecommendation(ctx context.Context, body *book.BookRequ
 	return h.s.GetRecommendation(ctx, body)
 }
 
+func (h *BookHandler) GetTrending(ctx context.Context, body *book.TrendingRequest) (*book.TrendingResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.s.GetTrending(ctx, body)
+}
+
+func (h *BookHandler) GetSimilarBooks(ctx context.Context, body *book.SimilarBooksRequest) (*book.SimilarBooksResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.s.GetSimilarBooks(ctx, body)
+}
+
+func (h *BookHandler) GetDigitalAccess(ctx context.Context, body *book.DigitalAccessRequest) (*book.DigitalAccess, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.s.GetDigitalAccess(ctx, body)
+}
+
+func (h *BookHandler) AddLicense(ctx context.Context, body *book.DigitalLicense) (*book.CommonBookResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.s.AddLicense(ctx, body)
+}
+
+func (h *BookHandler) ListLicenses(ctx context.Context, body *book.Book) (*book.LicensesResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.s.ListLicenses(ctx, body)
+}
+
+func (h *BookHandler) RevokeLicense(ctx context.Context, body *book.DigitalLicense) (*book.CommonBookResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.s.RevokeLicense(ctx, body)
+}
+
+func (h *BookHandler) CreateReview(ctx context.Context, body *book.Review) (*book.CommonReviewResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.r.CreateReview(ctx, body)
+}
+
+func (h *BookHandler) UpdateReview(ctx context.Context, body *book.Review) (*book.CommonReviewResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.r.UpdateReview(ctx, body)
+}
+
+func (h *BookHandler) DeleteReview(ctx context.Context, body *book.Review) (*book.CommonReviewResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.r.DeleteReview(ctx, body)
+}
+
+func (h *BookHandler) ListReviews(ctx context.Context, body *book.ReviewRequest) (*book.ReviewsResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.r.ListReviews(ctx, body)
+}
+
+func (h *BookHandler) ModerateReview(ctx context.Context, body *book.ModerateReviewRequest) (*book.CommonReviewResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.r.ModerateReview(ctx, body)
+}
+
+func (h *BookHandler) CreateList(ctx context.Context, body *book.ReadingList) (*book.CommonListResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.l.CreateList(ctx, body)
+}
+
+func (h *BookHandler) UpdateList(ctx context.Context, body *book.ReadingList) (*book.CommonListResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.l.UpdateList(ctx, body)
+}
+
+func (h *BookHandler) DeleteList(ctx context.Context, body *book.ReadingList) (*book.CommonListResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.l.DeleteList(ctx, body)
+}
+
+func (h *BookHandler) GetList(ctx context.Context, body *book.ReadingList) (*book.ReadingList, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.l.GetList(ctx, body)
+}
+
+func (h *BookHandler) ListMyLists(ctx context.Context, body *book.ReadingListRequest) (*book.ReadingListsResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.l.ListMyLists(ctx, body)
+}
+
+func (h *BookHandler) AddToList(ctx context.Context, body *book.ReadingListItem) (*book.CommonListResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.l.AddToList(ctx, body)
+}
+
+func (h *BookHandler) RemoveFromList(ctx context.Context, body *book.ReadingListItem) (*book.CommonListResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.l.RemoveFromList(ctx, body)
+}
+
+func (h *BookHandler) ReorderList(ctx context.Context, body *book.ReorderListRequest) (*book.CommonListResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.l.ReorderList(ctx, body)
+}
+
+func (h *BookHandler) ListNotifications(ctx context.Context, body *book.NotificationRequest) (*book.NotificationsResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.l.ListNotifications(ctx, body)
+}
+
+func (h *BookHandler) CreateWork(ctx context.Context, body *book.Work) (*book.CommonWorkResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.w.CreateWork(ctx, body)
+}
+
+func (h *BookHandler) UpdateWork(ctx context.Context, body *book.Work) (*book.CommonWorkResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.w.UpdateWork(ctx, body)
+}
+
+func (h *BookHandler) GetWork(ctx context.Context, body *book.Work) (*book.Work, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.w.GetWork(ctx, body)
+}
+
+func (h *BookHandler) ListEditions(ctx context.Context, body *book.EditionsRequest) (*book.BooksResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.w.ListEditions(ctx, body)
+}
+
+func (h *BookHandler) CreateSeries(ctx context.Context, body *book.Series) (*book.CommonWorkResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.w.CreateSeries(ctx, body)
+}
+
+func (h *BookHandler) UpdateSeries(ctx context.Context, body *book.Series) (*book.CommonWorkResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.w.UpdateSeries(ctx, body)
+}
+
+func (h *BookHandler) GetSeries(ctx context.Context, body *book.Series) (*book.Series, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.w.GetSeries(ctx, body)
+}
+
+func (h *BookHandler) ListSeriesBooks(ctx context.Context, body *book.SeriesBooksRequest) (*book.BooksResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.w.ListSeriesBooks(ctx, body)
+}
+
+func (h *BookHandler) ImportBooks(stream book.BookService_ImportBooksServer) error {
+	_, err := getUserIDFromContext(stream.Context())
+	if err != nil {
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.s.ImportBooks(stream)
+}
+
+func (h *BookHandler) ExportBooks(body *book.ExportBooksRequest, stream book.BookService_ExportBooksServer) error {
+	_, err := getUserIDFromContext(stream.Context())
+	if err != nil {
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.s.ExportBooks(body, stream)
+}
+
+func (h *BookHandler) UploadCover(stream book.BookService_UploadCoverServer) error {
+	_, err := getUserIDFromContext(stream.Context())
+	if err != nil {
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.c.UploadCover(stream)
+}
+
+func (h *BookHandler) DownloadCover(body *book.CoverRequest, stream book.BookService_DownloadCoverServer) error {
+	_, err := getUserIDFromContext(stream.Context())
+	if err != nil {
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.c.DownloadCover(body, stream)
+}
+
+func (h *BookHandler) CreateBranch(ctx context.Context, body *book.Branch) (*book.CommonBranchResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.b.CreateBranch(ctx, body)
+}
+
+func (h *BookHandler) UpdateBranch(ctx context.Context, body *book.Branch) (*book.CommonBranchResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.b.UpdateBranch(ctx, body)
+}
+
+func (h *BookHandler) GetBranch(ctx context.Context, body *book.Branch) (*book.Branch, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.b.GetBranch(ctx, body)
+}
+
+func (h *BookHandler) ListBranches(ctx context.Context, body *book.BranchRequest) (*book.BranchesResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.b.ListBranches(ctx, body)
+}
+
+func (h *BookHandler) DeleteBranch(ctx context.Context, body *book.Branch) (*book.CommonBranchResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.b.DeleteBranch(ctx, body)
+}
+
+func (h *BookHandler) PlaceHold(ctx context.Context, body *book.Hold) (*book.CommonHoldResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.t.PlaceHold(ctx, body)
+}
+
+func (h *BookHandler) CancelHold(ctx context.Context, body *book.Hold) (*book.CommonHoldResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.t.CancelHold(ctx, body)
+}
+
+func (h *BookHandler) ListHolds(ctx context.Context, body *book.HoldRequest) (*book.HoldsResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.t.ListHolds(ctx, body)
+}
+
+func (h *BookHandler) RequestTransfer(ctx context.Context, body *book.Transfer) (*book.CommonTransferResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.t.RequestTransfer(ctx, body)
+}
+
+func (h *BookHandler) DispatchTransfer(ctx context.Context, body *book.Transfer) (*book.CommonTransferResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.t.DispatchTransfer(ctx, body)
+}
+
+func (h *BookHandler) ReceiveTransfer(ctx context.Context, body *book.Transfer) (*book.CommonTransferResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.t.ReceiveTransfer(ctx, body)
+}
+
+func (h *BookHandler) CancelTransfer(ctx context.Context, body *book.Transfer) (*book.CommonTransferResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.t.CancelTransfer(ctx, body)
+}
+
+func (h *BookHandler) ListTransfers(ctx context.Context, body *book.TransferRequest) (*book.TransfersResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.t.ListTransfers(ctx, body)
+}
+
+func (h *BookHandler) LookupISBN(ctx context.Context, body *book.LookupISBNRequest) (*book.ISBNLookupResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.e.LookupISBN(ctx, body)
+}
+
+func (h *BookHandler) CreateDraft(ctx context.Context, body *book.CreateDraftRequest) (*book.BookDraft, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.e.CreateDraft(ctx, body)
+}
+
+func (h *BookHandler) ListDrafts(ctx context.Context, body *book.BookRequest) (*book.BookDraftsResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.e.ListDrafts(ctx, body)
+}
+
+func (h *BookHandler) PublishDraft(ctx context.Context, body *book.PublishDraftRequest) (*book.CommonBookResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.e.PublishDraft(ctx, body)
+}
+
+func (h *BookHandler) DeleteDraft(ctx context.Context, body *book.BookDraft) (*book.CommonBookResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.e.DeleteDraft(ctx, body)
+}
+
 func getUserIDFromContext(ctx context.Context) (string, error) {
+	claims, err := getClaimsFromContext(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	userID, ok := claims["id"].(string)
+	if !ok {
+		return "", errors.New("invalid user ID in token claims")
+	}
+
+	return userID, nil
+}
+
+// requireService accepts only tokens minted by another service for calls
+// made outside of a user request.
+func requireService(ctx context.Context) error {
+	claims, err := getClaimsFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if service, _ := claims["service"].(bool); !service {
+		return errors.New("only services can call this method")
+	}
+
+	return nil
+}
+
+func getClaimsFromContext(ctx context.Context) (jwt.MapClaims, error) {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
-		return "", errors.New("missing metadata")
+		return nil, errors.New("missing metadata")
 	}
 
 	authHeader, ok := md["authorization"]
 	if !ok || len(authHeader) == 0 {
-		return "", errors.New("missing authorization header")
+		return nil, errors.New("missing authorization header")
 	}
 
 	tokenStr := strings.TrimPrefix(authHeader[0], "Bearer ")
@@ -112,18 +648,13 @@ func getUserIDFromContext(ctx context.Context) (string, error) {
 	})
 
 	if err != nil || !token.Valid {
-		return "", errors.New("invalid token")
+		return nil, errors.New("invalid token")
 	}
 
 	claims, ok := token.Claims.(jwt.MapClaims)
 	if !ok || !token.Valid {
-		return "", errors.New("invalid token claims")
+		return nil, errors.New("invalid token claims")
 	}
 
-	userID, ok := claims["id"].(string)
-	if !ok {
-		return "", errors.New("invalid user ID in token claims")
-	}
-
-	return userID, nil
+	return claims, nil
 }
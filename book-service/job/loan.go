@@ -0,0 +1,52 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"github.com/shafaalafghany/book-service/repository"
+	"go.uber.org/zap"
+)
+
+// LoanJob returns digital loans once their loan period has ended, freeing
+// their license slots.
+type LoanJob struct {
+	repo     repository.BookRepositoryInterface
+	log      *zap.Logger
+	interval time.Duration
+}
+
+func NewLoanJob(repo repository.BookRepositoryInterface, log *zap.Logger, interval time.Duration) *LoanJob {
+	return &LoanJob{
+		repo:     repo,
+		log:      log,
+		interval: interval,
+	}
+}
+
+func (j *LoanJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.expire(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *LoanJob) expire(ctx context.Context) {
+	count, err := j.repo.ExpireLoans(ctx, time.Now())
+	if err != nil {
+		j.log.Error("failed to return expired digital loans", zap.Error(err))
+		return
+	}
+
+	if count > 0 {
+		j.log.Info("returned expired digital loans", zap.Int64("count", count))
+	}
+}
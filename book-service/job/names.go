@@ -0,0 +1,130 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"github.com/shafaalafghany/book-service/repository"
+	"gitlab.com/shafaalafghany/synapsis-common/auth"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/author"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/category"
+	"go.uber.org/zap"
+)
+
+// nameBatch stays within the ids author-service and category-service accept
+// in one BatchGet.
+const nameBatch = 100
+
+// NameJob copies author and category names from their services onto books.
+// The first run backfills books stored before the names were kept locally;
+// later runs pick up renames.
+type NameJob struct {
+	repo       repository.BookRepositoryInterface
+	authors    author.AuthorServiceClient
+	categories category.CategoryServiceClient
+	secret     string
+	log        *zap.Logger
+	interval   time.Duration
+}
+
+func NewNameJob(repo repository.BookRepositoryInterface, authors author.AuthorServiceClient, categories category.CategoryServiceClient, secret string, log *zap.Logger, interval time.Duration) *NameJob {
+	return &NameJob{
+		repo:       repo,
+		authors:    authors,
+		categories: categories,
+		secret:     secret,
+		log:        log,
+		interval:   interval,
+	}
+}
+
+func (j *NameJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.refresh(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *NameJob) refresh(ctx context.Context) {
+	authors, err := j.refreshAuthors(ctx)
+	if err != nil {
+		j.log.Error("failed to refresh author names", zap.Error(err))
+	}
+
+	categories, err := j.refreshCategories(ctx)
+	if err != nil {
+		j.log.Error("failed to refresh category names", zap.Error(err))
+	}
+
+	j.log.Info("refreshed author and category names", zap.Int64("author_books", authors), zap.Int64("category_books", categories))
+}
+
+func (j *NameJob) refreshAuthors(ctx context.Context) (int64, error) {
+	var total int64
+	after := ""
+	for {
+		ids, err := j.repo.AuthorIdsAfter(ctx, after, nameBatch)
+		if err != nil || len(ids) == 0 {
+			return total, err
+		}
+		after = ids[len(ids)-1]
+
+		outbondCtx, err := auth.ServiceContext(ctx, j.secret, "book-service")
+		if err != nil {
+			return total, err
+		}
+		res, err := j.authors.BatchGet(outbondCtx, &author.BatchGetAuthorsRequest{Ids: ids})
+		if err != nil {
+			return total, err
+		}
+
+		names := map[string]string{}
+		for _, v := range res.GetAuthors() {
+			names[v.GetId()] = v.GetName()
+		}
+		count, err := j.repo.RefreshAuthorNames(ctx, names)
+		if err != nil {
+			return total, err
+		}
+		total += count
+	}
+}
+
+func (j *NameJob) refreshCategories(ctx context.Context) (int64, error) {
+	var total int64
+	after := ""
+	for {
+		ids, err := j.repo.CategoryIdsAfter(ctx, after, nameBatch)
+		if err != nil || len(ids) == 0 {
+			return total, err
+		}
+		after = ids[len(ids)-1]
+
+		outbondCtx, err := auth.ServiceContext(ctx, j.secret, "book-service")
+		if err != nil {
+			return total, err
+		}
+		res, err := j.categories.BatchGet(outbondCtx, &category.BatchGetCategoriesRequest{Ids: ids})
+		if err != nil {
+			return total, err
+		}
+
+		names := map[string]string{}
+		for _, v := range res.GetCategories() {
+			names[v.GetId()] = v.GetName()
+		}
+		count, err := j.repo.RefreshCategoryNames(ctx, names)
+		if err != nil {
+			return total, err
+		}
+		total += count
+	}
+}
@@ -0,0 +1,48 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"github.com/shafaalafghany/book-service/repository"
+	"go.uber.org/zap"
+)
+
+type SimilarityJob struct {
+	repo     repository.BookRepositoryInterface
+	log      *zap.Logger
+	interval time.Duration
+}
+
+func NewSimilarityJob(repo repository.BookRepositoryInterface, log *zap.Logger, interval time.Duration) *SimilarityJob {
+	return &SimilarityJob{
+		repo:     repo,
+		log:      log,
+		interval: interval,
+	}
+}
+
+func (j *SimilarityJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.rebuild()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *SimilarityJob) rebuild() {
+	count, err := j.repo.RebuildSimilarities()
+	if err != nil {
+		j.log.Error("failed to rebuild book similarities", zap.Error(err))
+		return
+	}
+
+	j.log.Info("rebuilt book similarities", zap.Int64("pairs", count))
+}
@@ -0,0 +1,48 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"github.com/shafaalafghany/book-service/repository"
+	"go.uber.org/zap"
+)
+
+type TrendingJob struct {
+	repo     repository.BookRepositoryInterface
+	log      *zap.Logger
+	interval time.Duration
+}
+
+func NewTrendingJob(repo repository.BookRepositoryInterface, log *zap.Logger, interval time.Duration) *TrendingJob {
+	return &TrendingJob{
+		repo:     repo,
+		log:      log,
+		interval: interval,
+	}
+}
+
+func (j *TrendingJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.rebuild(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *TrendingJob) rebuild(ctx context.Context) {
+	count, err := j.repo.RebuildTrending(ctx)
+	if err != nil {
+		j.log.Error("failed to rebuild trending books", zap.Error(err))
+		return
+	}
+
+	j.log.Info("rebuilt trending books", zap.Int64("entries", count))
+}
@@ -1,23 +1,33 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net"
+	"net/http"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/go-redis/redis/v8"
 	"github.com/joho/godotenv"
+	"github.com/shafaalafghany/book-service/enrichment"
 	"github.com/shafaalafghany/book-service/handler"
+	"github.com/shafaalafghany/book-service/job"
 	"github.com/shafaalafghany/book-service/middleware"
 	"github.com/shafaalafghany/book-service/model"
+	"github.com/shafaalafghany/book-service/opds"
 	"github.com/shafaalafghany/book-service/repository"
 	"github.com/shafaalafghany/book-service/service"
+	"github.com/shafaalafghany/book-service/storage"
 	"gitlab.com/shafaalafghany/synapsis-proto/go/author"
 	"gitlab.com/shafaalafghany/synapsis-proto/go/book"
 	"gitlab.com/shafaalafghany/synapsis-proto/go/category"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/publisher"
 	"gitlab.com/shafaalafghany/synapsis-proto/go/user"
 
+	"gitlab.com/shafaalafghany/synapsis-common/purge"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"google.golang.org/grpc"
@@ -28,36 +38,72 @@ import (
 )
 
 type Config struct {
-	DBHost          string
-	DBUser          string
-	DBPassword      string
-	DBPort          string
-	DBName          string
-	JwtSecret       string
-	AppPort         string
-	RedisHost       string
-	RedisPort       string
-	UserService     string
-	AuthorService   string
-	CategoryService string
+	DBHost           string
+	DBUser           string
+	DBPassword       string
+	DBPort           string
+	DBName           string
+	JwtSecret        string
+	AppPort          string
+	RedisHost        string
+	RedisPort        string
+	UserService      string
+	AuthorService    string
+	CategoryService  string
+	PublisherService string
+
+	PurgeRetention string
+	PurgeInterval  string
+
+	RecommendationInterval string
+	TrendingInterval       string
+	LoanReturnInterval     string
+	NameRefreshInterval    string
+
+	CoverStorageDir string
+	CoverMaxBytes   string
+
+	OpdsPort string
+
+	EnrichmentProvider string
+	EnrichmentURL      string
+	EnrichmentFixtures string
 }
 
 func main() {
 	_ = godotenv.Load()
 
 	config := Config{
-		JwtSecret:       os.Getenv("SECRET_KEY"),
-		AppPort:         os.Getenv("APP_PORT"),
-		DBHost:          os.Getenv("DB_HOST"),
-		DBPort:          os.Getenv("DB_PORT"),
-		DBUser:          os.Getenv("DB_USER"),
-		DBPassword:      os.Getenv("DB_PASS"),
-		DBName:          os.Getenv("DB_NAME"),
-		UserService:     os.Getenv("USER_SERVICE"),
-		AuthorService:   os.Getenv("AUTHOR_SERVICE"),
-		CategoryService: os.Getenv("CATEGORY_SERVICE"),
-		RedisHost:       os.Getenv("REDIS_HOST"),
-		RedisPort:       os.Getenv("REDIS_PORT"),
+		JwtSecret:        os.Getenv("SECRET_KEY"),
+		AppPort:          os.Getenv("APP_PORT"),
+		DBHost:           os.Getenv("DB_HOST"),
+		DBPort:           os.Getenv("DB_PORT"),
+		DBUser:           os.Getenv("DB_USER"),
+		DBPassword:       os.Getenv("DB_PASS"),
+		DBName:           os.Getenv("DB_NAME"),
+		UserService:      os.Getenv("USER_SERVICE"),
+		AuthorService:    os.Getenv("AUTHOR_SERVICE"),
+		CategoryService:  os.Getenv("CATEGORY_SERVICE"),
+		PublisherService: os.Getenv("PUBLISHER_SERVICE"),
+		RedisHost:        os.Getenv("REDIS_HOST"),
+		RedisPort:        os.Getenv("REDIS_PORT"),
+
+		PurgeRetention: os.Getenv("PURGE_RETENTION"),
+		PurgeInterval:  os.Getenv("PURGE_INTERVAL"),
+
+		RecommendationInterval: os.Getenv("RECOMMENDATION_INTERVAL"),
+		TrendingInterval:       os.Getenv("TRENDING_INTERVAL"),
+		LoanReturnInterval:     os.Getenv("LOAN_RETURN_INTERVAL"),
+		NameRefreshInterval:    os.Getenv("NAME_REFRESH_INTERVAL"),
+
+		CoverStorageDir: os.Getenv("COVER_STORAGE_DIR"),
+		CoverMaxBytes:   os.Getenv("COVER_MAX_BYTES"),
+
+		OpdsPort: os.Getenv("OPDS_PORT"),
+
+		EnrichmentProvider: os.Getenv("ENRICHMENT_PROVIDER"),
+		EnrichmentURL:      os.Getenv("ENRICHMENT_URL"),
+		EnrichmentFixtures: os.Getenv("ENRICHMENT_FIXTURES"),
 	}
 
 	logConfig := zap.NewDevelopmentConfig()
@@ -68,14 +114,42 @@ func main() {
 	}
 
 	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s", config.DBHost, config.DBUser, config.DBPassword, config.DBName, config.DBPort)
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{TranslateError: true})
 	if err != nil {
 		panic(err)
 	}
 
 	db.Exec("CREATE EXTENSION IF NOT EXISTS \"uuid-ossp\"")
 	db.AutoMigrate(&model.Book{})
+	db.AutoMigrate(&model.Work{})
+	db.AutoMigrate(&model.Series{})
+	db.AutoMigrate(&model.BookContributor{})
 	db.AutoMigrate(&model.BorrowRecord{})
+	db.AutoMigrate(&model.BookSimilarity{})
+	db.AutoMigrate(&model.Review{})
+	db.AutoMigrate(&model.ReadingList{})
+	db.AutoMigrate(&model.ReadingListItem{})
+	db.AutoMigrate(&model.Notification{})
+	db.AutoMigrate(&model.BookDraft{})
+	db.AutoMigrate(&model.DigitalLicense{})
+	db.AutoMigrate(&model.Branch{})
+	db.AutoMigrate(&model.Transfer{})
+	db.AutoMigrate(&model.Hold{})
+	db.Exec(`INSERT INTO book_contributors (id, book_id, author_id, role, position, author_name, created_at)
+		SELECT uuid_generate_v4(), books.id, books.author_id, 'author', 1, books.author_name, NOW() FROM books
+		WHERE books.author_id <> '' AND NOT EXISTS (SELECT 1 FROM book_contributors WHERE book_contributors.book_id = books.id)`)
+	db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_books_isbn ON books (isbn) WHERE isbn <> '' AND deleted_at IS NULL")
+	db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_reviews_book_user ON reviews (book_id, user_id) WHERE deleted_at IS NULL")
+	db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_branches_code ON branches (code) WHERE deleted_at IS NULL")
+	db.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm")
+	db.Exec(`ALTER TABLE books ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+		setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+		setweight(to_tsvector('english', coalesce(author_name, '')), 'B') ||
+		setweight(to_tsvector('english', coalesce(category_name, '')), 'C')
+	) STORED`)
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_books_search_vector ON books USING GIN (search_vector)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_books_name_trgm ON books USING GIN (name gin_trgm_ops)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_books_author_name_trgm ON books USING GIN (author_name gin_trgm_ops)")
 
 	redisClient := redis.NewClient(&redis.Options{
 		Addr: fmt.Sprintf("%s:%s", config.RedisHost, config.RedisPort),
@@ -99,16 +173,141 @@ func main() {
 	}
 	defer categoryConn.Close()
 
+	publisherConn, err := grpc.NewClient(config.PublisherService, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatalf("failed to connect publisher service %v", err)
+	}
+	defer publisherConn.Close()
+
 	userClient := user.NewUserServiceClient(userConn)
 	authorClient := author.NewAuthorServiceClient(authorConn)
 	categoryClient := category.NewCategoryServiceClient(categoryConn)
+	publisherClient := publisher.NewPublisherServiceClient(publisherConn)
 
 	bookRepo := repository.NewBookRepository(db, logger, redisClient)
-	bookService := service.NewBookService(bookRepo, logger, userClient, authorClient, categoryClient)
-	bookHandler := handler.NewBookHandler(bookService, logger)
+	weights := repository.DefaultSimilarityWeights
+	for env, weight := range map[string]*float64{
+		"SIMILAR_WEIGHT_AUTHOR":   &weights.Author,
+		"SIMILAR_WEIGHT_CATEGORY": &weights.Category,
+		"SIMILAR_WEIGHT_ANCESTOR": &weights.Ancestor,
+		"SIMILAR_WEIGHT_COBORROW": &weights.CoBorrow,
+		"SIMILAR_WEIGHT_TITLE":    &weights.Title,
+	} {
+		if value := os.Getenv(env); value != "" {
+			if *weight, err = strconv.ParseFloat(value, 64); err != nil {
+				log.Fatalf("invalid %s %v", env, err)
+			}
+		}
+	}
+
+	workRepo := repository.NewWorkRepository(db, logger)
+	branchRepo := repository.NewBranchRepository(db, logger)
+	bookService := service.NewBookService(bookRepo, workRepo, branchRepo, logger, userClient, authorClient, categoryClient, publisherClient, weights)
+	reviewRepo := repository.NewReviewRepository(db, logger)
+	reviewService := service.NewReviewService(reviewRepo, bookRepo, logger, userClient)
+	readingListRepo := repository.NewReadingListRepository(db, logger)
+	readingListService := service.NewReadingListService(readingListRepo, bookRepo, logger, userClient)
+	workService := service.NewWorkService(workRepo, bookRepo, logger, userClient, authorClient, categoryClient)
+	coverDir := config.CoverStorageDir
+	if coverDir == "" {
+		coverDir = "data/covers"
+	}
+	coverStore, err := storage.NewLocalStore(coverDir)
+	if err != nil {
+		log.Fatalf("failed to open cover storage %v", err)
+	}
+
+	coverMaxBytes := service.DefaultMaxCoverBytes
+	if config.CoverMaxBytes != "" {
+		if coverMaxBytes, err = strconv.Atoi(config.CoverMaxBytes); err != nil || coverMaxBytes <= 0 {
+			log.Fatalf("invalid COVER_MAX_BYTES %s", config.CoverMaxBytes)
+		}
+	}
+	coverService := service.NewCoverService(bookRepo, coverStore, logger, userClient, coverMaxBytes)
+
+	var provider enrichment.Provider
+	switch config.EnrichmentProvider {
+	case "", "openlibrary":
+		provider = enrichment.NewOpenLibraryProvider(config.EnrichmentURL)
+	case "stub":
+		fixtures := config.EnrichmentFixtures
+		if fixtures == "" {
+			fixtures = "enrichment/fixtures"
+		}
+		provider = enrichment.NewStubProvider(fixtures)
+	default:
+		log.Fatalf("invalid ENRICHMENT_PROVIDER %s", config.EnrichmentProvider)
+	}
+	draftRepo := repository.NewBookDraftRepository(db, logger)
+	enrichmentService := service.NewEnrichmentService(draftRepo, bookRepo, bookService, provider, logger, userClient, authorClient, categoryClient)
+	branchService := service.NewBranchService(branchRepo, logger, userClient)
+	transferService := service.NewTransferService(bookRepo, branchRepo, logger, userClient)
+	bookHandler := handler.NewBookHandler(bookService, reviewService, readingListService, workService, coverService, enrichmentService, branchService, transferService, logger)
+
+	if config.PurgeRetention != "" {
+		retention, err := time.ParseDuration(config.PurgeRetention)
+		if err != nil {
+			log.Fatalf("invalid PURGE_RETENTION %v", err)
+		}
+
+		interval := 24 * time.Hour
+		if config.PurgeInterval != "" {
+			if interval, err = time.ParseDuration(config.PurgeInterval); err != nil {
+				log.Fatalf("invalid PURGE_INTERVAL %v", err)
+			}
+		}
+
+		go purge.NewJob(bookRepo, "books", logger, retention, interval).Run(context.Background())
+	}
+
+	recommendationInterval := time.Hour
+	if config.RecommendationInterval != "" {
+		if recommendationInterval, err = time.ParseDuration(config.RecommendationInterval); err != nil {
+			log.Fatalf("invalid RECOMMENDATION_INTERVAL %v", err)
+		}
+	}
+
+	go job.NewSimilarityJob(bookRepo, logger, recommendationInterval).Run(context.Background())
+
+	trendingInterval := 24 * time.Hour
+	if config.TrendingInterval != "" {
+		if trendingInterval, err = time.ParseDuration(config.TrendingInterval); err != nil {
+			log.Fatalf("invalid TRENDING_INTERVAL %v", err)
+		}
+	}
+
+	go job.NewTrendingJob(bookRepo, logger, trendingInterval).Run(context.Background())
+
+	loanReturnInterval := time.Minute
+	if config.LoanReturnInterval != "" {
+		if loanReturnInterval, err = time.ParseDuration(config.LoanReturnInterval); err != nil {
+			log.Fatalf("invalid LOAN_RETURN_INTERVAL %v", err)
+		}
+	}
+
+	go job.NewLoanJob(bookRepo, logger, loanReturnInterval).Run(context.Background())
+
+	nameRefreshInterval := time.Hour
+	if config.NameRefreshInterval != "" {
+		if nameRefreshInterval, err = time.ParseDuration(config.NameRefreshInterval); err != nil {
+			log.Fatalf("invalid NAME_REFRESH_INTERVAL %v", err)
+		}
+	}
+
+	go job.NewNameJob(bookRepo, authorClient, categoryClient, config.JwtSecret, logger, nameRefreshInterval).Run(context.Background())
+
+	if config.OpdsPort != "" {
+		opdsServer := opds.NewServer(bookRepo, bookService, coverService, logger, config.JwtSecret)
+		go func() {
+			if err := http.ListenAndServe(":"+config.OpdsPort, opdsServer); err != nil {
+				log.Fatalf("failed to serve opds: %v", err)
+			}
+		}()
+	}
 
 	server := grpc.NewServer(
 		grpc.UnaryInterceptor(middleware.JWTAuthInterceptor(config.JwtSecret)),
+		grpc.StreamInterceptor(middleware.JWTStreamInterceptor(config.JwtSecret)),
 	)
 	book.RegisterBookServiceServer(server, bookHandler)
 	reflection.Register(server)
@@ -0,0 +1,196 @@
+package marc
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	recordTerminator   = 0x1D
+	fieldTerminator    = 0x1E
+	subfieldDelimiter  = 0x1F
+	leaderLength       = 24
+	directoryEntrySize = 12
+	maxRecordLength    = 99999
+	maxFieldLength     = 9999
+)
+
+type BinaryReader struct {
+	r     *bufio.Reader
+	count int
+}
+
+func NewBinaryReader(r io.Reader) *BinaryReader {
+	return &BinaryReader{r: bufio.NewReader(r)}
+}
+
+func (br *BinaryReader) Read() (*Record, error) {
+	// Some exports put a line break between records.
+	for {
+		c, err := br.r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		if c != '\n' && c != '\r' {
+			br.r.UnreadByte()
+			break
+		}
+	}
+	br.count++
+
+	prefix := make([]byte, 5)
+	if _, err := io.ReadFull(br.r, prefix); err != nil {
+		return nil, fmt.Errorf("%w: record %d is truncated", ErrInvalidRecord, br.count)
+	}
+	length, err := strconv.Atoi(string(prefix))
+	if err != nil || length <= leaderLength || length > maxRecordLength {
+		return nil, fmt.Errorf("%w: record %d has an invalid length", ErrInvalidRecord, br.count)
+	}
+
+	data := make([]byte, length)
+	copy(data, prefix)
+	if _, err := io.ReadFull(br.r, data[5:]); err != nil {
+		return nil, fmt.Errorf("%w: record %d is truncated", ErrInvalidRecord, br.count)
+	}
+
+	record, err := decodeBinary(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: record %d %s", ErrInvalidRecord, br.count, err.Error())
+	}
+	return record, nil
+}
+
+func decodeBinary(data []byte) (*Record, error) {
+	if data[len(data)-1] != recordTerminator {
+		return nil, errors.New("is missing the record terminator")
+	}
+
+	leader := string(data[:leaderLength])
+	base, err := strconv.Atoi(leader[12:17])
+	if err != nil || base <= leaderLength || base >= len(data) || data[base-1] != fieldTerminator {
+		return nil, errors.New("has an invalid base address")
+	}
+
+	directory := data[leaderLength : base-1]
+	if len(directory)%directoryEntrySize != 0 {
+		return nil, errors.New("has an invalid directory")
+	}
+
+	record := &Record{Leader: leader}
+	for i := 0; i < len(directory); i += directoryEntrySize {
+		entry := directory[i : i+directoryEntrySize]
+		tag := string(entry[:3])
+		length, err1 := strconv.Atoi(string(entry[3:7]))
+		start, err2 := strconv.Atoi(string(entry[7:12]))
+		if err1 != nil || err2 != nil || length < 1 || base+start+length > len(data)-1 {
+			return nil, fmt.Errorf("has an invalid directory entry for field %s", tag)
+		}
+
+		raw := data[base+start : base+start+length]
+		raw = bytes.TrimSuffix(raw, []byte{fieldTerminator})
+
+		if isControlTag(tag) {
+			record.AddControlField(tag, string(raw))
+			continue
+		}
+
+		if len(raw) < 2 {
+			return nil, fmt.Errorf("has a data field %s without indicators", tag)
+		}
+		field := &Field{Tag: tag, Ind1: raw[0], Ind2: raw[1]}
+		for _, part := range bytes.Split(raw[2:], []byte{subfieldDelimiter})[1:] {
+			if len(part) == 0 {
+				continue
+			}
+			field.Subfields = append(field.Subfields, Subfield{Code: part[0], Value: string(part[1:])})
+		}
+		record.Fields = append(record.Fields, field)
+	}
+
+	return record, nil
+}
+
+type BinaryWriter struct {
+	w io.Writer
+}
+
+func NewBinaryWriter(w io.Writer) *BinaryWriter {
+	return &BinaryWriter{w: w}
+}
+
+// Write encodes the record as UTF-8 MARC21. The record length, base address
+// and character coding in the leader are always recomputed.
+func (bw *BinaryWriter) Write(r *Record) error {
+	var directory, body bytes.Buffer
+	for _, f := range r.Fields {
+		if !validTag(f.Tag) {
+			return fmt.Errorf("%w: invalid tag %q", ErrInvalidRecord, f.Tag)
+		}
+
+		start := body.Len()
+		if f.IsControl() {
+			body.WriteString(clean(f.Value))
+		} else {
+			body.WriteByte(indicator(f.Ind1))
+			body.WriteByte(indicator(f.Ind2))
+			for _, v := range f.Subfields {
+				body.WriteByte(subfieldDelimiter)
+				body.WriteByte(v.Code)
+				body.WriteString(clean(v.Value))
+			}
+		}
+		body.WriteByte(fieldTerminator)
+
+		length := body.Len() - start
+		if length > maxFieldLength {
+			return fmt.Errorf("%w: field %s is longer than %d bytes", ErrInvalidRecord, f.Tag, maxFieldLength)
+		}
+		fmt.Fprintf(&directory, "%s%04d%05d", f.Tag, length, start)
+	}
+	directory.WriteByte(fieldTerminator)
+
+	base := leaderLength + directory.Len()
+	total := base + body.Len() + 1
+	if total > maxRecordLength {
+		return fmt.Errorf("%w: record is longer than %d bytes", ErrInvalidRecord, maxRecordLength)
+	}
+
+	leader := []byte(r.Leader)
+	if len(leader) != leaderLength {
+		leader = []byte(defaultLeader)
+	}
+	copy(leader[0:5], fmt.Sprintf("%05d", total))
+	leader[9] = 'a'
+	copy(leader[10:12], "22")
+	copy(leader[12:17], fmt.Sprintf("%05d", base))
+	copy(leader[20:24], "4500")
+
+	var out bytes.Buffer
+	out.Grow(total)
+	out.Write(leader)
+	out.Write(directory.Bytes())
+	out.Write(body.Bytes())
+	out.WriteByte(recordTerminator)
+
+	_, err := bw.w.Write(out.Bytes())
+	return err
+}
+
+func (bw *BinaryWriter) Close() error {
+	return nil
+}
+
+// clean drops the MARC delimiter characters, which cannot appear in data.
+func clean(v string) string {
+	return strings.Map(func(r rune) rune {
+		if r == recordTerminator || r == fieldTerminator || r == subfieldDelimiter {
+			return -1
+		}
+		return r
+	}, v)
+}
@@ -0,0 +1,308 @@
+package marc
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxDescriptionBytes keeps the 520 field under the ISO 2709 field limit.
+const maxDescriptionBytes = 9000
+
+var (
+	yearPattern  = regexp.MustCompile(`\d{4}`)
+	pagesPattern = regexp.MustCompile(`(\d+)\s*(?:p\b|p\.|pages?\b)`)
+)
+
+// relatorCodes maps the MARC relator codes used in $4 to the relator terms
+// used in $e.
+var relatorCodes = map[string]string{
+	"aut": "author",
+	"edt": "editor",
+	"trl": "translator",
+	"ill": "illustrator",
+}
+
+// languageCodes maps ISO 639-1 codes to the three-letter MARC codes that
+// 008/35-37 requires. MARC uses the bibliographic variant of ISO 639-2, so
+// German is "ger" rather than "deu".
+var languageCodes = map[string]string{
+	"af": "afr", "ar": "ara", "bg": "bul", "bn": "ben", "ca": "cat",
+	"cs": "cze", "cy": "wel", "da": "dan", "de": "ger", "el": "gre",
+	"en": "eng", "es": "spa", "et": "est", "fa": "per", "fi": "fin",
+	"fr": "fre", "ga": "gle", "he": "heb", "hi": "hin", "hr": "hrv",
+	"hu": "hun", "id": "ind", "is": "ice", "it": "ita", "ja": "jpn",
+	"jv": "jav", "ko": "kor", "la": "lat", "lt": "lit", "lv": "lav",
+	"ms": "may", "nl": "dut", "no": "nor", "pl": "pol", "pt": "por",
+	"ro": "rum", "ru": "rus", "sk": "slo", "sl": "slv", "sr": "srp",
+	"su": "sun", "sv": "swe", "sw": "swa", "ta": "tam", "th": "tha",
+	"tl": "tgl", "tr": "tur", "uk": "ukr", "ur": "urd", "vi": "vie",
+	"zh": "chi",
+}
+
+// undetermined is the MARC code for an unknown language.
+const undetermined = "und"
+
+// Book is the bibliographic data exchanged as MARC21:
+//
+//	001      control number (book id)
+//	008      publication year and language
+//	020 $a   ISBN
+//	100 $a   first author, 700 $a $e other contributors with their role
+//	245 $a   title
+//	250 $a   edition
+//	260/264  $b publisher, $c publication year
+//	300 $a   page count
+//	520 $a   description
+//	650 $a   subjects, the first is used as the category
+type Book struct {
+	ID            string
+	Title         string
+	ISBN          string
+	Contributors  []Contributor
+	Subjects      []string
+	Publisher     string
+	PublishedYear int
+	Language      string
+	Edition       string
+	PageCount     int
+	Description   string
+}
+
+type Contributor struct {
+	Name string
+	Role string
+}
+
+func (b *Book) Record() *Record {
+	r := NewRecord()
+	if b.ID != "" {
+		r.AddControlField("001", b.ID)
+	}
+	r.AddControlField("008", fixedData(b))
+
+	if b.ISBN != "" {
+		r.AddDataField("020", ' ', ' ', Subfield{'a', b.ISBN})
+	}
+
+	mainEntry := -1
+	for i, v := range b.Contributors {
+		if v.Role == "author" {
+			mainEntry = i
+			r.AddDataField("100", '0', ' ', Subfield{'a', v.Name}, Subfield{'e', v.Role})
+			break
+		}
+	}
+
+	titleInd := byte('0')
+	if mainEntry >= 0 {
+		titleInd = '1'
+	}
+	r.AddDataField("245", titleInd, '0', Subfield{'a', b.Title})
+
+	if b.Edition != "" {
+		r.AddDataField("250", ' ', ' ', Subfield{'a', b.Edition})
+	}
+
+	if b.Publisher != "" || b.PublishedYear > 0 {
+		var subfields []Subfield
+		if b.Publisher != "" {
+			subfields = append(subfields, Subfield{'b', b.Publisher})
+		}
+		if b.PublishedYear > 0 {
+			subfields = append(subfields, Subfield{'c', strconv.Itoa(b.PublishedYear)})
+		}
+		r.AddDataField("264", ' ', '1', subfields...)
+	}
+
+	if b.PageCount > 0 {
+		r.AddDataField("300", ' ', ' ', Subfield{'a', fmt.Sprintf("%d pages", b.PageCount)})
+	}
+
+	if b.Description != "" {
+		r.AddDataField("520", ' ', ' ', Subfield{'a', truncate(b.Description, maxDescriptionBytes)})
+	}
+
+	for _, v := range b.Subjects {
+		r.AddDataField("650", ' ', '4', Subfield{'a', v})
+	}
+
+	for i, v := range b.Contributors {
+		if i == mainEntry {
+			continue
+		}
+		r.AddDataField("700", '0', ' ', Subfield{'a', v.Name}, Subfield{'e', v.Role})
+	}
+
+	return r
+}
+
+// ParseBook maps a bibliographic record back to a Book. It is lenient about
+// ISBD punctuation and inverted personal names, which most catalogs use.
+func ParseBook(r *Record) *Book {
+	b := &Book{}
+	if f := r.Field("001"); f != nil {
+		b.ID = strings.TrimSpace(f.Value)
+	}
+
+	if f := r.Field("020"); f != nil {
+		if parts := strings.Fields(f.Subfield('a')); len(parts) > 0 {
+			b.ISBN = parts[0]
+		}
+	}
+
+	for _, tag := range []string{"100", "700"} {
+		for _, f := range r.FieldsByTag(tag) {
+			name := personalName(f)
+			if name == "" {
+				continue
+			}
+			b.Contributors = append(b.Contributors, Contributor{Name: name, Role: relator(f)})
+		}
+	}
+
+	if f := r.Field("245"); f != nil {
+		title := trimPunctuation(f.Subfield('a'))
+		if sub := trimPunctuation(f.Subfield('b')); sub != "" {
+			title += ": " + sub
+		}
+		b.Title = title
+	}
+
+	if f := r.Field("250"); f != nil {
+		b.Edition = trimPunctuation(f.Subfield('a'))
+	}
+
+	if f := publication(r); f != nil {
+		b.Publisher = trimPunctuation(f.Subfield('b'))
+		if year := yearPattern.FindString(f.Subfield('c')); year != "" {
+			b.PublishedYear, _ = strconv.Atoi(year)
+		}
+	}
+
+	if f := r.Field("008"); f != nil && len(f.Value) >= 38 {
+		if year, err := strconv.Atoi(f.Value[7:11]); err == nil && b.PublishedYear == 0 {
+			b.PublishedYear = year
+		}
+		if language := strings.TrimSpace(f.Value[35:38]); isLetters(language) && language != undetermined {
+			b.Language = language
+		}
+	}
+	if f := r.Field("041"); f != nil && b.Language == "" {
+		if language := strings.TrimSpace(f.Subfield('a')); language != undetermined {
+			b.Language = language
+		}
+	}
+
+	if f := r.Field("300"); f != nil {
+		if m := pagesPattern.FindStringSubmatch(f.Subfield('a')); m != nil {
+			b.PageCount, _ = strconv.Atoi(m[1])
+		}
+	}
+
+	if f := r.Field("520"); f != nil {
+		b.Description = strings.TrimSpace(f.Subfield('a'))
+	}
+
+	for _, f := range r.FieldsByTag("650") {
+		if subject := trimPunctuation(f.Subfield('a')); subject != "" {
+			b.Subjects = append(b.Subjects, subject)
+		}
+	}
+
+	return b
+}
+
+// publication prefers the 264 publication statement over the older 260.
+func publication(r *Record) *Field {
+	for _, f := range r.FieldsByTag("264") {
+		if f.Ind2 == '1' {
+			return f
+		}
+	}
+	return r.Field("260")
+}
+
+// personalName turns an inverted "Surname, Forename" heading into the
+// direct order the catalog stores.
+func personalName(f *Field) string {
+	name := trimPunctuation(f.Subfield('a'))
+	if f.Ind1 != '1' {
+		return name
+	}
+
+	surname, forename, ok := strings.Cut(name, ", ")
+	if !ok {
+		return name
+	}
+	return strings.TrimSpace(forename) + " " + strings.TrimSpace(surname)
+}
+
+func relator(f *Field) string {
+	if term := strings.ToLower(trimPunctuation(f.Subfield('e'))); term != "" {
+		return term
+	}
+	if term, ok := relatorCodes[strings.ToLower(strings.TrimSpace(f.Subfield('4')))]; ok {
+		return term
+	}
+	return "author"
+}
+
+// trimPunctuation strips trailing ISBD punctuation. A final period is kept
+// after an initial such as "J. R. R.".
+func trimPunctuation(v string) string {
+	v = strings.TrimRight(strings.TrimSpace(v), " /:;,=")
+	if strings.HasSuffix(v, ".") {
+		before, _ := utf8.DecodeLastRuneInString(strings.TrimSuffix(v, "."))
+		if !unicode.IsUpper(before) {
+			v = strings.TrimSuffix(v, ".")
+		}
+	}
+	return strings.TrimSpace(v)
+}
+
+func fixedData(b *Book) string {
+	dates := "nuuuu    "
+	if b.PublishedYear > 0 {
+		dates = fmt.Sprintf("s%04d    ", b.PublishedYear)
+	}
+
+	return "||||||" + dates + "xx " + strings.Repeat("|", 17) + marcLanguage(b.Language) + " d"
+}
+
+// marcLanguage returns the three-letter code for 008/35-37, mapping two-letter
+// ISO 639-1 codes and falling back to "und" for anything it cannot place.
+func marcLanguage(language string) string {
+	if len(language) == 3 && isLetters(language) {
+		return language
+	}
+	if code, ok := languageCodes[language]; ok {
+		return code
+	}
+	return undetermined
+}
+
+func truncate(v string, limit int) string {
+	if len(v) <= limit {
+		return v
+	}
+	for limit > 0 && !utf8.RuneStart(v[limit]) {
+		limit--
+	}
+	return v[:limit]
+}
+
+func isLetters(v string) bool {
+	if v == "" {
+		return false
+	}
+	for _, c := range v {
+		if c < 'a' || c > 'z' {
+			return false
+		}
+	}
+	return true
+}
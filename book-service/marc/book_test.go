@@ -0,0 +1,135 @@
+package marc
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"testing"
+)
+
+var sampleBooks = []*Book{
+	{
+		ID:    "OL1",
+		Title: "The hobbit",
+		ISBN:  "9780261103344",
+		Contributors: []Contributor{
+			{Name: "J. R. R. Tolkien", Role: "author"},
+			{Name: "Pauline Baynes", Role: "illustrator"},
+		},
+		Subjects:      []string{"Fantasy fiction", "Middle Earth (Imaginary place)"},
+		Publisher:     "Allen & Unwin",
+		PublishedYear: 1937,
+		Language:      "eng",
+	},
+	{
+		ID:    "OL2",
+		Title: "Cien años de soledad",
+		ISBN:  "9780060883287",
+		Contributors: []Contributor{
+			{Name: "Gabriel García Márquez", Role: "author"},
+			{Name: "Gregory Rabassa", Role: "translator"},
+		},
+		Subjects:      []string{"Magic realism (Literature)"},
+		Publisher:     "Sudamericana",
+		PublishedYear: 1967,
+		Language:      "spa",
+	},
+}
+
+func TestRoundTrip(t *testing.T) {
+	tests := []struct {
+		name      string
+		file      string
+		newReader func(io.Reader) Reader
+		newWriter func(io.Writer) Writer
+	}{
+		{
+			name:      "marc21",
+			file:      "testdata/sample.mrc",
+			newReader: func(r io.Reader) Reader { return NewBinaryReader(r) },
+			newWriter: func(w io.Writer) Writer { return NewBinaryWriter(w) },
+		},
+		{
+			name:      "marcxml",
+			file:      "testdata/sample.xml",
+			newReader: func(r io.Reader) Reader { return NewXMLReader(r) },
+			newWriter: func(w io.Writer) Writer { return NewXMLWriter(w) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.Open(tt.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			books := readBooks(t, tt.newReader(f))
+			if !reflect.DeepEqual(books, sampleBooks) {
+				t.Fatalf("parsed %s:\ngot  %+v\nwant %+v", tt.file, books, sampleBooks)
+			}
+
+			var out bytes.Buffer
+			w := tt.newWriter(&out)
+			for _, b := range books {
+				if err := w.Write(b.Record()); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			again := readBooks(t, tt.newReader(&out))
+			if !reflect.DeepEqual(again, books) {
+				t.Fatalf("after writing:\ngot  %+v\nwant %+v", again, books)
+			}
+		})
+	}
+}
+
+func TestLanguage(t *testing.T) {
+	tests := []struct {
+		language string
+		fixed    string
+		parsed   string
+	}{
+		{language: "eng", fixed: "eng", parsed: "eng"},
+		{language: "en", fixed: "eng", parsed: "eng"},
+		{language: "de", fixed: "ger", parsed: "ger"},
+		{language: "id", fixed: "ind", parsed: "ind"},
+		{language: "xx", fixed: "und", parsed: ""},
+		{language: "", fixed: "und", parsed: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.language, func(t *testing.T) {
+			r := (&Book{Title: "Title", Language: tt.language}).Record()
+			if got := r.Field("008").Value[35:38]; got != tt.fixed {
+				t.Errorf("008/35-37 = %q, want %q", got, tt.fixed)
+			}
+			if got := ParseBook(r).Language; got != tt.parsed {
+				t.Errorf("parsed language = %q, want %q", got, tt.parsed)
+			}
+		})
+	}
+}
+
+func readBooks(t *testing.T, r Reader) []*Book {
+	t.Helper()
+
+	var books []*Book
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return books
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		books = append(books, ParseBook(record))
+	}
+}
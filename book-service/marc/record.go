@@ -0,0 +1,130 @@
+// Package marc reads and writes MARC21 bibliographic records in the binary
+// ISO 2709 transmission format and in MARCXML.
+package marc
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+const defaultLeader = "00000nam a2200000 i 4500"
+
+var ErrInvalidRecord = errors.New("invalid marc record")
+
+type Subfield struct {
+	Code  byte
+	Value string
+}
+
+// Field is either a control field (tags 001-009), which only has a Value, or
+// a data field with two indicators and subfields.
+type Field struct {
+	Tag       string
+	Ind1      byte
+	Ind2      byte
+	Value     string
+	Subfields []Subfield
+}
+
+func (f *Field) IsControl() bool {
+	return isControlTag(f.Tag)
+}
+
+// Subfield returns the value of the first subfield with the code.
+func (f *Field) Subfield(code byte) string {
+	for _, v := range f.Subfields {
+		if v.Code == code {
+			return v.Value
+		}
+	}
+	return ""
+}
+
+type Record struct {
+	Leader string
+	Fields []*Field
+}
+
+func NewRecord() *Record {
+	return &Record{Leader: defaultLeader}
+}
+
+func (r *Record) AddControlField(tag, value string) {
+	r.Fields = append(r.Fields, &Field{Tag: tag, Value: value})
+}
+
+func (r *Record) AddDataField(tag string, ind1, ind2 byte, subfields ...Subfield) {
+	r.Fields = append(r.Fields, &Field{Tag: tag, Ind1: ind1, Ind2: ind2, Subfields: subfields})
+}
+
+// Field returns the first field with the tag, or nil.
+func (r *Record) Field(tag string) *Field {
+	for _, v := range r.Fields {
+		if v.Tag == tag {
+			return v
+		}
+	}
+	return nil
+}
+
+func (r *Record) FieldsByTag(tag string) []*Field {
+	var fields []*Field
+	for _, v := range r.Fields {
+		if v.Tag == tag {
+			fields = append(fields, v)
+		}
+	}
+	return fields
+}
+
+// ValidUTF8 reports whether every value in the record is UTF-8. Records in
+// the legacy MARC-8 encoding usually are not.
+func (r *Record) ValidUTF8() bool {
+	for _, f := range r.Fields {
+		if !utf8.ValidString(f.Value) {
+			return false
+		}
+		for _, v := range f.Subfields {
+			if !utf8.ValidString(v.Value) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+type Reader interface {
+	// Read returns the next record, or io.EOF when there are none left.
+	Read() (*Record, error)
+}
+
+type Writer interface {
+	Write(*Record) error
+	// Close finishes the output without closing the underlying writer.
+	Close() error
+}
+
+func isControlTag(tag string) bool {
+	return strings.HasPrefix(tag, "00")
+}
+
+func validTag(tag string) bool {
+	if len(tag) != 3 {
+		return false
+	}
+	for i := 0; i < 3; i++ {
+		c := tag[i]
+		if !(c >= '0' && c <= '9') && !(c >= 'A' && c <= 'Z') && !(c >= 'a' && c <= 'z') {
+			return false
+		}
+	}
+	return true
+}
+
+func indicator(v byte) byte {
+	if v == 0 {
+		return ' '
+	}
+	return v
+}
@@ -0,0 +1,162 @@
+package marc
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+const xmlNamespace = "http://www.loc.gov/MARC21/slim"
+
+type xmlRecord struct {
+	XMLName       xml.Name          `xml:"record"`
+	Leader        string            `xml:"leader"`
+	ControlFields []xmlControlField `xml:"controlfield"`
+	DataFields    []xmlDataField    `xml:"datafield"`
+}
+
+type xmlControlField struct {
+	Tag   string `xml:"tag,attr"`
+	Value string `xml:",chardata"`
+}
+
+type xmlDataField struct {
+	Tag       string        `xml:"tag,attr"`
+	Ind1      string        `xml:"ind1,attr"`
+	Ind2      string        `xml:"ind2,attr"`
+	Subfields []xmlSubfield `xml:"subfield"`
+}
+
+type xmlSubfield struct {
+	Code  string `xml:"code,attr"`
+	Value string `xml:",chardata"`
+}
+
+// XMLReader reads the record elements of a MARCXML document, whether the
+// root is a collection or a single record.
+type XMLReader struct {
+	d     *xml.Decoder
+	count int
+}
+
+func NewXMLReader(r io.Reader) *XMLReader {
+	return &XMLReader{d: xml.NewDecoder(r)}
+}
+
+func (xr *XMLReader) Read() (*Record, error) {
+	for {
+		token, err := xr.d.Token()
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidRecord, err.Error())
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "record" {
+			continue
+		}
+		xr.count++
+
+		var raw xmlRecord
+		if err := xr.d.DecodeElement(&raw, &start); err != nil {
+			return nil, fmt.Errorf("%w: record %d %s", ErrInvalidRecord, xr.count, err.Error())
+		}
+
+		record := &Record{Leader: raw.Leader}
+		for _, v := range raw.ControlFields {
+			record.AddControlField(v.Tag, v.Value)
+		}
+		for _, v := range raw.DataFields {
+			field := &Field{Tag: v.Tag, Ind1: xmlIndicator(v.Ind1), Ind2: xmlIndicator(v.Ind2)}
+			for _, s := range v.Subfields {
+				if len(s.Code) != 1 {
+					return nil, fmt.Errorf("%w: record %d has an invalid subfield code in field %s", ErrInvalidRecord, xr.count, v.Tag)
+				}
+				field.Subfields = append(field.Subfields, Subfield{Code: s.Code[0], Value: s.Value})
+			}
+			record.Fields = append(record.Fields, field)
+		}
+
+		return record, nil
+	}
+}
+
+type XMLWriter struct {
+	w       io.Writer
+	e       *xml.Encoder
+	started bool
+}
+
+func NewXMLWriter(w io.Writer) *XMLWriter {
+	e := xml.NewEncoder(w)
+	e.Indent("", "  ")
+	return &XMLWriter{w: w, e: e}
+}
+
+func (xw *XMLWriter) Write(r *Record) error {
+	if err := xw.start(); err != nil {
+		return err
+	}
+
+	raw := xmlRecord{Leader: r.Leader}
+	if len(raw.Leader) != leaderLength {
+		raw.Leader = defaultLeader
+	}
+	for _, f := range r.Fields {
+		if !validTag(f.Tag) {
+			return fmt.Errorf("%w: invalid tag %q", ErrInvalidRecord, f.Tag)
+		}
+
+		if f.IsControl() {
+			raw.ControlFields = append(raw.ControlFields, xmlControlField{Tag: f.Tag, Value: f.Value})
+			continue
+		}
+
+		field := xmlDataField{Tag: f.Tag, Ind1: string(indicator(f.Ind1)), Ind2: string(indicator(f.Ind2))}
+		for _, s := range f.Subfields {
+			field.Subfields = append(field.Subfields, xmlSubfield{Code: string(s.Code), Value: s.Value})
+		}
+		raw.DataFields = append(raw.DataFields, field)
+	}
+
+	return xw.e.Encode(raw)
+}
+
+func (xw *XMLWriter) Close() error {
+	if err := xw.start(); err != nil {
+		return err
+	}
+	if err := xw.e.EncodeToken(xml.EndElement{Name: xml.Name{Local: "collection"}}); err != nil {
+		return err
+	}
+	if err := xw.e.Flush(); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(xw.w, "\n")
+	return err
+}
+
+func (xw *XMLWriter) start() error {
+	if xw.started {
+		return nil
+	}
+	xw.started = true
+
+	if _, err := io.WriteString(xw.w, xml.Header); err != nil {
+		return err
+	}
+	return xw.e.EncodeToken(xml.StartElement{
+		Name: xml.Name{Local: "collection"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "xmlns"}, Value: xmlNamespace}},
+	})
+}
+
+func xmlIndicator(v string) byte {
+	if len(v) != 1 {
+		return ' '
+	}
+	return v[0]
+}
@@ -3,6 +3,8 @@ package middleware
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
 	"strings"
 
 	"github.com/dgrijalva/jwt-go"
@@ -14,28 +16,90 @@ import (
 
 func JWTAuthInterceptor(secretKey string) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-		md, ok := metadata.FromIncomingContext(ctx)
-		if !ok {
-			return nil, status.Error(codes.Unauthenticated, "missing metadata")
+		if err := authorize(ctx, secretKey); err != nil {
+			return nil, err
 		}
 
-		authHeader, ok := md["authorization"]
-		if !ok || len(authHeader) == 0 {
-			return nil, status.Error(codes.Unauthenticated, "missing authorization metadata")
+		return handler(ctx, req)
+	}
+}
+
+func JWTStreamInterceptor(secretKey string) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := authorize(ss.Context(), secretKey); err != nil {
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+func authorize(ctx context.Context, secretKey string) error {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "missing metadata")
+	}
+
+	authHeader, ok := md["authorization"]
+	if !ok || len(authHeader) == 0 {
+		return status.Error(codes.Unauthenticated, "missing authorization metadata")
+	}
+
+	if _, err := parseToken(strings.TrimPrefix(authHeader[0], "Bearer "), secretKey); err != nil {
+		return status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	return nil
+}
+
+type userIDKey struct{}
+
+// JWTHTTPMiddleware authenticates plain HTTP requests with the same tokens
+// as the gRPC API. The token is taken from a bearer header or, for clients
+// that only speak Basic auth, from the password. It is forwarded as incoming
+// gRPC metadata so services can be called as if from a gRPC handler.
+func JWTHTTPMiddleware(secretKey, realm string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenStr := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if _, password, ok := r.BasicAuth(); ok {
+			tokenStr = password
 		}
 
-		tokenStr := strings.TrimPrefix(authHeader[0], "Bearer ")
-		token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, errors.New("unexpected signing method")
-			}
-			return []byte(secretKey), nil
-		})
+		claims, err := parseToken(tokenStr, secretKey)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf("Basic realm=%q", realm))
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
 
-		if err != nil || !token.Valid {
-			return nil, status.Error(codes.Unauthenticated, "invalid token")
+		userID, _ := claims["id"].(string)
+		ctx := metadata.NewIncomingContext(r.Context(), metadata.Pairs("authorization", "Bearer "+tokenStr))
+		ctx = context.WithValue(ctx, userIDKey{}, userID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// UserID returns the id of the user authenticated by JWTHTTPMiddleware.
+func UserID(ctx context.Context) string {
+	userID, _ := ctx.Value(userIDKey{}).(string)
+	return userID
+}
+
+func parseToken(tokenStr, secretKey string) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
 		}
+		return []byte(secretKey), nil
+	})
+	if err != nil || !token.Valid {
+		return nil, errors.New("invalid token")
+	}
 
-		return handler(ctx, req)
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, errors.New("invalid token claims")
 	}
+
+	return claims, nil
 }
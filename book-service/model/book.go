@@ -8,16 +8,39 @@ import (
 )
 
 type Book struct {
-	ID         string     `json:"id" gorm:"type:uuid;default:uuid_generate_v4();primary_key"`
-	Name       string     `json:"name" gorm:"not null"`
-	AuthorID   string     `json:"author_id" gorm:"not null;index"`
-	CategoryID string     `json:"category_id" gorm:"not null;index"`
-	IsBorrowed bool       `json:"is_borrowed" gorm:"not null"`
-	Borrows    int        `json:"borrows" gorm:"not null"`
-	CreatedBy  string     `json:"created_by" gorm:"not null;index"`
-	CreatedAt  time.Time  `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt  time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
-	DeletedAt  *time.Time `json:"deleted_at" gorm:"index"`
+	ID            string     `json:"id" gorm:"type:uuid;default:uuid_generate_v4();primary_key"`
+	Name          string     `json:"name" gorm:"not null"`
+	AuthorID      string     `json:"author_id" gorm:"not null;index"`
+	CategoryID    string     `json:"category_id" gorm:"not null;index"`
+	AuthorName    string     `json:"author_name" gorm:"not null;default:''"`
+	CategoryName  string     `json:"category_name" gorm:"not null;default:''"`
+	ISBN          string     `json:"isbn" gorm:"column:isbn;not null;default:''"`
+	PublisherID   string     `json:"publisher_id" gorm:"not null;default:'';index"`
+	Publisher     string     `json:"publisher" gorm:"not null;default:''"`
+	PublishedYear int        `json:"published_year" gorm:"not null;default:0"`
+	Language      string     `json:"language" gorm:"not null;default:''"`
+	PageCount     int        `json:"page_count" gorm:"not null;default:0"`
+	Edition       string     `json:"edition" gorm:"not null;default:''"`
+	Description   string     `json:"description" gorm:"type:text;not null;default:''"`
+	WorkID        string     `json:"work_id" gorm:"not null;default:'';index"`
+	SeriesID      string     `json:"series_id" gorm:"not null;default:'';index"`
+	SeriesVolume  int        `json:"series_volume" gorm:"not null;default:0"`
+	CoverType     string     `json:"cover_type" gorm:"not null;default:''"`
+	ItemType      string     `json:"item_type" gorm:"not null;default:'physical'"`
+	IsBorrowed    bool       `json:"is_borrowed" gorm:"not null"`
+	Borrows       int        `json:"borrows" gorm:"not null"`
+	AverageRating float64    `json:"average_rating" gorm:"not null;default:0"`
+	RatingCount   int        `json:"rating_count" gorm:"not null;default:0"`
+	CreatedBy     string     `json:"created_by" gorm:"not null;index"`
+	CreatedAt     time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt     *time.Time `json:"deleted_at" gorm:"index"`
+
+	HomeBranchID    string `json:"home_branch_id" gorm:"not null;default:'';index"`
+	CurrentBranchID string `json:"current_branch_id" gorm:"not null;default:'';index"`
+	InTransit       bool   `json:"in_transit" gorm:"not null;default:false"`
+
+	Contributors []*BookContributor `json:"contributors" gorm:"foreignKey:BookID;constraint:OnDelete:CASCADE"`
 }
 
 func (b *Book) BeforeCreate(tx *gorm.DB) (err error) {
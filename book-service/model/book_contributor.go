@@ -0,0 +1,30 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	ContributorAuthor      = "author"
+	ContributorEditor      = "editor"
+	ContributorTranslator  = "translator"
+	ContributorIllustrator = "illustrator"
+)
+
+type BookContributor struct {
+	ID         string    `json:"id" gorm:"type:uuid;default:uuid_generate_v4();primary_key"`
+	BookID     string    `json:"book_id" gorm:"type:uuid;not null;uniqueIndex:idx_book_contributors_book_author_role"`
+	AuthorID   string    `json:"author_id" gorm:"not null;index;uniqueIndex:idx_book_contributors_book_author_role"`
+	Role       string    `json:"role" gorm:"not null;uniqueIndex:idx_book_contributors_book_author_role"`
+	Position   int       `json:"position" gorm:"not null"`
+	AuthorName string    `json:"author_name" gorm:"not null;default:''"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+func (c *BookContributor) BeforeCreate(tx *gorm.DB) (err error) {
+	c.ID = uuid.NewString()
+	return
+}
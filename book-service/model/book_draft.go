@@ -0,0 +1,35 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// BookDraft is a catalog record proposed from an ISBN lookup. It stays out of
+// the catalog until a librarian publishes it as a book.
+type BookDraft struct {
+	ID                string    `gorm:"type:uuid;default:uuid_generate_v4();primary_key"`
+	ISBN              string    `gorm:"uniqueIndex;not null"`
+	Name              string    `gorm:"not null"`
+	AuthorIDs         []string  `gorm:"type:jsonb;serializer:json;not null"`
+	CategoryID        string    `gorm:"not null;default:''"`
+	Publisher         string    `gorm:"not null;default:''"`
+	PublishedYear     int       `gorm:"not null;default:0"`
+	PageCount         int       `gorm:"not null;default:0"`
+	Language          string    `gorm:"not null;default:''"`
+	Description       string    `gorm:"type:text;not null;default:''"`
+	CoverURL          string    `gorm:"not null;default:''"`
+	UnmatchedAuthors  []string  `gorm:"type:jsonb;serializer:json;not null"`
+	UnmatchedSubjects []string  `gorm:"type:jsonb;serializer:json;not null"`
+	Provider          string    `gorm:"not null"`
+	CreatedBy         string    `gorm:"not null;index"`
+	CreatedAt         time.Time `gorm:"autoCreateTime"`
+	UpdatedAt         time.Time `gorm:"autoUpdateTime"`
+}
+
+func (d *BookDraft) BeforeCreate(tx *gorm.DB) (err error) {
+	d.ID = uuid.NewString()
+	return
+}
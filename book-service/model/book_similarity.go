@@ -0,0 +1,10 @@
+package model
+
+import "time"
+
+type BookSimilarity struct {
+	BookID        string    `gorm:"primaryKey"`
+	SimilarBookID string    `gorm:"primaryKey"`
+	Score         float64   `gorm:"not null"`
+	UpdatedAt     time.Time `gorm:"autoUpdateTime"`
+}
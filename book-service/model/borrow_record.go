@@ -13,9 +13,15 @@ type BorrowRecord struct {
 	UserID     string     `gorm:"not null;index"`
 	BorrowedAt time.Time  `gorm:"not null"`
 	ReturnedAt *time.Time `gorm:""`
-	CreatedAt  time.Time  `gorm:"autoCreateTime"`
-	UpdatedAt  time.Time  `gorm:"autoUpdateTime"`
-	DeletedAt  *time.Time `gorm:"index"`
+	LicenseID  string     `gorm:"not null;default:'';index"`
+	ExpiresAt  *time.Time `gorm:"index"`
+	TokenHash  string     `gorm:"not null;default:'';index"`
+
+	BorrowBranchID string     `gorm:"not null;default:'';index"`
+	ReturnBranchID string     `gorm:"not null;default:'';index"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt      time.Time  `gorm:"autoUpdateTime"`
+	DeletedAt      *time.Time `gorm:"index"`
 }
 
 func (b *BorrowRecord) BeforeCreate(tx *gorm.DB) (err error) {
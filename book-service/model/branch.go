@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Branch struct {
+	ID        string     `gorm:"type:uuid;default:uuid_generate_v4();primary_key"`
+	Name      string     `gorm:"not null"`
+	Code      string     `gorm:"not null"`
+	Address   string     `gorm:"type:text;not null;default:''"`
+	Phone     string     `gorm:"not null;default:''"`
+	CreatedBy string     `gorm:"not null;index"`
+	CreatedAt time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt time.Time  `gorm:"autoUpdateTime"`
+	DeletedAt *time.Time `gorm:"index"`
+
+	BookCount int64 `gorm:"->;-:migration"`
+}
+
+func (b *Branch) BeforeCreate(tx *gorm.DB) (err error) {
+	b.ID = uuid.NewString()
+	return
+}
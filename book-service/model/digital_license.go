@@ -0,0 +1,38 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	ItemPhysical = "physical"
+	ItemDigital  = "digital"
+)
+
+// DigitalLicense is one purchased license for a digital book. Concurrency
+// caps simultaneous loans; CheckoutLimit caps loans over the license's
+// lifetime, with zero meaning unlimited.
+type DigitalLicense struct {
+	ID            string     `gorm:"type:uuid;default:uuid_generate_v4();primary_key"`
+	BookID        string     `gorm:"not null;index"`
+	Concurrency   int        `gorm:"not null"`
+	CheckoutLimit int        `gorm:"not null;default:0"`
+	CheckoutsUsed int        `gorm:"not null;default:0"`
+	LoanDays      int        `gorm:"not null"`
+	ExpiresAt     *time.Time `gorm:"index"`
+	Reference     string     `gorm:"not null;default:''"`
+	CreatedBy     string     `gorm:"not null;index"`
+	CreatedAt     time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt     time.Time  `gorm:"autoUpdateTime"`
+	DeletedAt     *time.Time `gorm:"index"`
+
+	ActiveLoans int64 `gorm:"->;-:migration"`
+}
+
+func (l *DigitalLicense) BeforeCreate(tx *gorm.DB) (err error) {
+	l.ID = uuid.NewString()
+	return
+}
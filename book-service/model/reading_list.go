@@ -0,0 +1,62 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	ListReading  = "reading"
+	ListWishlist = "wishlist"
+
+	ListPrivate = "private"
+	ListPublic  = "public"
+	ListLink    = "link"
+)
+
+type ReadingList struct {
+	ID         string     `gorm:"type:uuid;default:uuid_generate_v4();primary_key"`
+	UserID     string     `gorm:"not null;index"`
+	Name       string     `gorm:"not null"`
+	Kind       string     `gorm:"not null;default:'reading'"`
+	Visibility string     `gorm:"not null;default:'private'"`
+	ShareToken string     `gorm:"not null;default:''"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt  time.Time  `gorm:"autoUpdateTime"`
+	DeletedAt  *time.Time `gorm:"index"`
+}
+
+func (l *ReadingList) BeforeCreate(tx *gorm.DB) (err error) {
+	l.ID = uuid.NewString()
+	return
+}
+
+type ReadingListItem struct {
+	ID                  string    `gorm:"type:uuid;default:uuid_generate_v4();primary_key"`
+	ListID              string    `gorm:"not null;uniqueIndex:idx_reading_list_items_list_book"`
+	BookID              string    `gorm:"not null;uniqueIndex:idx_reading_list_items_list_book;index"`
+	Position            int       `gorm:"not null"`
+	NotifyWhenAvailable bool      `gorm:"not null;default:false"`
+	CreatedAt           time.Time `gorm:"autoCreateTime"`
+	UpdatedAt           time.Time `gorm:"autoUpdateTime"`
+}
+
+func (i *ReadingListItem) BeforeCreate(tx *gorm.DB) (err error) {
+	i.ID = uuid.NewString()
+	return
+}
+
+type Notification struct {
+	ID        string    `gorm:"type:uuid;default:uuid_generate_v4();primary_key"`
+	UserID    string    `gorm:"not null;index"`
+	BookID    string    `gorm:"not null"`
+	Message   string    `gorm:"not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (n *Notification) BeforeCreate(tx *gorm.DB) (err error) {
+	n.ID = uuid.NewString()
+	return
+}
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	ReviewVisible = "visible"
+	ReviewFlagged = "flagged"
+	ReviewHidden  = "hidden"
+)
+
+type Review struct {
+	ID             string     `gorm:"type:uuid;default:uuid_generate_v4();primary_key"`
+	BookID         string     `gorm:"not null;index"`
+	UserID         string     `gorm:"not null;index"`
+	Rating         int        `gorm:"not null"`
+	Body           string     `gorm:"not null;default:''"`
+	Status         string     `gorm:"not null;default:'visible'"`
+	ModerationNote string     `gorm:"not null;default:''"`
+	ModeratedBy    string     `gorm:"not null;default:''"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt      time.Time  `gorm:"autoUpdateTime"`
+	DeletedAt      *time.Time `gorm:"index"`
+}
+
+func (r *Review) BeforeCreate(tx *gorm.DB) (err error) {
+	r.ID = uuid.NewString()
+	return
+}
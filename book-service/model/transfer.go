@@ -0,0 +1,73 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	TransferRequested = "requested"
+	TransferInTransit = "in_transit"
+	TransferReceived  = "received"
+	TransferCancelled = "cancelled"
+
+	TransferReasonManual = "manual"
+	TransferReasonReturn = "return"
+	TransferReasonHold   = "hold"
+)
+
+const (
+	HoldWaiting   = "waiting"
+	HoldInTransit = "in_transit"
+	HoldReady     = "ready"
+	HoldFulfilled = "fulfilled"
+	HoldCancelled = "cancelled"
+)
+
+// Transfer moves a physical item between branches. The item is unavailable
+// from the moment the transfer is requested until it is received or
+// cancelled.
+type Transfer struct {
+	ID           string     `gorm:"type:uuid;default:uuid_generate_v4();primary_key"`
+	BookID       string     `gorm:"not null;index"`
+	FromBranchID string     `gorm:"not null;index"`
+	ToBranchID   string     `gorm:"not null;index"`
+	Status       string     `gorm:"not null;index"`
+	Reason       string     `gorm:"not null"`
+	HoldID       string     `gorm:"not null;default:''"`
+	RequestedBy  string     `gorm:"not null;default:''"`
+	DispatchedBy string     `gorm:"not null;default:''"`
+	ReceivedBy   string     `gorm:"not null;default:''"`
+	DispatchedAt *time.Time `gorm:""`
+	ReceivedAt   *time.Time `gorm:""`
+	CreatedAt    time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt    time.Time  `gorm:"autoUpdateTime"`
+
+	BookName string `gorm:"->;-:migration"`
+	BookISBN string `gorm:"column:book_isbn;->;-:migration"`
+}
+
+// Hold is a reader's request to pick up a physical item at a branch. Holds
+// on the same book are served in the order they were placed.
+type Hold struct {
+	ID             string     `gorm:"type:uuid;default:uuid_generate_v4();primary_key"`
+	BookID         string     `gorm:"not null;index"`
+	UserID         string     `gorm:"not null;index"`
+	PickupBranchID string     `gorm:"not null;index"`
+	Status         string     `gorm:"not null;index"`
+	ReadyAt        *time.Time `gorm:""`
+	CreatedAt      time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt      time.Time  `gorm:"autoUpdateTime"`
+}
+
+func (t *Transfer) BeforeCreate(tx *gorm.DB) (err error) {
+	t.ID = uuid.NewString()
+	return
+}
+
+func (h *Hold) BeforeCreate(tx *gorm.DB) (err error) {
+	h.ID = uuid.NewString()
+	return
+}
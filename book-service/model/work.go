@@ -0,0 +1,42 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Work struct {
+	ID          string     `gorm:"type:uuid;default:uuid_generate_v4();primary_key"`
+	Title       string     `gorm:"not null"`
+	Description string     `gorm:"type:text;not null;default:''"`
+	CreatedBy   string     `gorm:"not null;index"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt   time.Time  `gorm:"autoUpdateTime"`
+	DeletedAt   *time.Time `gorm:"index"`
+
+	EditionCount int64 `gorm:"->;-:migration"`
+}
+
+type Series struct {
+	ID          string     `gorm:"type:uuid;default:uuid_generate_v4();primary_key"`
+	Name        string     `gorm:"not null"`
+	Description string     `gorm:"type:text;not null;default:''"`
+	CreatedBy   string     `gorm:"not null;index"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt   time.Time  `gorm:"autoUpdateTime"`
+	DeletedAt   *time.Time `gorm:"index"`
+
+	BookCount int64 `gorm:"->;-:migration"`
+}
+
+func (w *Work) BeforeCreate(tx *gorm.DB) (err error) {
+	w.ID = uuid.NewString()
+	return
+}
+
+func (s *Series) BeforeCreate(tx *gorm.DB) (err error) {
+	s.ID = uuid.NewString()
+	return
+}
@@ -0,0 +1,206 @@
+package opds
+
+import (
+	"encoding/xml"
+	"net/http"
+	"strconv"
+
+	"github.com/shafaalafghany/book-service/model"
+)
+
+const (
+	atomNavigationType  = "application/atom+xml;profile=opds-catalog;kind=navigation"
+	atomAcquisitionType = "application/atom+xml;profile=opds-catalog;kind=acquisition"
+	atomEntryType       = "application/atom+xml;type=entry;profile=opds-catalog"
+	openSearchType      = "application/opensearchdescription+xml"
+
+	relAcquisitionBorrow = "http://opds-spec.org/acquisition/borrow"
+	relImage             = "http://opds-spec.org/image"
+	relThumbnail         = "http://opds-spec.org/image/thumbnail"
+)
+
+type atomFeed struct {
+	XMLName      xml.Name    `xml:"feed"`
+	Xmlns        string      `xml:"xmlns,attr"`
+	XmlnsDC      string      `xml:"xmlns:dc,attr"`
+	XmlnsOPDS    string      `xml:"xmlns:opds,attr"`
+	XmlnsOS      string      `xml:"xmlns:opensearch,attr"`
+	XmlnsThr     string      `xml:"xmlns:thr,attr"`
+	ID           string      `xml:"id"`
+	Title        string      `xml:"title"`
+	Updated      string      `xml:"updated"`
+	Links        []atomLink  `xml:"link"`
+	ItemsPerPage int         `xml:"opensearch:itemsPerPage,omitempty"`
+	Entries      []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	XMLName      xml.Name       `xml:"entry"`
+	Xmlns        string         `xml:"xmlns,attr,omitempty"`
+	XmlnsDC      string         `xml:"xmlns:dc,attr,omitempty"`
+	XmlnsOPDS    string         `xml:"xmlns:opds,attr,omitempty"`
+	Title        string         `xml:"title"`
+	ID           string         `xml:"id"`
+	Updated      string         `xml:"updated"`
+	Authors      []atomPerson   `xml:"author"`
+	Contributors []atomPerson   `xml:"contributor"`
+	Identifier   string         `xml:"dc:identifier,omitempty"`
+	Publisher    string         `xml:"dc:publisher,omitempty"`
+	Issued       string         `xml:"dc:issued,omitempty"`
+	Language     string         `xml:"dc:language,omitempty"`
+	Categories   []atomCategory `xml:"category"`
+	Summary      *atomText      `xml:"summary"`
+	Content      *atomText      `xml:"content"`
+	Links        []atomLink     `xml:"link"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
+
+type atomCategory struct {
+	Term  string `xml:"term,attr"`
+	Label string `xml:"label,attr"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type atomLink struct {
+	Rel          string            `xml:"rel,attr,omitempty"`
+	Href         string            `xml:"href,attr"`
+	Type         string            `xml:"type,attr,omitempty"`
+	Title        string            `xml:"title,attr,omitempty"`
+	Count        int64             `xml:"thr:count,attr,omitempty"`
+	Availability *atomAvailability `xml:"opds:availability"`
+}
+
+type atomAvailability struct {
+	Status string `xml:"status,attr"`
+}
+
+func atomType(kind string) string {
+	switch kind {
+	case kindNavigation:
+		return atomNavigationType
+	case kindEntry:
+		return atomEntryType
+	}
+	return atomAcquisitionType
+}
+
+func writeAtom(w http.ResponseWriter, f *feed) error {
+	if f.kind == kindEntry {
+		entry := toAtomEntry(f.books[0], f.confirmBorrow)
+		entry.Xmlns = "http://www.w3.org/2005/Atom"
+		entry.XmlnsDC = "http://purl.org/dc/terms/"
+		entry.XmlnsOPDS = "http://opds-spec.org/2010/catalog"
+		return writeXML(w, atomEntryType, entry)
+	}
+
+	res := atomFeed{
+		Xmlns:        "http://www.w3.org/2005/Atom",
+		XmlnsDC:      "http://purl.org/dc/terms/",
+		XmlnsOPDS:    "http://opds-spec.org/2010/catalog",
+		XmlnsOS:      "http://a9.com/-/spec/opensearch/1.1/",
+		XmlnsThr:     "http://purl.org/syndication/thread/1.0",
+		ID:           "urn:library:opds" + f.path,
+		Title:        f.title,
+		Updated:      updatedAt(f.lastUpdated()),
+		ItemsPerPage: f.pageSize,
+		Links: []atomLink{
+			{Rel: "self", Href: atomPrefix + f.path, Type: atomType(f.kind)},
+			{Rel: "search", Href: atomPrefix + "/opensearch.xml", Type: openSearchType},
+		},
+	}
+	for _, v := range f.links {
+		res.Links = append(res.Links, atomLink{Rel: v.rel, Href: atomPrefix + v.path, Type: atomType(v.kind)})
+	}
+
+	for _, v := range f.nav {
+		res.Entries = append(res.Entries, atomEntry{
+			Title:   v.title,
+			ID:      "urn:library:opds" + v.path,
+			Updated: res.Updated,
+			Content: &atomText{Type: "text", Value: v.title},
+			Links:   []atomLink{{Rel: "subsection", Href: atomPrefix + v.path, Type: atomType(v.kind), Count: v.count}},
+		})
+	}
+	for _, v := range f.books {
+		res.Entries = append(res.Entries, toAtomEntry(v, false))
+	}
+
+	return writeXML(w, atomType(f.kind), res)
+}
+
+func toAtomEntry(data *model.Book, confirm bool) atomEntry {
+	entry := atomEntry{
+		Title:     data.Name,
+		ID:        "urn:uuid:" + data.ID,
+		Updated:   updatedAt(data.UpdatedAt),
+		Language:  data.Language,
+		Publisher: data.Publisher,
+		Links: []atomLink{
+			{Rel: "alternate", Href: atomPrefix + "/books/" + data.ID, Type: atomEntryType},
+		},
+	}
+
+	for _, v := range authors(data) {
+		person := atomPerson{Name: v.AuthorName}
+		if v.Role == model.ContributorAuthor {
+			person.URI = atomPrefix + "/books?author=" + v.AuthorID
+			entry.Authors = append(entry.Authors, person)
+		} else {
+			entry.Contributors = append(entry.Contributors, person)
+		}
+	}
+
+	if data.ISBN != "" {
+		entry.Identifier = "urn:isbn:" + data.ISBN
+	}
+	if data.PublishedYear > 0 {
+		entry.Issued = strconv.Itoa(data.PublishedYear)
+	}
+	if data.CategoryID != "" {
+		entry.Categories = []atomCategory{{Term: data.CategoryID, Label: data.CategoryName}}
+	}
+	if data.Description != "" {
+		entry.Summary = &atomText{Type: "text", Value: data.Description}
+	}
+
+	if data.CoverType != "" {
+		entry.Links = append(entry.Links,
+			atomLink{Rel: relImage, Href: coverPath(data.ID, "large"), Type: "image/jpeg"},
+			atomLink{Rel: relThumbnail, Href: coverPath(data.ID, "small"), Type: "image/jpeg"},
+		)
+	}
+	if !data.IsBorrowed && !data.InTransit {
+		entry.Links = append(entry.Links, atomLink{
+			Rel:          relAcquisitionBorrow,
+			Href:         atomPrefix + borrowPath(data.ID, confirm),
+			Type:         atomEntryType,
+			Title:        borrowTitle(confirm),
+			Availability: &atomAvailability{Status: "available"},
+		})
+	}
+
+	return entry
+}
+
+func writeXML(w http.ResponseWriter, contentType string, v any) error {
+	w.Header().Set("Content-Type", contentType+";charset=utf-8")
+	if _, err := w.Write([]byte(xml.Header)); err != nil {
+		return err
+	}
+
+	e := xml.NewEncoder(w)
+	e.Indent("", "  ")
+	return e.Encode(v)
+}
+
+func coverPath(id, size string) string {
+	return atomPrefix + "/books/" + id + "/cover/" + size
+}
@@ -0,0 +1,80 @@
+package opds
+
+import (
+	"time"
+
+	"github.com/shafaalafghany/book-service/model"
+)
+
+const (
+	kindNavigation  = "navigation"
+	kindAcquisition = "acquisition"
+	kindEntry       = "entry"
+)
+
+// feed is what a catalog page contains, independent of whether it is served
+// as OPDS 1.2 Atom or OPDS 2.0 JSON. Paths are relative to the catalog root
+// and get the format's prefix when rendered.
+type feed struct {
+	path     string
+	title    string
+	kind     string
+	updated  time.Time
+	links    []link
+	nav      []navItem
+	books    []*model.Book
+	pageSize int
+	// confirmBorrow marks the entry served behind a borrow link. Its own
+	// borrow link points at the URL that lends the book.
+	confirmBorrow bool
+}
+
+type link struct {
+	rel  string
+	path string
+	kind string
+}
+
+type navItem struct {
+	title string
+	path  string
+	kind  string
+	count int64
+}
+
+func (f *feed) lastUpdated() time.Time {
+	updated := f.updated
+	for _, v := range f.books {
+		if v.UpdatedAt.After(updated) {
+			updated = v.UpdatedAt
+		}
+	}
+	if updated.IsZero() {
+		updated = time.Now()
+	}
+	return updated.UTC()
+}
+
+// borrowPath is where an entry's borrow link points. Clients follow
+// acquisition links with GET, so catalog entries lead to a confirmation
+// entry, and only that entry links to the borrow URL, which takes POST.
+func borrowPath(id string, confirm bool) string {
+	if confirm {
+		return "/books/" + id + "/borrow"
+	}
+	return "/books/" + id + "/borrow/confirm"
+}
+
+func borrowTitle(confirm bool) string {
+	if confirm {
+		return "Confirm borrow"
+	}
+	return "Borrow"
+}
+
+func authors(data *model.Book) []*model.BookContributor {
+	if len(data.Contributors) == 0 && data.AuthorID != "" {
+		return []*model.BookContributor{{AuthorID: data.AuthorID, AuthorName: data.AuthorName, Role: model.ContributorAuthor}}
+	}
+	return data.Contributors
+}
@@ -0,0 +1,190 @@
+package opds
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/shafaalafghany/book-service/model"
+)
+
+const (
+	opdsJSONType        = "application/opds+json"
+	opdsPublicationType = "application/opds-publication+json"
+)
+
+type jsonFeed struct {
+	Metadata     jsonFeedMetadata   `json:"metadata"`
+	Links        []jsonLink         `json:"links"`
+	Navigation   []jsonLink         `json:"navigation,omitempty"`
+	Publications *[]jsonPublication `json:"publications,omitempty"`
+}
+
+type jsonFeedMetadata struct {
+	Title        string `json:"title"`
+	Modified     string `json:"modified"`
+	ItemsPerPage int    `json:"itemsPerPage,omitempty"`
+}
+
+type jsonLink struct {
+	Rel        string              `json:"rel,omitempty"`
+	Href       string              `json:"href"`
+	Type       string              `json:"type,omitempty"`
+	Title      string              `json:"title,omitempty"`
+	Templated  bool                `json:"templated,omitempty"`
+	Properties *jsonLinkProperties `json:"properties,omitempty"`
+}
+
+type jsonLinkProperties struct {
+	NumberOfItems int64             `json:"numberOfItems,omitempty"`
+	Availability  *jsonAvailability `json:"availability,omitempty"`
+}
+
+type jsonAvailability struct {
+	State string `json:"state"`
+}
+
+type jsonPublication struct {
+	Metadata jsonPublicationMetadata `json:"metadata"`
+	Links    []jsonLink              `json:"links"`
+	Images   []jsonLink              `json:"images,omitempty"`
+}
+
+type jsonPublicationMetadata struct {
+	Type          string            `json:"@type"`
+	Identifier    string            `json:"identifier"`
+	Title         string            `json:"title"`
+	Author        []jsonContributor `json:"author,omitempty"`
+	Editor        []jsonContributor `json:"editor,omitempty"`
+	Translator    []jsonContributor `json:"translator,omitempty"`
+	Illustrator   []jsonContributor `json:"illustrator,omitempty"`
+	Publisher     string            `json:"publisher,omitempty"`
+	Published     string            `json:"published,omitempty"`
+	Language      string            `json:"language,omitempty"`
+	Modified      string            `json:"modified"`
+	Description   string            `json:"description,omitempty"`
+	NumberOfPages int               `json:"numberOfPages,omitempty"`
+	Subject       []jsonSubject     `json:"subject,omitempty"`
+}
+
+type jsonContributor struct {
+	Name  string     `json:"name"`
+	Links []jsonLink `json:"links,omitempty"`
+}
+
+type jsonSubject struct {
+	Name string `json:"name"`
+	Code string `json:"code"`
+}
+
+func jsonType(kind string) string {
+	if kind == kindEntry {
+		return opdsPublicationType
+	}
+	return opdsJSONType
+}
+
+func writeJSON(w http.ResponseWriter, f *feed) error {
+	if f.kind == kindEntry {
+		return encodeJSON(w, opdsPublicationType, toJSONPublication(f.books[0], f.confirmBorrow))
+	}
+
+	res := jsonFeed{
+		Metadata: jsonFeedMetadata{
+			Title:        f.title,
+			Modified:     updatedAt(f.lastUpdated()),
+			ItemsPerPage: f.pageSize,
+		},
+		Links: []jsonLink{
+			{Rel: "self", Href: jsonPrefix + f.path, Type: opdsJSONType},
+			{Rel: "search", Href: jsonPrefix + "/search{?query}", Type: opdsJSONType, Templated: true},
+		},
+	}
+	for _, v := range f.links {
+		res.Links = append(res.Links, jsonLink{Rel: v.rel, Href: jsonPrefix + v.path, Type: jsonType(v.kind)})
+	}
+
+	for _, v := range f.nav {
+		item := jsonLink{Href: jsonPrefix + v.path, Type: opdsJSONType, Title: v.title}
+		if v.count > 0 {
+			item.Properties = &jsonLinkProperties{NumberOfItems: v.count}
+		}
+		res.Navigation = append(res.Navigation, item)
+	}
+
+	if f.kind == kindAcquisition {
+		publications := make([]jsonPublication, 0, len(f.books))
+		for _, v := range f.books {
+			publications = append(publications, toJSONPublication(v, false))
+		}
+		res.Publications = &publications
+	}
+
+	return encodeJSON(w, opdsJSONType, res)
+}
+
+func toJSONPublication(data *model.Book, confirm bool) jsonPublication {
+	res := jsonPublication{
+		Metadata: jsonPublicationMetadata{
+			Type:          "http://schema.org/Book",
+			Identifier:    "urn:uuid:" + data.ID,
+			Title:         data.Name,
+			Publisher:     data.Publisher,
+			Language:      data.Language,
+			Modified:      updatedAt(data.UpdatedAt),
+			Description:   data.Description,
+			NumberOfPages: data.PageCount,
+		},
+		Links: []jsonLink{
+			{Rel: "self", Href: jsonPrefix + "/books/" + data.ID, Type: opdsPublicationType},
+		},
+	}
+
+	if data.ISBN != "" {
+		res.Metadata.Identifier = "urn:isbn:" + data.ISBN
+	}
+	if data.PublishedYear > 0 {
+		res.Metadata.Published = strconv.Itoa(data.PublishedYear)
+	}
+	if data.CategoryID != "" {
+		res.Metadata.Subject = []jsonSubject{{Name: data.CategoryName, Code: data.CategoryID}}
+	}
+
+	for _, v := range authors(data) {
+		contributor := jsonContributor{Name: v.AuthorName}
+		switch v.Role {
+		case model.ContributorAuthor:
+			contributor.Links = []jsonLink{{Href: jsonPrefix + "/books?author=" + v.AuthorID, Type: opdsJSONType}}
+			res.Metadata.Author = append(res.Metadata.Author, contributor)
+		case model.ContributorEditor:
+			res.Metadata.Editor = append(res.Metadata.Editor, contributor)
+		case model.ContributorTranslator:
+			res.Metadata.Translator = append(res.Metadata.Translator, contributor)
+		case model.ContributorIllustrator:
+			res.Metadata.Illustrator = append(res.Metadata.Illustrator, contributor)
+		}
+	}
+
+	if data.CoverType != "" {
+		res.Images = []jsonLink{
+			{Href: coverPath(data.ID, "large"), Type: "image/jpeg"},
+			{Href: coverPath(data.ID, "small"), Type: "image/jpeg"},
+		}
+	}
+	if !data.IsBorrowed && !data.InTransit {
+		res.Links = append(res.Links, jsonLink{
+			Rel:        relAcquisitionBorrow,
+			Href:       jsonPrefix + borrowPath(data.ID, confirm),
+			Type:       opdsPublicationType,
+			Title:      borrowTitle(confirm),
+			Properties: &jsonLinkProperties{Availability: &jsonAvailability{State: "available"}},
+		})
+	}
+
+	return res
+}
+
+func encodeJSON(w http.ResponseWriter, contentType string, v any) error {
+	w.Header().Set("Content-Type", contentType)
+	return json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,403 @@
+// Package opds serves the catalog to e-reader apps as OPDS 1.2 (Atom) under
+// /opds and OPDS 2.0 (JSON) under /opds/v2.
+package opds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shafaalafghany/book-service/middleware"
+	"github.com/shafaalafghany/book-service/model"
+	"github.com/shafaalafghany/book-service/repository"
+	"github.com/shafaalafghany/book-service/service"
+	"gitlab.com/shafaalafghany/synapsis-common/pagination"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/book"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	atomPrefix = "/opds"
+	jsonPrefix = "/opds/v2"
+
+	catalogTitle = "Library catalog"
+	pageSize     = 50
+)
+
+var coverSizes = map[string]book.CoverSize{
+	"small":    book.CoverSize_COVER_SMALL,
+	"medium":   book.CoverSize_COVER_MEDIUM,
+	"large":    book.CoverSize_COVER_LARGE,
+	"original": book.CoverSize_COVER_ORIGINAL,
+}
+
+var bookSorts = map[string]struct {
+	title     string
+	sortBy    string
+	sortOrder string
+}{
+	"":        {"All books", "name", "asc"},
+	"new":     {"New arrivals", "created_at", "desc"},
+	"popular": {"Most borrowed", "borrows", "desc"},
+}
+
+type Server struct {
+	repo   repository.BookRepositoryInterface
+	books  service.BookServiceInterface
+	covers service.CoverServiceInterface
+	log    *zap.Logger
+	mux    *http.ServeMux
+}
+
+func NewServer(repo repository.BookRepositoryInterface, books service.BookServiceInterface, covers service.CoverServiceInterface, log *zap.Logger, secretKey string) http.Handler {
+	s := &Server{
+		repo:   repo,
+		books:  books,
+		covers: covers,
+		log:    log,
+		mux:    http.NewServeMux(),
+	}
+
+	s.handleFeed(http.MethodGet, "", s.root)
+	s.handleFeed(http.MethodGet, "/categories", s.categories)
+	s.handleFeed(http.MethodGet, "/authors", s.authors)
+	s.handleFeed(http.MethodGet, "/books", s.bookList)
+	s.handleFeed(http.MethodGet, "/search", s.search)
+	s.handleFeed(http.MethodGet, "/books/{id}", s.entry)
+	s.handleFeed(http.MethodGet, "/books/{id}/borrow/confirm", s.confirmBorrow)
+	s.handleFeed(http.MethodPost, "/books/{id}/borrow", s.borrow)
+	s.mux.HandleFunc("GET "+atomPrefix+"/opensearch.xml", s.openSearch)
+	s.mux.HandleFunc("GET "+atomPrefix+"/books/{id}/cover/{size}", s.cover)
+
+	return middleware.JWTHTTPMiddleware(secretKey, catalogTitle, s.mux)
+}
+
+// handleFeed registers a page in both formats.
+func (s *Server) handleFeed(method, path string, build func(*http.Request) (*feed, error)) {
+	for _, prefix := range []string{atomPrefix, jsonPrefix} {
+		render := writeAtom
+		if prefix == jsonPrefix {
+			render = writeJSON
+		}
+
+		handler := func(w http.ResponseWriter, r *http.Request) {
+			f, err := build(r)
+			if err != nil {
+				s.writeError(w, err)
+				return
+			}
+			if err := render(w, f); err != nil {
+				s.log.Error("failed to write opds feed", zap.Error(err))
+			}
+		}
+
+		s.mux.HandleFunc(method+" "+prefix+path, handler)
+	}
+}
+
+func (s *Server) root(r *http.Request) (*feed, error) {
+	return &feed{
+		path:  "",
+		title: catalogTitle,
+		kind:  kindNavigation,
+		links: []link{{rel: "start", path: "", kind: kindNavigation}},
+		nav: []navItem{
+			{title: bookSorts["new"].title, path: "/books?sort=new", kind: kindAcquisition},
+			{title: bookSorts["popular"].title, path: "/books?sort=popular", kind: kindAcquisition},
+			{title: bookSorts[""].title, path: "/books", kind: kindAcquisition},
+			{title: "By category", path: "/categories", kind: kindNavigation},
+			{title: "By author", path: "/authors", kind: kindNavigation},
+		},
+	}, nil
+}
+
+func (s *Server) categories(r *http.Request) (*feed, error) {
+	return s.facets(r, "/categories", "Categories", "category", s.repo.CategoryFacets)
+}
+
+func (s *Server) authors(r *http.Request) (*feed, error) {
+	return s.facets(r, "/authors", "Authors", "author", s.repo.AuthorFacets)
+}
+
+func (s *Server) facets(r *http.Request, path, title, param string, list func(context.Context, int, int) ([]*repository.BookFacet, error)) (*feed, error) {
+	page := 1
+	if v := r.URL.Query().Get("page"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return nil, status.Error(codes.InvalidArgument, "page must be a positive number")
+		}
+		page = n
+	}
+
+	facets, err := list(r.Context(), (page-1)*pageSize, pageSize+1)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	f := &feed{
+		path:     pagePath(path, page),
+		title:    title,
+		kind:     kindNavigation,
+		links:    []link{{rel: "start", path: "", kind: kindNavigation}, {rel: "up", path: "", kind: kindNavigation}},
+		pageSize: pageSize,
+	}
+	if len(facets) > pageSize {
+		facets = facets[:pageSize]
+		f.links = append(f.links, link{rel: "next", path: pagePath(path, page+1), kind: kindNavigation})
+	}
+
+	for _, v := range facets {
+		f.nav = append(f.nav, navItem{
+			title: v.Name,
+			path:  "/books?" + url.Values{param: {v.ID}}.Encode(),
+			kind:  kindAcquisition,
+			count: v.Count,
+		})
+	}
+
+	return f, nil
+}
+
+func (s *Server) bookList(r *http.Request) (*feed, error) {
+	query := r.URL.Query()
+	sort, ok := bookSorts[query.Get("sort")]
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "sort must be new or popular")
+	}
+
+	filter := repository.BookFilter{}
+	title := sort.title
+	if v := query.Get("category"); v != "" {
+		filter.CategoryIDs = []string{v}
+	}
+	if v := query.Get("author"); v != "" {
+		filter.ContributorIDs = []string{v}
+		filter.ContributorRole = model.ContributorAuthor
+	}
+
+	page, err := s.repo.Get(r.Context(), filter, pagination.ListOptions{
+		PageSize:  pageSize,
+		PageToken: query.Get("page_token"),
+		SortBy:    sort.sortBy,
+		SortOrder: sort.sortOrder,
+	})
+	if err != nil {
+		if errors.Is(err, pagination.ErrInvalidPageToken) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if len(page.Books) > 0 {
+		first := page.Books[0]
+		if query.Get("category") != "" {
+			title = first.CategoryName
+		}
+		if id := query.Get("author"); id != "" {
+			for _, v := range first.Contributors {
+				if v.AuthorID == id {
+					title = v.AuthorName
+				}
+			}
+		}
+	}
+
+	query.Del("page_token")
+	path := withQuery("/books", query)
+	f := &feed{
+		path:     withQuery("/books", r.URL.Query()),
+		title:    title,
+		kind:     kindAcquisition,
+		links:    []link{{rel: "start", path: "", kind: kindNavigation}, {rel: "first", path: path, kind: kindAcquisition}},
+		books:    page.Books,
+		pageSize: pageSize,
+	}
+	if page.NextPageToken != "" {
+		query.Set("page_token", page.NextPageToken)
+		f.links = append(f.links, link{rel: "next", path: withQuery("/books", query), kind: kindAcquisition})
+	}
+
+	return f, nil
+}
+
+func (s *Server) search(r *http.Request) (*feed, error) {
+	query := r.URL.Query()
+	q := query.Get("q")
+	if q == "" {
+		q = query.Get("query")
+	}
+	if strings.TrimSpace(q) == "" {
+		return nil, status.Error(codes.InvalidArgument, "q cannot be empty")
+	}
+
+	result, err := s.repo.Search(r.Context(), repository.BookFilter{}, pagination.ListOptions{
+		Search:    q,
+		PageSize:  pageSize,
+		PageToken: query.Get("page_token"),
+	})
+	if err != nil {
+		if errors.Is(err, pagination.ErrInvalidPageToken) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	f := &feed{
+		path:     withQuery("/search", query),
+		title:    fmt.Sprintf("Search results for %q", q),
+		kind:     kindAcquisition,
+		links:    []link{{rel: "start", path: "", kind: kindNavigation}},
+		pageSize: pageSize,
+	}
+	for _, v := range result.Hits {
+		f.books = append(f.books, &v.Book)
+	}
+	if result.NextPageToken != "" {
+		next := url.Values{"q": {q}, "page_token": {result.NextPageToken}}
+		f.links = append(f.links, link{rel: "next", path: withQuery("/search", next), kind: kindAcquisition})
+	}
+
+	return f, nil
+}
+
+func (s *Server) entry(r *http.Request) (*feed, error) {
+	data, err := s.repo.GetById(r.Context(), &model.Book{ID: r.PathValue("id")})
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "book not found")
+	}
+
+	return &feed{path: "/books/" + data.ID, title: data.Name, kind: kindEntry, books: []*model.Book{data}}, nil
+}
+
+// confirmBorrow is where catalog entries' borrow links lead. It only shows
+// the entry, with a borrow link to the URL that lends the book.
+func (s *Server) confirmBorrow(r *http.Request) (*feed, error) {
+	f, err := s.entry(r)
+	if err != nil {
+		return nil, err
+	}
+
+	f.path = borrowPath(f.books[0].ID, false)
+	f.confirmBorrow = true
+	return f, nil
+}
+
+// borrow lends the book to the authenticated user through the regular
+// BorrowBook flow and returns the updated entry.
+func (s *Server) borrow(r *http.Request) (*feed, error) {
+	// Browsers attach saved Basic credentials to cross-site form posts, so
+	// only requests from the catalog's own origin or from apps are accepted.
+	if origin := r.Header.Get("Origin"); origin != "" && origin != baseURL(r) {
+		return nil, status.Error(codes.PermissionDenied, "cross-origin borrow requests are not allowed")
+	}
+
+	_, err := s.books.BorrowBook(r.Context(), &book.BorrowRecord{
+		BookId: r.PathValue("id"),
+		UserId: middleware.UserID(r.Context()),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.entry(r)
+}
+
+func (s *Server) cover(w http.ResponseWriter, r *http.Request) {
+	size, ok := coverSizes[r.PathValue("size")]
+	if !ok {
+		http.Error(w, "unknown cover size", http.StatusNotFound)
+		return
+	}
+
+	blob, contentType, err := s.covers.OpenCover(r.Context(), r.PathValue("id"), size)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+	defer blob.Close()
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "private, max-age=3600")
+	if _, err := io.Copy(w, blob); err != nil {
+		s.log.Error("failed to write cover", zap.Error(err))
+	}
+}
+
+func (s *Server) openSearch(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", openSearchType)
+	fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<OpenSearchDescription xmlns="http://a9.com/-/spec/opensearch/1.1/">
+  <ShortName>%s</ShortName>
+  <Description>Search the library catalog by title, author or category</Description>
+  <InputEncoding>UTF-8</InputEncoding>
+  <OutputEncoding>UTF-8</OutputEncoding>
+  <Url type="%s" template="%s%s/search?q={searchTerms}"/>
+</OpenSearchDescription>
+`, catalogTitle, atomAcquisitionType, baseURL(r), atomPrefix)
+}
+
+func (s *Server) writeError(w http.ResponseWriter, err error) {
+	st := status.Convert(err)
+	code := http.StatusInternalServerError
+	switch st.Code() {
+	case codes.InvalidArgument:
+		code = http.StatusBadRequest
+	case codes.NotFound:
+		code = http.StatusNotFound
+	case codes.AlreadyExists, codes.FailedPrecondition:
+		code = http.StatusConflict
+	case codes.Unauthenticated:
+		code = http.StatusUnauthorized
+	case codes.PermissionDenied:
+		code = http.StatusForbidden
+	default:
+		s.log.Error("opds request failed", zap.Error(err))
+	}
+
+	http.Error(w, st.Message(), code)
+}
+
+// baseURL is the absolute URL of the server as the client reached it,
+// honouring a reverse proxy's forwarded headers.
+func baseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if v := r.Header.Get("X-Forwarded-Proto"); v != "" {
+		scheme = v
+	}
+
+	host := r.Host
+	if v := r.Header.Get("X-Forwarded-Host"); v != "" {
+		host = v
+	}
+
+	return scheme + "://" + host
+}
+
+func pagePath(path string, page int) string {
+	if page == 1 {
+		return path
+	}
+	return fmt.Sprintf("%s?page=%d", path, page)
+}
+
+func withQuery(path string, query url.Values) string {
+	if len(query) == 0 {
+		return path
+	}
+	return path + "?" + query.Encode()
+}
+
+func updatedAt(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,246 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/shafaalafghany/book-service/model"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const expireLoansBatch = 500
+
+var (
+	ErrNoLicenseAvailable = errors.New("no license is available for this book")
+	ErrAlreadyOnLoan      = errors.New("book is already on loan to this user")
+)
+
+const activeLoans = "(SELECT COUNT(*) FROM borrow_records WHERE borrow_records.license_id = digital_licenses.id::text AND borrow_records.returned_at IS NULL AND borrow_records.expires_at > ?) AS active_loans"
+
+func (r *BookRepository) CreateLicense(ctx context.Context, data *model.DigitalLicense) error {
+	if err := r.db.Create(data).Error; err != nil {
+		return err
+	}
+
+	return r.invalidateBook(ctx, data.BookID)
+}
+
+func (r *BookRepository) GetLicenses(ctx context.Context, bookId string) ([]*model.DigitalLicense, error) {
+	var licenses []*model.DigitalLicense
+	if err := r.db.Model(&model.DigitalLicense{}).
+		Select("digital_licenses.*, "+activeLoans, time.Now()).
+		Where("book_id = ? AND deleted_at IS NULL", bookId).
+		Order("created_at ASC").
+		Find(&licenses).Error; err != nil {
+		return nil, err
+	}
+
+	return licenses, nil
+}
+
+func (r *BookRepository) GetLicenseById(ctx context.Context, id string) (*model.DigitalLicense, error) {
+	var license model.DigitalLicense
+	if err := r.db.Where("id = ? AND deleted_at IS NULL", id).First(&license).Error; err != nil {
+		return nil, err
+	}
+
+	return &license, nil
+}
+
+// RevokeLicense deletes the license and ends the loans still open on it, so
+// their access tokens stop working at once.
+func (r *BookRepository) RevokeLicense(ctx context.Context, id string) error {
+	now := time.Now()
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.DigitalLicense{}).Where("id = ? AND deleted_at IS NULL", id).Update("deleted_at", now).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&model.BorrowRecord{}).
+			Where("license_id = ? AND returned_at IS NULL", id).
+			Update("returned_at", now).Error
+	})
+}
+
+// ActiveLoans counts unreturned loans of a book, digital or physical.
+func (r *BookRepository) ActiveLoans(ctx context.Context, bookId string) (int64, error) {
+	var count int64
+	if err := r.db.Model(&model.BorrowRecord{}).
+		Where("book_id = ? AND returned_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", bookId, time.Now()).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// BorrowDigital lends a digital book on the first license with a free slot,
+// preferring licenses that expire soonest. The book row is locked so
+// concurrent borrows of the same title are counted one at a time. The loan
+// ends after the license's loan period, or when the license expires if that
+// is sooner.
+func (r *BookRepository) BorrowDigital(ctx context.Context, data *model.BorrowRecord) error {
+	now := data.BorrowedAt
+
+	var book model.Book
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&book, "id = ? AND deleted_at IS NULL", data.BookID).Error; err != nil {
+			return err
+		}
+
+		var onLoan int64
+		if err := tx.Model(&model.BorrowRecord{}).
+			Where("book_id = ? AND user_id = ? AND returned_at IS NULL AND expires_at > ?", data.BookID, data.UserID, now).
+			Count(&onLoan).Error; err != nil {
+			return err
+		}
+		if onLoan > 0 {
+			return ErrAlreadyOnLoan
+		}
+
+		var licenses []*model.DigitalLicense
+		if err := tx.Model(&model.DigitalLicense{}).
+			Select("digital_licenses.*, "+activeLoans, now).
+			Where("book_id = ? AND deleted_at IS NULL", data.BookID).
+			Where("expires_at IS NULL OR expires_at > ?", now).
+			Where("checkout_limit = 0 OR checkouts_used < checkout_limit").
+			Order("expires_at ASC NULLS LAST, created_at ASC").
+			Find(&licenses).Error; err != nil {
+			return err
+		}
+
+		var license *model.DigitalLicense
+		for _, v := range licenses {
+			if v.ActiveLoans < int64(v.Concurrency) {
+				license = v
+				break
+			}
+		}
+		if license == nil {
+			return ErrNoLicenseAvailable
+		}
+
+		expiresAt := now.AddDate(0, 0, license.LoanDays)
+		if license.ExpiresAt != nil && license.ExpiresAt.Before(expiresAt) {
+			expiresAt = *license.ExpiresAt
+		}
+		data.LicenseID = license.ID
+		data.ExpiresAt = &expiresAt
+
+		if err := tx.Create(data).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&model.DigitalLicense{}).Where("id = ?", license.ID).
+			UpdateColumn("checkouts_used", gorm.Expr("checkouts_used + 1")).Error; err != nil {
+			return err
+		}
+
+		book.Borrows++
+		return tx.Model(&model.Book{}).Where("id = ?", book.ID).UpdateColumn("borrows", book.Borrows).Error
+	}); err != nil {
+		return err
+	}
+
+	if err := r.recordTrending(ctx, &book, now); err != nil {
+		r.logger.Warn("failed to record trending borrow", zap.String("book_id", book.ID), zap.Error(err))
+	}
+
+	return r.invalidateBook(ctx, book.ID)
+}
+
+// ReturnDigital ends a user's loan early, which frees its license slot and
+// invalidates the access token.
+func (r *BookRepository) ReturnDigital(ctx context.Context, data *model.BorrowRecord) error {
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+		var borrowRecord model.BorrowRecord
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&borrowRecord, "book_id = ? AND user_id = ? AND returned_at IS NULL AND expires_at > ?", data.BookID, data.UserID, time.Now()).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&borrowRecord).Update("returned_at", time.Now()).Error; err != nil {
+			return err
+		}
+
+		var book model.Book
+		if err := tx.First(&book, "id = ?", data.BookID).Error; err != nil {
+			return err
+		}
+
+		return notifyAvailable(tx, &book)
+	}); err != nil {
+		return err
+	}
+
+	return r.invalidateBook(ctx, data.BookID)
+}
+
+// GetLoanByToken finds the open loan behind an access token. Loans on a
+// revoked license no longer grant access.
+func (r *BookRepository) GetLoanByToken(ctx context.Context, tokenHash string) (*model.BorrowRecord, error) {
+	var borrowRecord model.BorrowRecord
+	if err := r.db.Joins("JOIN digital_licenses ON digital_licenses.id::text = borrow_records.license_id AND digital_licenses.deleted_at IS NULL").
+		Where("borrow_records.token_hash = ? AND borrow_records.returned_at IS NULL AND borrow_records.expires_at > ?", tokenHash, time.Now()).
+		First(&borrowRecord).Error; err != nil {
+		return nil, err
+	}
+
+	return &borrowRecord, nil
+}
+
+// ExpireLoans returns digital loans whose period has ended, stamping them
+// with their expiry time, and notifies readers waiting for those books.
+func (r *BookRepository) ExpireLoans(ctx context.Context, now time.Time) (int64, error) {
+	var expired []*model.BorrowRecord
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("license_id <> '' AND returned_at IS NULL AND expires_at <= ?", now).
+			Limit(expireLoansBatch).
+			Find(&expired).Error; err != nil {
+			return err
+		}
+		if len(expired) == 0 {
+			return nil
+		}
+
+		ids := make([]string, 0, len(expired))
+		for _, v := range expired {
+			ids = append(ids, v.ID)
+		}
+		if err := tx.Model(&model.BorrowRecord{}).Where("id IN ?", ids).
+			Update("returned_at", gorm.Expr("expires_at")).Error; err != nil {
+			return err
+		}
+
+		bookIds := make([]string, 0, len(expired))
+		for _, v := range expired {
+			bookIds = append(bookIds, v.BookID)
+		}
+		var books []*model.Book
+		if err := tx.Where("id IN ?", bookIds).Find(&books).Error; err != nil {
+			return err
+		}
+		for _, v := range books {
+			if err := notifyAvailable(tx, v); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+
+	for _, v := range expired {
+		if err := r.invalidateBook(ctx, v.BookID); err != nil {
+			r.logger.Warn("failed to invalidate book cache", zap.String("book_id", v.BookID), zap.Error(err))
+		}
+	}
+
+	return int64(len(expired)), nil
+}
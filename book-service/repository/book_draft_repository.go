@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"github.com/shafaalafghany/book-service/model"
+	"gitlab.com/shafaalafghany/synapsis-common/pagination"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type BookDraftRepositoryInterface interface {
+	Create(*model.BookDraft) error
+	GetById(string) (*model.BookDraft, error)
+	GetByISBN(string) (*model.BookDraft, error)
+	Get(pagination.ListOptions) (*BookDraftPage, error)
+	Delete(string) error
+}
+
+type BookDraftRepository struct {
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+type BookDraftPage struct {
+	Drafts        []*model.BookDraft
+	NextPageToken string
+}
+
+func NewBookDraftRepository(db *gorm.DB, logger *zap.Logger) BookDraftRepositoryInterface {
+	return &BookDraftRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *BookDraftRepository) Create(data *model.BookDraft) error {
+	if err := r.db.Create(data).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *BookDraftRepository) GetById(id string) (*model.BookDraft, error) {
+	var draft model.BookDraft
+	if err := r.db.Where("id = ?", id).First(&draft).Error; err != nil {
+		return nil, err
+	}
+
+	return &draft, nil
+}
+
+func (r *BookDraftRepository) GetByISBN(isbn string) (*model.BookDraft, error) {
+	var draft model.BookDraft
+	if err := r.db.Where("isbn = ?", isbn).First(&draft).Error; err != nil {
+		return nil, err
+	}
+
+	return &draft, nil
+}
+
+func (r *BookDraftRepository) Get(opts pagination.ListOptions) (*BookDraftPage, error) {
+	if err := opts.Normalize(createdAtSortColumns, "created_at", "desc"); err != nil {
+		return nil, err
+	}
+
+	base := r.db.Model(&model.BookDraft{})
+
+	if opts.Search != "" {
+		base.Where("name ILIKE ? OR isbn = ?", "%"+opts.Search+"%", opts.Search)
+	}
+
+	query, err := pagination.Paginate(base, opts, createdAtSortColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &BookDraftPage{}
+	if err := query.Find(&page.Drafts).Error; err != nil {
+		return nil, err
+	}
+
+	if len(page.Drafts) > opts.PageSize {
+		page.Drafts = page.Drafts[:opts.PageSize]
+		last := page.Drafts[len(page.Drafts)-1]
+		page.NextPageToken = pagination.EncodePageToken(opts, last.CreatedAt, last.ID)
+	}
+
+	return page, nil
+}
+
+func (r *BookDraftRepository) Delete(id string) error {
+	if err := r.db.Where("id = ?", id).Delete(&model.BookDraft{}).Error; err != nil {
+		return err
+	}
+	return nil
+}
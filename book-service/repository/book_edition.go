@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/shafaalafghany/book-service/model"
+	"gitlab.com/shafaalafghany/synapsis-common/pagination"
+	"gorm.io/gorm"
+)
+
+const editionGroup = "COALESCE(NULLIF(work_id, ''), id::text)"
+
+var editionSortColumns = map[string]pagination.SortKind{
+	"name":           pagination.SortString,
+	"published_year": pagination.SortInt,
+	"created_at":     pagination.SortTime,
+}
+
+var seriesSortColumns = map[string]pagination.SortKind{
+	"series_volume": pagination.SortInt,
+}
+
+func (r *BookRepository) GetEditions(ctx context.Context, workId string, opts pagination.ListOptions) (*BookPage, error) {
+	if err := opts.Normalize(editionSortColumns, "published_year", "asc"); err != nil {
+		return nil, err
+	}
+
+	base := r.db.WithContext(ctx).Model(&model.Book{}).Where("work_id = ? AND deleted_at IS NULL", workId)
+
+	return r.page(base, opts, editionSortColumns)
+}
+
+func (r *BookRepository) GetSeriesBooks(ctx context.Context, seriesId string, opts pagination.ListOptions) (*BookPage, error) {
+	if err := opts.Normalize(seriesSortColumns, "series_volume", "asc"); err != nil {
+		return nil, err
+	}
+
+	base := r.db.WithContext(ctx).Model(&model.Book{}).Where("series_id = ? AND deleted_at IS NULL", seriesId)
+
+	return r.page(base, opts, seriesSortColumns)
+}
+
+// collapseEditions keeps only the best-ranked edition of each work from a
+// query that selects a rank column, so a novel with five editions shows up
+// once. Books without a work are their own group. The wrapping queries share
+// ranked's connection, so a caller inside a transaction stays inside it.
+func (r *BookRepository) collapseEditions(ranked *gorm.DB) *gorm.DB {
+	db := ranked.Session(&gorm.Session{NewDB: true})
+	numbered := db.Table("(?) AS ranked", ranked).
+		Select("ranked.*, ROW_NUMBER() OVER (PARTITION BY " + editionGroup + " ORDER BY rank DESC, borrows DESC, id) AS edition_rank")
+
+	return db.Table("(?) AS books", numbered).Where("edition_rank = 1")
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/shafaalafghany/book-service/model"
+)
+
+// BookFacet is a category or author with the number of books in the catalog.
+type BookFacet struct {
+	ID    string
+	Name  string
+	Count int64
+}
+
+// CategoryFacets lists the categories that have books, by name.
+func (r *BookRepository) CategoryFacets(ctx context.Context, offset, limit int) ([]*BookFacet, error) {
+	var facets []*BookFacet
+	err := r.db.WithContext(ctx).Model(&model.Book{}).
+		Select("category_id AS id, MAX(category_name) AS name, COUNT(*) AS count").
+		Where("deleted_at IS NULL").
+		Group("category_id").
+		Order("name, id").
+		Offset(offset).Limit(limit).
+		Scan(&facets).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return facets, nil
+}
+
+// AuthorFacets lists the authors credited with the author role on at least
+// one book, by name.
+func (r *BookRepository) AuthorFacets(ctx context.Context, offset, limit int) ([]*BookFacet, error) {
+	var facets []*BookFacet
+	err := r.db.WithContext(ctx).Table("book_contributors").
+		Select("book_contributors.author_id AS id, MAX(book_contributors.author_name) AS name, COUNT(DISTINCT books.id) AS count").
+		Joins("JOIN books ON books.id = book_contributors.book_id AND books.deleted_at IS NULL").
+		Where("book_contributors.role = ?", model.ContributorAuthor).
+		Group("book_contributors.author_id").
+		Order("name, id").
+		Offset(offset).Limit(limit).
+		Scan(&facets).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return facets, nil
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gitlab.com/shafaalafghany/synapsis-common/pagination"
+	"gorm.io/gorm"
+)
+
+type BookFilter struct {
+	AuthorIDs     []string   `json:"author_ids,omitempty"`
+	CategoryIDs   []string   `json:"category_ids,omitempty"`
+	IsBorrowed    *bool      `json:"is_borrowed,omitempty"`
+	CreatedAfter  *time.Time `json:"created_after,omitempty"`
+	CreatedBefore *time.Time `json:"created_before,omitempty"`
+	CreatedBy     string     `json:"created_by,omitempty"`
+
+	ContributorIDs  []string `json:"contributor_ids,omitempty"`
+	ContributorRole string   `json:"contributor_role,omitempty"`
+
+	BranchIDs []string `json:"branch_ids,omitempty"`
+	Available *bool    `json:"available,omitempty"`
+}
+
+func (f BookFilter) normalize() BookFilter {
+	f.AuthorIDs = uniqueSorted(f.AuthorIDs)
+	f.CategoryIDs = uniqueSorted(f.CategoryIDs)
+	f.CreatedBy = strings.TrimSpace(f.CreatedBy)
+	f.ContributorIDs = uniqueSorted(f.ContributorIDs)
+	f.ContributorRole = strings.TrimSpace(f.ContributorRole)
+	f.BranchIDs = uniqueSorted(f.BranchIDs)
+
+	if f.CreatedAfter != nil {
+		after := f.CreatedAfter.UTC()
+		f.CreatedAfter = &after
+	}
+	if f.CreatedBefore != nil {
+		before := f.CreatedBefore.UTC()
+		f.CreatedBefore = &before
+	}
+
+	return f
+}
+
+func (f BookFilter) apply(base *gorm.DB) *gorm.DB {
+	if len(f.AuthorIDs) > 0 {
+		base = base.Where("author_id IN ?", f.AuthorIDs)
+	}
+	if len(f.CategoryIDs) > 0 {
+		base = base.Where("category_id IN ?", f.CategoryIDs)
+	}
+	if f.IsBorrowed != nil {
+		base = base.Where("is_borrowed = ?", *f.IsBorrowed)
+	}
+	if len(f.BranchIDs) > 0 {
+		base = base.Where("current_branch_id IN ?", f.BranchIDs)
+	}
+	if f.Available != nil {
+		if *f.Available {
+			base = base.Where("is_borrowed = false AND in_transit = false")
+		} else {
+			base = base.Where("(is_borrowed = true OR in_transit = true)")
+		}
+	}
+	if f.CreatedAfter != nil {
+		base = base.Where("created_at >= ?", *f.CreatedAfter)
+	}
+	if f.CreatedBefore != nil {
+		base = base.Where("created_at < ?", *f.CreatedBefore)
+	}
+	if f.CreatedBy != "" {
+		base = base.Where("created_by = ?", f.CreatedBy)
+	}
+	if len(f.ContributorIDs) > 0 || f.ContributorRole != "" {
+		contributors := "SELECT 1 FROM book_contributors WHERE book_contributors.book_id = books.id"
+		var args []interface{}
+		if len(f.ContributorIDs) > 0 {
+			contributors += " AND book_contributors.author_id IN ?"
+			args = append(args, f.ContributorIDs)
+		}
+		if f.ContributorRole != "" {
+			contributors += " AND book_contributors.role = ?"
+			args = append(args, f.ContributorRole)
+		}
+		base = base.Where("EXISTS ("+contributors+")", args...)
+	}
+
+	return base
+}
+
+// bookListKey derives the cache key from the normalized filter and paging
+// options, so equivalent requests (e.g. the same IDs in another order) share
+// one cache entry. The key carries the list cache generation; bumping it
+// retires every cached list at once.
+func bookListKey(generation int64, filter BookFilter, opts pagination.ListOptions) (string, error) {
+	raw, err := json.Marshal(struct {
+		Filter BookFilter             `json:"filter"`
+		Opts   pagination.ListOptions `json:"opts"`
+	}{filter, opts})
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(raw)
+	return fmt.Sprintf("books:%d:%s", generation, hex.EncodeToString(sum[:])), nil
+}
+
+func uniqueSorted(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]bool, len(values))
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" || seen[v] {
+			continue
+		}
+		seen[v] = true
+		result = append(result, v)
+	}
+
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shafaalafghany/book-service/model"
+	"gorm.io/gorm"
+)
+
+// AuthorIdsAfter pages through the ids of every author a book points to,
+// including through its contributors, in id order.
+func (r *BookRepository) AuthorIdsAfter(ctx context.Context, after string, limit int) ([]string, error) {
+	ids := []string{}
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT author_id FROM (
+			SELECT author_id FROM books WHERE author_id <> ''
+			UNION
+			SELECT author_id FROM book_contributors
+		) AS authors
+		WHERE author_id > ? ORDER BY author_id LIMIT ?`, after, limit).Scan(&ids).Error
+
+	return ids, err
+}
+
+// CategoryIdsAfter pages through the ids of every category a book points to,
+// in id order.
+func (r *BookRepository) CategoryIdsAfter(ctx context.Context, after string, limit int) ([]string, error) {
+	ids := []string{}
+	err := r.db.WithContext(ctx).Model(&model.Book{}).Distinct("category_id").
+		Where("category_id <> '' AND category_id > ?", after).
+		Order("category_id").Limit(limit).Pluck("category_id", &ids).Error
+
+	return ids, err
+}
+
+// RefreshAuthorNames copies the current author names, keyed by author id, onto
+// books and their contributors. search_vector is generated from author_name,
+// so it follows along. It returns how many books changed.
+func (r *BookRepository) RefreshAuthorNames(ctx context.Context, names map[string]string) (int64, error) {
+	changed := map[string]bool{}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for id, name := range names {
+			var bookIds []string
+			if err := tx.Model(&model.Book{}).Where("author_id = ? AND author_name <> ?", id, name).Pluck("id", &bookIds).Error; err != nil {
+				return err
+			}
+			if len(bookIds) > 0 {
+				if err := tx.Model(&model.Book{}).Where("id IN ?", bookIds).UpdateColumn("author_name", name).Error; err != nil {
+					return err
+				}
+			}
+
+			var contributorBookIds []string
+			if err := tx.Model(&model.BookContributor{}).Where("author_id = ? AND author_name <> ?", id, name).Pluck("book_id", &contributorBookIds).Error; err != nil {
+				return err
+			}
+			if len(contributorBookIds) > 0 {
+				if err := tx.Model(&model.BookContributor{}).Where("author_id = ?", id).UpdateColumn("author_name", name).Error; err != nil {
+					return err
+				}
+			}
+
+			for _, v := range append(bookIds, contributorBookIds...) {
+				changed[v] = true
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return r.invalidateRenamed(ctx, changed)
+}
+
+// RefreshCategoryNames copies the current category names, keyed by category
+// id, onto books. It returns how many books changed.
+func (r *BookRepository) RefreshCategoryNames(ctx context.Context, names map[string]string) (int64, error) {
+	changed := map[string]bool{}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for id, name := range names {
+			var bookIds []string
+			if err := tx.Model(&model.Book{}).Where("category_id = ? AND category_name <> ?", id, name).Pluck("id", &bookIds).Error; err != nil {
+				return err
+			}
+			if len(bookIds) == 0 {
+				continue
+			}
+
+			if err := tx.Model(&model.Book{}).Where("id IN ?", bookIds).UpdateColumn("category_name", name).Error; err != nil {
+				return err
+			}
+			for _, v := range bookIds {
+				changed[v] = true
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return r.invalidateRenamed(ctx, changed)
+}
+
+func (r *BookRepository) invalidateRenamed(ctx context.Context, changed map[string]bool) (int64, error) {
+	if len(changed) == 0 {
+		return 0, nil
+	}
+
+	keys := make([]string, 0, len(changed))
+	for id := range changed {
+		keys = append(keys, fmt.Sprintf("book:%s", id))
+	}
+	if err := r.redis.Del(ctx, keys...).Err(); err != nil {
+		return 0, err
+	}
+	if err := r.invalidateCatalog(ctx); err != nil {
+		return 0, err
+	}
+
+	return int64(len(changed)), nil
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/shafaalafghany/book-service/model"
+	"gorm.io/gorm"
+)
+
+const maxSimilarBooks = 50
+
+const rebuildSimilaritiesQuery = `
+WITH borrowers AS (
+	SELECT DISTINCT book_id, user_id FROM borrow_records WHERE deleted_at IS NULL
+), counts AS (
+	SELECT book_id, COUNT(*) AS n FROM borrowers GROUP BY book_id
+), pairs AS (
+	SELECT a.book_id, b.book_id AS similar_book_id, COUNT(*) AS shared
+	FROM borrowers a
+	JOIN borrowers b ON a.user_id = b.user_id AND a.book_id <> b.book_id
+	GROUP BY a.book_id, b.book_id
+), scored AS (
+	SELECT p.book_id, p.similar_book_id, p.shared / sqrt(ca.n * cb.n) AS score,
+		ROW_NUMBER() OVER (PARTITION BY p.book_id ORDER BY p.shared / sqrt(ca.n * cb.n) DESC, p.similar_book_id) AS position
+	FROM pairs p
+	JOIN counts ca ON ca.book_id = p.book_id
+	JOIN counts cb ON cb.book_id = p.similar_book_id
+)
+INSERT INTO book_similarities (book_id, similar_book_id, score, updated_at)
+SELECT book_id, similar_book_id, score, NOW() FROM scored WHERE position <= ?`
+
+func (r *BookRepository) RebuildSimilarities() (int64, error) {
+	var count int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM book_similarities").Error; err != nil {
+			return err
+		}
+
+		result := tx.Exec(rebuildSimilaritiesQuery, maxSimilarBooks)
+		if result.Error != nil {
+			return result.Error
+		}
+		count = result.RowsAffected
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if err := r.invalidateSimilarBooks(context.Background()); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *BookRepository) Recommend(userId, search string, limit int) ([]*model.Book, error) {
+	history := r.db.Model(&model.BorrowRecord{}).Select("book_id").Where("user_id = ? AND deleted_at IS NULL", userId)
+
+	scores := r.db.Model(&model.BookSimilarity{}).
+		Select("similar_book_id, SUM(score) AS score").
+		Where("book_id IN (?) AND similar_book_id NOT IN (?)", history, history).
+		Group("similar_book_id")
+
+	readWorks := r.db.Model(&model.Book{}).Select("work_id").Where("id::text IN (?) AND work_id <> ''", history)
+
+	ranked := r.db.Table("books").
+		Select("books.*, recommended.score AS rank").
+		Joins("JOIN (?) AS recommended ON recommended.similar_book_id = books.id::text", scores).
+		Where("books.deleted_at IS NULL").
+		Where("books.work_id = '' OR books.work_id NOT IN (?)", readWorks)
+
+	if search != "" {
+		ranked.Where("books.name ILIKE ?", "%"+search+"%")
+	}
+
+	base := r.collapseEditions(ranked).Order("rank DESC, borrows DESC").Limit(limit)
+
+	var books []*model.Book
+	if err := base.Find(&books).Error; err != nil {
+		return nil, err
+	}
+
+	return books, nil
+}
+
+func (r *BookRepository) BorrowedBookIds(userId string) ([]string, error) {
+	var ids []string
+	if err := r.db.Model(&model.BorrowRecord{}).Distinct("book_id").Where("user_id = ? AND deleted_at IS NULL", userId).Pluck("book_id", &ids).Error; err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
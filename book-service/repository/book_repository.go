@@ -5,24 +5,71 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/go-redis/redis/v8"
 	"github.com/shafaalafghany/book-service/model"
+	"gitlab.com/shafaalafghany/synapsis-common/pagination"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
-	"gorm.io/gorm/clause"
 )
 
 type BookRepositoryInterface interface {
-	Create(*model.Book) error
+	Create(context.Context, *model.Book) error
+	CreateBatch(context.Context, []*model.Book) ([]error, error)
 	GetById(context.Context, *model.Book) (*model.Book, error)
-	Get(context.Context, string) ([]*model.Book, error)
+	GetByIds(context.Context, []string) ([]*model.Book, error)
+	GetByISBN(context.Context, string) (*model.Book, error)
+	ExistingISBNs(context.Context, []string) ([]string, error)
+	GetEditions(context.Context, string, pagination.ListOptions) (*BookPage, error)
+	GetSeriesBooks(context.Context, string, pagination.ListOptions) (*BookPage, error)
+	Get(context.Context, BookFilter, pagination.ListOptions) (*BookPage, error)
+	CategoryFacets(context.Context, int, int) ([]*BookFacet, error)
+	AuthorFacets(context.Context, int, int) ([]*BookFacet, error)
 	Update(context.Context, *model.Book, string) error
 	Delete(context.Context, string) error
+	Search(context.Context, BookFilter, pagination.ListOptions) (*BookSearchResult, error)
+	GetDeleted(context.Context, pagination.ListOptions) (*BookPage, error)
+	GetDeletedById(context.Context, string) (*model.Book, error)
+	Restore(context.Context, string) error
+	Purge(time.Time) (int64, error)
+	ReferencedIds(context.Context, BookReferences) (*BookReferences, error)
+	AuthorIdsAfter(context.Context, string, int) ([]string, error)
+	CategoryIdsAfter(context.Context, string, int) ([]string, error)
+	RefreshAuthorNames(context.Context, map[string]string) (int64, error)
+	RefreshCategoryNames(context.Context, map[string]string) (int64, error)
 	Borrow(context.Context, *model.BorrowRecord) error
 	ReturnBook(context.Context, *model.BorrowRecord) error
-	MostBorrows(string) ([]*model.Book, error)
+	CreateLicense(context.Context, *model.DigitalLicense) error
+	GetLicenses(context.Context, string) ([]*model.DigitalLicense, error)
+	GetLicenseById(context.Context, string) (*model.DigitalLicense, error)
+	RevokeLicense(context.Context, string) error
+	ActiveLoans(context.Context, string) (int64, error)
+	BorrowDigital(context.Context, *model.BorrowRecord) error
+	ReturnDigital(context.Context, *model.BorrowRecord) error
+	GetLoanByToken(context.Context, string) (*model.BorrowRecord, error)
+	ExpireLoans(context.Context, time.Time) (int64, error)
+	PlaceHold(context.Context, *model.Hold) error
+	CancelHold(context.Context, string) error
+	GetHoldById(context.Context, string) (*model.Hold, error)
+	GetHolds(context.Context, HoldFilter, pagination.ListOptions) (*HoldPage, error)
+	RequestTransfer(context.Context, *model.Transfer) error
+	DispatchTransfer(context.Context, string, string) error
+	ReceiveTransfer(context.Context, string, string) error
+	CancelTransfer(context.Context, string) error
+	GetTransferById(context.Context, string) (*model.Transfer, error)
+	GetTransfers(context.Context, TransferFilter, pagination.ListOptions) (*TransferPage, error)
+	MostBorrows(string, []string, int) ([]*model.Book, error)
+	Recommend(string, string, int) ([]*model.Book, error)
+	BorrowedBookIds(string) ([]string, error)
+	RebuildSimilarities() (int64, error)
+	Trending(context.Context, TrendingQuery) ([]*TrendingBook, error)
+	RebuildTrending(context.Context) (int64, error)
+	RefreshRating(context.Context, string) error
+	SetCover(context.Context, string, string) error
+	GetCachedSimilarBooks(context.Context, string, int) ([]*SimilarBook, bool, error)
+	ScoreSimilarBooks(context.Context, *model.Book, []string, SimilarityWeights, int) ([]*SimilarBook, error)
 }
 
 type BookRepository struct {
@@ -31,6 +78,38 @@ type BookRepository struct {
 	redis  *redis.Client
 }
 
+// BookReferences groups ids of records in other services that books point
+// at.
+type BookReferences struct {
+	AuthorIDs    []string
+	CategoryIDs  []string
+	PublisherIDs []string
+	UserIDs      []string
+}
+
+type BookPage struct {
+	Books         []*model.Book `json:"books"`
+	NextPageToken string        `json:"next_page_token"`
+	TotalCount    int64         `json:"total_count"`
+}
+
+const bookListGenerationKey = "books:generation"
+
+var bookSortColumns = map[string]pagination.SortKind{
+	"name":       pagination.SortString,
+	"borrows":    pagination.SortInt,
+	"created_at": pagination.SortTime,
+	"updated_at": pagination.SortTime,
+}
+
+var deletedBookSortColumns = map[string]pagination.SortKind{
+	"name":       pagination.SortString,
+	"borrows":    pagination.SortInt,
+	"created_at": pagination.SortTime,
+	"updated_at": pagination.SortTime,
+	"deleted_at": pagination.SortTime,
+}
+
 func NewBookRepository(db *gorm.DB, logger *zap.Logger, redis *redis.Client) BookRepositoryInterface {
 	return &BookRepository{
 		db:     db,
@@ -39,19 +118,58 @@ func NewBookRepository(db *gorm.DB, logger *zap.Logger, redis *redis.Client) Boo
 	}
 }
 
-func (r *BookRepository) Create(data *model.Book) error {
+func (r *BookRepository) Create(ctx context.Context, data *model.Book) error {
 	if err := r.db.Create(&data).Error; err != nil {
 		return err
 	}
+
+	if err := r.invalidateCatalog(ctx); err != nil {
+		return err
+	}
 	return nil
 }
 
+// CreateBatch inserts the books and their contributors in one transaction.
+// Each book is inserted under its own savepoint, so a book that fails is
+// rolled back alone and its error is returned at the same index; the other
+// books are still created.
+func (r *BookRepository) CreateBatch(ctx context.Context, books []*model.Book) ([]error, error) {
+	failed := make([]error, len(books))
+	created := 0
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i, v := range books {
+			if err := tx.SavePoint("batch_book").Error; err != nil {
+				return err
+			}
+			if err := tx.Create(v).Error; err != nil {
+				if err := tx.RollbackTo("batch_book").Error; err != nil {
+					return err
+				}
+				failed[i] = err
+				continue
+			}
+			created++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if created > 0 {
+		if err := r.invalidateCatalog(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return failed, nil
+}
+
 func (r *BookRepository) GetById(ctx context.Context, data *model.Book) (*model.Book, error) {
 	var book model.Book
 	key := fmt.Sprintf("book:%s", data.ID)
 	bookRedis, err := r.redis.Get(ctx, key).Result()
 	if err == redis.Nil {
-		if err := r.db.First(&book).Error; err != nil {
+		if err := r.db.Preload("Contributors", orderContributors).Where("id = ? AND deleted_at IS NULL", data.ID).First(&book).Error; err != nil {
 			return nil, err
 		}
 
@@ -76,25 +194,69 @@ func (r *BookRepository) GetById(ctx context.Context, data *model.Book) (*model.
 	return &book, nil
 }
 
-func (r *BookRepository) Get(ctx context.Context, search string) ([]*model.Book, error) {
+func (r *BookRepository) GetByIds(ctx context.Context, ids []string) ([]*model.Book, error) {
 	var books []*model.Book
-	base := r.db.Model(&model.Book{}).Where("deleted_at IS NULL")
+	if err := r.db.WithContext(ctx).Preload("Contributors", orderContributors).Where("id IN ? AND deleted_at IS NULL", ids).Find(&books).Error; err != nil {
+		return nil, err
+	}
 
-	if search != "" {
-		if err := base.Where("name ILIKE ?", "%"+search+"%").Find(&books).Error; err != nil {
-			return nil, err
-		}
-		return books, nil
+	return books, nil
+}
+
+func (r *BookRepository) GetByISBN(ctx context.Context, isbn string) (*model.Book, error) {
+	var book model.Book
+	if err := r.db.WithContext(ctx).Where("isbn = ? AND deleted_at IS NULL", isbn).First(&book).Error; err != nil {
+		return nil, err
+	}
+
+	return &book, nil
+}
+
+func (r *BookRepository) ExistingISBNs(ctx context.Context, isbns []string) ([]string, error) {
+	var existing []string
+	if len(isbns) == 0 {
+		return existing, nil
+	}
+
+	if err := r.db.WithContext(ctx).Model(&model.Book{}).Where("isbn IN ? AND deleted_at IS NULL", isbns).Pluck("isbn", &existing).Error; err != nil {
+		return nil, err
+	}
+
+	return existing, nil
+}
+
+func (r *BookRepository) Get(ctx context.Context, filter BookFilter, opts pagination.ListOptions) (*BookPage, error) {
+	if err := opts.Normalize(bookSortColumns, "created_at", "asc"); err != nil {
+		return nil, err
+	}
+
+	filter = filter.normalize()
+	opts.Search = strings.TrimSpace(opts.Search)
+
+	base := filter.apply(r.db.Model(&model.Book{}).Where("deleted_at IS NULL"))
+
+	if opts.Search != "" {
+		base.Where("name ILIKE ?", "%"+opts.Search+"%")
+	}
+
+	generation, err := r.cacheGeneration(ctx, bookListGenerationKey)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := bookListKey(generation, filter, opts)
+	if err != nil {
+		return nil, err
 	}
 
-	key := "books"
 	booksRedis, err := r.redis.Get(ctx, key).Result()
 	if err == redis.Nil {
-		if err := base.Find(&books).Error; err != nil {
+		page, err := r.page(base, opts, bookSortColumns)
+		if err != nil {
 			return nil, err
 		}
 
-		booksJson, err := json.Marshal(books)
+		booksJson, err := json.Marshal(page)
 		if err != nil {
 			return nil, err
 		}
@@ -103,23 +265,68 @@ func (r *BookRepository) Get(ctx context.Context, search string) ([]*model.Book,
 			return nil, err
 		}
 
-		return books, nil
+		return page, nil
 	} else if err != nil {
 		return nil, err
 	}
 
-	if err = json.Unmarshal([]byte(booksRedis), &books); err != nil {
+	var page BookPage
+	if err = json.Unmarshal([]byte(booksRedis), &page); err != nil {
 		return nil, err
 	}
 
-	return books, nil
+	return &page, nil
 }
 
+// Update replaces the name, authors and category. Every other field is only
+// written when given, so an update that leaves it out keeps the stored value.
+// Publisher and series fields are written as pairs. Branches are also cleared
+// when the book becomes digital.
 func (r *BookRepository) Update(ctx context.Context, data *model.Book, id string) error {
 	updatedData := map[string]interface{}{
-		"name":        data.Name,
-		"author_id":   data.AuthorID,
-		"category_id": data.CategoryID,
+		"name":          data.Name,
+		"author_id":     data.AuthorID,
+		"category_id":   data.CategoryID,
+		"author_name":   data.AuthorName,
+		"category_name": data.CategoryName,
+	}
+	if data.ISBN != "" {
+		updatedData["isbn"] = data.ISBN
+	}
+	if data.PublisherID != "" || data.Publisher != "" {
+		updatedData["publisher_id"] = data.PublisherID
+		updatedData["publisher"] = data.Publisher
+	}
+	if data.PublishedYear != 0 {
+		updatedData["published_year"] = data.PublishedYear
+	}
+	if data.Language != "" {
+		updatedData["language"] = data.Language
+	}
+	if data.PageCount != 0 {
+		updatedData["page_count"] = data.PageCount
+	}
+	if data.Edition != "" {
+		updatedData["edition"] = data.Edition
+	}
+	if data.Description != "" {
+		updatedData["description"] = data.Description
+	}
+	if data.WorkID != "" {
+		updatedData["work_id"] = data.WorkID
+	}
+	if data.SeriesID != "" {
+		updatedData["series_id"] = data.SeriesID
+		updatedData["series_volume"] = data.SeriesVolume
+	}
+	if data.ItemType != "" {
+		updatedData["item_type"] = data.ItemType
+	}
+	if data.ItemType == model.ItemDigital || data.HomeBranchID != "" {
+		updatedData["home_branch_id"] = data.HomeBranchID
+	}
+	if data.ItemType == model.ItemDigital || data.CurrentBranchID != "" {
+		updatedData["current_branch_id"] = data.CurrentBranchID
 	}
 
 	exist, err := r.redis.Exists(ctx, fmt.Sprintf("book:%s", id)).Result()
@@ -133,22 +340,30 @@ func (r *BookRepository) Update(ctx context.Context, data *model.Book, id string
 		}
 	}
 
-	exist, err = r.redis.Exists(ctx, "books").Result()
-	if err != nil {
+	if err = r.invalidateCatalog(ctx); err != nil {
 		return err
 	}
 
-	if exist == 1 {
-		if err = r.redis.Del(ctx, "books").Err(); err != nil {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.Book{}).Where("id = ? AND deleted_at IS NULL", id).Updates(updatedData).Error; err != nil {
 			return err
 		}
-	}
 
-	if err = r.db.Model(&model.Book{}).Where("id = ? AND deleted_at IS NULL", id).Updates(updatedData).Error; err != nil {
-		return err
-	}
+		if err := tx.Where("book_id = ?", id).Delete(&model.BookContributor{}).Error; err != nil {
+			return err
+		}
 
-	return nil
+		for _, v := range data.Contributors {
+			v.BookID = id
+		}
+		if len(data.Contributors) > 0 {
+			if err := tx.Create(&data.Contributors).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
 }
 
 func (r *BookRepository) Delete(ctx context.Context, id string) error {
@@ -163,17 +378,10 @@ func (r *BookRepository) Delete(ctx context.Context, id string) error {
 		}
 	}
 
-	exist, err = r.redis.Exists(ctx, "books").Result()
-	if err != nil {
+	if err = r.invalidateCatalog(ctx); err != nil {
 		return err
 	}
 
-	if exist == 1 {
-		if err = r.redis.Del(ctx, "books").Err(); err != nil {
-			return err
-		}
-	}
-
 	if err := r.db.Model(&model.Book{}).Where("id = ? AND deleted_at IS NULL", id).Update("deleted_at", time.Now()).Error; err != nil {
 		return err
 	}
@@ -181,85 +389,243 @@ func (r *BookRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
-func (r *BookRepository) Borrow(ctx context.Context, data *model.BorrowRecord) error {
-	lockKey := fmt.Sprintf("lock:book:%s", data.BookID)
-	if ok := r.redis.SetNX(ctx, lockKey, data.UserID, 30*time.Second).Val(); !ok {
-		return fmt.Errorf("book currently is still borrowed, try again later")
+func (r *BookRepository) GetDeleted(ctx context.Context, opts pagination.ListOptions) (*BookPage, error) {
+	if err := opts.Normalize(deletedBookSortColumns, "deleted_at", "desc"); err != nil {
+		return nil, err
 	}
-	defer r.redis.Del(ctx, lockKey)
 
+	base := r.db.Model(&model.Book{}).Where("deleted_at IS NOT NULL")
+
+	if opts.Search != "" {
+		base.Where("name ILIKE ?", "%"+opts.Search+"%")
+	}
+
+	return r.page(base, opts, deletedBookSortColumns)
+}
+
+func (r *BookRepository) GetDeletedById(ctx context.Context, id string) (*model.Book, error) {
 	var book model.Book
-	if err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
-		First(&book, "id = ? AND deleted_at IS NULL", data.BookID).Error; err != nil {
+	if err := r.db.Preload("Contributors", orderContributors).Where("id = ? AND deleted_at IS NOT NULL", id).First(&book).Error; err != nil {
+		return nil, err
+	}
+
+	return &book, nil
+}
+
+func (r *BookRepository) Restore(ctx context.Context, id string) error {
+	if err := r.invalidateCatalog(ctx); err != nil {
 		return err
 	}
 
-	if book.IsBorrowed {
-		return errors.New("book is still borrowed")
+	if err := r.db.Model(&model.Book{}).Where("id = ? AND deleted_at IS NOT NULL", id).Update("deleted_at", nil).Error; err != nil {
+		return err
 	}
 
-	if err := r.db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Create(&data).Error; err != nil {
+	return nil
+}
+
+// bookHistory lists the tables that record what happened to a book. A
+// deleted book stays in the trash while any of them still refers to it.
+var bookHistory = []string{"borrow_records", "reviews", "reading_list_items", "notifications", "holds", "transfers"}
+
+// Purge hard-deletes books that have been in the trash since before the
+// given time and that no loan, review, list, hold or transfer refers to.
+// Contributors, licenses and similarity scores belong to the book and are
+// deleted with it.
+func (r *BookRepository) Purge(before time.Time) (int64, error) {
+	var count int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		purgeable := tx.Model(&model.Book{}).Where("deleted_at IS NOT NULL AND deleted_at < ?", before)
+		for _, table := range bookHistory {
+			purgeable = purgeable.Where(fmt.Sprintf("NOT EXISTS (SELECT 1 FROM %s WHERE %s.book_id = books.id::text)", table, table))
+		}
+
+		var ids []string
+		if err := purgeable.Pluck("id", &ids).Error; err != nil {
 			return err
 		}
+		if len(ids) == 0 {
+			return nil
+		}
 
-		book.IsBorrowed = true
-		book.Borrows++
-		if err := tx.Save(&book).Error; err != nil {
+		if err := tx.Where("book_id IN ?", ids).Delete(&model.BookContributor{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("book_id IN ?", ids).Delete(&model.DigitalLicense{}).Error; err != nil {
 			return err
 		}
+		if err := tx.Where("book_id IN ? OR similar_book_id IN ?", ids, ids).Delete(&model.BookSimilarity{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Where("id IN ?", ids).Delete(&model.Book{})
+		if result.Error != nil {
+			return result.Error
+		}
+		count = result.RowsAffected
 
 		return nil
-	}); err != nil {
-		return err
+	})
+	if err != nil {
+		return 0, err
 	}
 
-	return nil
+	return count, nil
 }
 
-func (r *BookRepository) ReturnBook(ctx context.Context, data *model.BorrowRecord) error {
-	var book model.Book
-	if err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
-		First(&book, "id = ? AND deleted_at IS NULL", data.BookID).Error; err != nil {
-		return err
+// ReferencedIds returns the given authors, categories and publishers that
+// books still refer to, and the given users that still have loans, reviews,
+// reading lists, holds or notifications. Books in the trash count, since
+// they can be restored.
+func (r *BookRepository) ReferencedIds(ctx context.Context, refs BookReferences) (*BookReferences, error) {
+	result := &BookReferences{AuthorIDs: []string{}, CategoryIDs: []string{}, PublisherIDs: []string{}, UserIDs: []string{}}
+
+	if len(refs.AuthorIDs) > 0 {
+		if err := r.db.WithContext(ctx).Raw(`
+			SELECT author_id FROM books WHERE author_id IN ?
+			UNION
+			SELECT author_id FROM book_contributors WHERE author_id IN ?`,
+			refs.AuthorIDs, refs.AuthorIDs).Scan(&result.AuthorIDs).Error; err != nil {
+			return nil, err
+		}
+	}
+	if len(refs.CategoryIDs) > 0 {
+		if err := r.db.WithContext(ctx).Model(&model.Book{}).Distinct("category_id").
+			Where("category_id IN ?", refs.CategoryIDs).Pluck("category_id", &result.CategoryIDs).Error; err != nil {
+			return nil, err
+		}
+	}
+	if len(refs.PublisherIDs) > 0 {
+		if err := r.db.WithContext(ctx).Model(&model.Book{}).Distinct("publisher_id").
+			Where("publisher_id IN ?", refs.PublisherIDs).Pluck("publisher_id", &result.PublisherIDs).Error; err != nil {
+			return nil, err
+		}
+	}
+	if len(refs.UserIDs) > 0 {
+		if err := r.db.WithContext(ctx).Raw(`
+			SELECT user_id FROM borrow_records WHERE user_id IN ?
+			UNION
+			SELECT user_id FROM reviews WHERE user_id IN ?
+			UNION
+			SELECT user_id FROM reading_lists WHERE user_id IN ?
+			UNION
+			SELECT user_id FROM holds WHERE user_id IN ?
+			UNION
+			SELECT user_id FROM notifications WHERE user_id IN ?`,
+			refs.UserIDs, refs.UserIDs, refs.UserIDs, refs.UserIDs, refs.UserIDs).Scan(&result.UserIDs).Error; err != nil {
+			return nil, err
+		}
 	}
 
-	if !book.IsBorrowed {
-		return errors.New("book is not borrowed")
+	return result, nil
+}
+
+func (r *BookRepository) Borrow(ctx context.Context, data *model.BorrowRecord) error {
+	lockKey := fmt.Sprintf("lock:book:%s", data.BookID)
+	if ok := r.redis.SetNX(ctx, lockKey, data.UserID, 30*time.Second).Val(); !ok {
+		return fmt.Errorf("book currently is still borrowed, try again later")
 	}
+	defer r.redis.Del(ctx, lockKey)
+
+	var book *model.Book
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if book, err = lockBook(tx, data.BookID); err != nil {
+			return err
+		}
+
+		if book.IsBorrowed {
+			return errors.New("book is still borrowed")
+		}
+
+		if err := checkHolds(tx, book, data.UserID); err != nil {
+			return err
+		}
 
-	var borrowRecord model.BorrowRecord
-	if err := r.db.First(&borrowRecord, "book_id = ? AND user_id = ? AND returned_at IS NULL", data.BookID, data.UserID).Error; err != nil {
+		if err := tx.Create(&data).Error; err != nil {
+			return err
+		}
+
+		book.IsBorrowed = true
+		book.Borrows++
+		return tx.Model(&model.Book{}).Where("id = ?", book.ID).Updates(map[string]interface{}{
+			"is_borrowed": true,
+			"borrows":     book.Borrows,
+		}).Error
+	}); err != nil {
 		return err
 	}
 
-	now := time.Now()
-	borrowRecord.ReturnedAt = &now
+	if err := r.recordTrending(ctx, book, data.BorrowedAt); err != nil {
+		r.logger.Warn("failed to record trending borrow", zap.String("book_id", book.ID), zap.Error(err))
+	}
+
+	return r.invalidateBook(ctx, book.ID)
+}
 
+func (r *BookRepository) ReturnBook(ctx context.Context, data *model.BorrowRecord) error {
 	if err := r.db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Save(&borrowRecord).Error; err != nil {
+		book, err := lockBook(tx, data.BookID)
+		if err != nil {
+			return err
+		}
+
+		if !book.IsBorrowed {
+			return errors.New("book is not borrowed")
+		}
+
+		var borrowRecord model.BorrowRecord
+		if err := tx.First(&borrowRecord, "book_id = ? AND user_id = ? AND returned_at IS NULL", data.BookID, data.UserID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&model.BorrowRecord{}).Where("id = ?", borrowRecord.ID).Updates(map[string]interface{}{
+			"returned_at":      time.Now(),
+			"return_branch_id": data.ReturnBranchID,
+		}).Error; err != nil {
 			return err
 		}
 
 		book.IsBorrowed = false
-		if err := tx.Save(&book).Error; err != nil {
+		updated := map[string]interface{}{"is_borrowed": false}
+		if data.ReturnBranchID != "" {
+			book.CurrentBranchID = data.ReturnBranchID
+			updated["current_branch_id"] = data.ReturnBranchID
+		}
+		if err := tx.Model(&model.Book{}).Where("id = ?", book.ID).Updates(updated).Error; err != nil {
 			return err
 		}
 
-		return nil
+		if err := routeItem(tx, book); err != nil {
+			return err
+		}
+
+		// Readers waiting on the title are only told when the item stays on
+		// the shelf, not when it goes to a hold or back to its home branch.
+		free, err := onShelf(tx, book)
+		if err != nil || !free {
+			return err
+		}
+		return notifyAvailable(tx, book)
 	}); err != nil {
 		return err
 	}
 
-	return nil
+	return r.invalidateBook(ctx, data.BookID)
 }
 
-func (r *BookRepository) MostBorrows(search string) ([]*model.Book, error) {
-	base := r.db.Order("borrows DESC").Limit(5)
+func (r *BookRepository) MostBorrows(search string, exclude []string, limit int) ([]*model.Book, error) {
+	ranked := r.db.Table("books").Select("books.*, borrows AS rank").Where("deleted_at IS NULL")
 
 	if search != "" {
-		base.Where("name ILIKE ?", "%"+search+"%")
+		ranked.Where("name ILIKE ?", "%"+search+"%")
 	}
+	if len(exclude) > 0 {
+		ranked.Where("id::text NOT IN ?", exclude)
+	}
+
+	base := r.collapseEditions(ranked).Order("rank DESC, id").Limit(limit)
+
 	var books []*model.Book
 	if err := base.Find(&books).Error; err != nil {
 		return nil, err
@@ -267,3 +633,110 @@ func (r *BookRepository) MostBorrows(search string) ([]*model.Book, error) {
 
 	return books, nil
 }
+
+func (r *BookRepository) page(base *gorm.DB, opts pagination.ListOptions, columns map[string]pagination.SortKind) (*BookPage, error) {
+	page := &BookPage{}
+
+	if opts.IncludeTotal {
+		if err := base.Session(&gorm.Session{}).Count(&page.TotalCount).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	query, err := pagination.Paginate(base.Session(&gorm.Session{}), opts, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := query.Preload("Contributors", orderContributors).Find(&page.Books).Error; err != nil {
+		return nil, err
+	}
+
+	if len(page.Books) > opts.PageSize {
+		page.Books = page.Books[:opts.PageSize]
+		last := page.Books[len(page.Books)-1]
+		page.NextPageToken = pagination.EncodePageToken(opts, bookSortValue(last, opts.SortBy), last.ID)
+	}
+
+	return page, nil
+}
+
+func (r *BookRepository) RefreshRating(ctx context.Context, id string) error {
+	if err := r.db.Exec(`
+		UPDATE books SET average_rating = COALESCE(ratings.average, 0), rating_count = ratings.count
+		FROM (
+			SELECT AVG(rating) AS average, COUNT(*) AS count FROM reviews
+			WHERE book_id = ? AND status <> ? AND deleted_at IS NULL
+		) AS ratings
+		WHERE books.id = ?`, id, model.ReviewHidden, id).Error; err != nil {
+		return err
+	}
+
+	return r.invalidateBook(ctx, id)
+}
+
+func (r *BookRepository) SetCover(ctx context.Context, id, contentType string) error {
+	if err := r.db.WithContext(ctx).Model(&model.Book{}).Where("id = ? AND deleted_at IS NULL", id).Update("cover_type", contentType).Error; err != nil {
+		return err
+	}
+
+	return r.invalidateBook(ctx, id)
+}
+
+func (r *BookRepository) invalidateBook(ctx context.Context, id string) error {
+	if err := r.redis.Del(ctx, fmt.Sprintf("book:%s", id)).Err(); err != nil {
+		return err
+	}
+
+	return r.invalidateBookLists(ctx)
+}
+
+func (r *BookRepository) invalidateCatalog(ctx context.Context) error {
+	if err := r.invalidateBookLists(ctx); err != nil {
+		return err
+	}
+
+	return r.invalidateSimilarBooks(ctx)
+}
+
+// invalidateBookLists moves the list cache to a new generation instead of
+// deleting keys; entries of older generations are never read again and
+// expire on their own.
+func (r *BookRepository) invalidateBookLists(ctx context.Context) error {
+	return r.redis.Incr(ctx, bookListGenerationKey).Err()
+}
+
+func (r *BookRepository) cacheGeneration(ctx context.Context, key string) (int64, error) {
+	generation, err := r.redis.Get(ctx, key).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+
+	return generation, err
+}
+
+func orderContributors(db *gorm.DB) *gorm.DB {
+	return db.Order("position ASC")
+}
+
+func bookSortValue(data *model.Book, sortBy string) interface{} {
+	switch sortBy {
+	case "name":
+		return data.Name
+	case "borrows":
+		return data.Borrows
+	case "published_year":
+		return data.PublishedYear
+	case "series_volume":
+		return data.SeriesVolume
+	case "updated_at":
+		return data.UpdatedAt
+	case "deleted_at":
+		if data.DeletedAt != nil {
+			return *data.DeletedAt
+		}
+		return nil
+	default:
+		return data.CreatedAt
+	}
+}
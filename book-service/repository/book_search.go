@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/shafaalafghany/book-service/model"
+	"gitlab.com/shafaalafghany/synapsis-common/pagination"
+	"gorm.io/gorm"
+)
+
+const (
+	searchHeadlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxWords=35, MinWords=15"
+	searchDocument        = "concat_ws(' - ', name, NULLIF(author_name, ''), NULLIF(category_name, ''))"
+	fuzzyThreshold        = 0.3
+)
+
+type BookSearchHit struct {
+	model.Book `gorm:"embedded"`
+	Rank       float64
+	Snippet    string
+}
+
+type BookSearchResult struct {
+	Hits          []*BookSearchHit
+	NextPageToken string
+	Fuzzy         bool
+}
+
+type searchCursor struct {
+	Offset int  `json:"o"`
+	Fuzzy  bool `json:"f"`
+}
+
+// Search ranks books by full-text relevance over the name, author and
+// category. When the query matches nothing it falls back to trigram
+// similarity so misspelled queries still find something; the page token
+// remembers which mode produced the first page.
+func (r *BookRepository) Search(ctx context.Context, filter BookFilter, opts pagination.ListOptions) (*BookSearchResult, error) {
+	if opts.PageSize <= 0 {
+		opts.PageSize = pagination.DefaultPageSize
+	}
+	if opts.PageSize > pagination.MaxPageSize {
+		opts.PageSize = pagination.MaxPageSize
+	}
+
+	query := strings.TrimSpace(opts.Search)
+	filter = filter.normalize()
+
+	cursor := searchCursor{}
+	if opts.PageToken != "" {
+		raw, err := base64.RawURLEncoding.DecodeString(opts.PageToken)
+		if err != nil {
+			return nil, pagination.ErrInvalidPageToken
+		}
+		if err := json.Unmarshal(raw, &cursor); err != nil || cursor.Offset < 0 {
+			return nil, pagination.ErrInvalidPageToken
+		}
+	}
+
+	var hits []*BookSearchHit
+	if !cursor.Fuzzy {
+		if err := r.fullTextSearch(filter, query, cursor.Offset, opts.PageSize).Find(&hits).Error; err != nil {
+			return nil, err
+		}
+
+		if len(hits) == 0 && cursor.Offset == 0 {
+			cursor.Fuzzy = true
+		}
+	}
+
+	if cursor.Fuzzy && len(hits) == 0 {
+		err := r.db.Transaction(func(tx *gorm.DB) error {
+			// The % operator matches against pg_trgm.similarity_threshold,
+			// scoped here to this transaction.
+			if err := tx.Exec("SELECT set_config('pg_trgm.similarity_threshold', ?, true)", strconv.FormatFloat(fuzzyThreshold, 'f', -1, 64)).Error; err != nil {
+				return err
+			}
+			return r.fuzzySearch(tx, filter, query, cursor.Offset, opts.PageSize).Find(&hits).Error
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := &BookSearchResult{Fuzzy: cursor.Fuzzy}
+	if len(hits) > opts.PageSize {
+		hits = hits[:opts.PageSize]
+
+		next, err := json.Marshal(searchCursor{Offset: cursor.Offset + opts.PageSize, Fuzzy: cursor.Fuzzy})
+		if err != nil {
+			return nil, err
+		}
+		result.NextPageToken = base64.RawURLEncoding.EncodeToString(next)
+	}
+	result.Hits = hits
+
+	return result, nil
+}
+
+func (r *BookRepository) fullTextSearch(filter BookFilter, query string, offset, limit int) *gorm.DB {
+	ranked := filter.apply(r.db.Table("books")).
+		Select("books.*, ts_rank_cd(search_vector, websearch_to_tsquery('english', ?)) AS rank, ts_headline('english', "+searchDocument+", websearch_to_tsquery('english', ?), ?) AS snippet", query, query, searchHeadlineOptions).
+		Where("deleted_at IS NULL AND search_vector @@ websearch_to_tsquery('english', ?)", query)
+
+	return r.collapseEditions(ranked).
+		Order("rank DESC, id").
+		Offset(offset).
+		Limit(limit + 1)
+}
+
+// fuzzySearch filters with the % operator so the trigram indexes on name and
+// author_name apply; similarity() only orders the matches.
+func (r *BookRepository) fuzzySearch(tx *gorm.DB, filter BookFilter, query string, offset, limit int) *gorm.DB {
+	ranked := filter.apply(tx.Table("books")).
+		Select("books.*, GREATEST(similarity(name, ?), similarity(author_name, ?)) AS rank, "+searchDocument+" AS snippet", query, query).
+		Where("deleted_at IS NULL AND (name % ? OR author_name % ?)", query, query)
+
+	return r.collapseEditions(ranked).
+		Order("rank DESC, id").
+		Offset(offset).
+		Limit(limit + 1)
+}
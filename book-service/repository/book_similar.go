@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/shafaalafghany/book-service/model"
+)
+
+const (
+	similarTitleThreshold = 0.2
+	similarCacheTTL       = time.Hour
+	similarGenerationKey  = "similar:generation"
+)
+
+type SimilarityWeights struct {
+	Author   float64
+	Category float64
+	Ancestor float64
+	CoBorrow float64
+	Title    float64
+}
+
+var DefaultSimilarityWeights = SimilarityWeights{
+	Author:   3,
+	Category: 2,
+	Ancestor: 1,
+	CoBorrow: 4,
+	Title:    2,
+}
+
+type SimilarBook struct {
+	Book  *model.Book
+	Score float64
+}
+
+type similarEntry struct {
+	ID    string  `json:"id"`
+	Score float64 `json:"score"`
+}
+
+type scoredBook struct {
+	model.Book `gorm:"embedded"`
+	Score      float64
+}
+
+func similarKey(generation int64, id string) string {
+	return fmt.Sprintf("similar:%d:%s", generation, id)
+}
+
+func (r *BookRepository) GetCachedSimilarBooks(ctx context.Context, id string, limit int) ([]*SimilarBook, bool, error) {
+	generation, err := r.cacheGeneration(ctx, similarGenerationKey)
+	if err != nil {
+		return nil, false, err
+	}
+
+	cached, err := r.redis.Get(ctx, similarKey(generation, id)).Result()
+	if err == redis.Nil {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+
+	var entries []similarEntry
+	if err := json.Unmarshal([]byte(cached), &entries); err != nil {
+		return nil, false, err
+	}
+
+	result, err := r.loadSimilarBooks(ctx, entries, limit)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return result, true, nil
+}
+
+func (r *BookRepository) ScoreSimilarBooks(ctx context.Context, source *model.Book, ancestors []string, weights SimilarityWeights, limit int) ([]*SimilarBook, error) {
+	generation, err := r.cacheGeneration(ctx, similarGenerationKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var scored []*scoredBook
+	if err := r.db.WithContext(ctx).Raw(`
+		SELECT books.*,
+			CASE WHEN books.author_id = ? THEN ? ELSE 0 END +
+			CASE WHEN books.category_id = ? THEN ? ELSE 0 END +
+			CASE WHEN books.category_id IN ? THEN ? ELSE 0 END +
+			? * COALESCE(bs.score, 0) +
+			? * similarity(books.name, ?) AS score
+		FROM books
+		LEFT JOIN book_similarities bs ON bs.book_id = ? AND bs.similar_book_id = books.id::text
+		WHERE books.deleted_at IS NULL AND books.id <> ?
+			AND (books.author_id = ? OR books.category_id = ? OR books.category_id IN ? OR bs.score IS NOT NULL OR similarity(books.name, ?) > ?)
+		ORDER BY score DESC, books.borrows DESC
+		LIMIT ?`,
+		source.AuthorID, weights.Author,
+		source.CategoryID, weights.Category,
+		ancestors, weights.Ancestor,
+		weights.CoBorrow,
+		weights.Title, source.Name,
+		source.ID,
+		source.ID,
+		source.AuthorID, source.CategoryID, ancestors, source.Name, similarTitleThreshold,
+		maxSimilarBooks,
+	).Scan(&scored).Error; err != nil {
+		return nil, err
+	}
+
+	entries := make([]similarEntry, 0, len(scored))
+	result := []*SimilarBook{}
+	for _, v := range scored {
+		entries = append(entries, similarEntry{ID: v.ID, Score: v.Score})
+		if len(result) < limit {
+			book := v.Book
+			result = append(result, &SimilarBook{Book: &book, Score: v.Score})
+		}
+	}
+
+	entriesJson, err := json.Marshal(entries)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.redis.Set(ctx, similarKey(generation, source.ID), entriesJson, similarCacheTTL).Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *BookRepository) loadSimilarBooks(ctx context.Context, entries []similarEntry, limit int) ([]*SimilarBook, error) {
+	if len(entries) == 0 {
+		return []*SimilarBook{}, nil
+	}
+
+	ids := make([]string, 0, len(entries))
+	for _, v := range entries {
+		ids = append(ids, v.ID)
+	}
+
+	books, err := r.GetByIds(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	found := map[string]*model.Book{}
+	for _, v := range books {
+		found[v.ID] = v
+	}
+
+	result := []*SimilarBook{}
+	for _, v := range entries {
+		book, ok := found[v.ID]
+		if !ok {
+			continue
+		}
+
+		result = append(result, &SimilarBook{Book: book, Score: v.Score})
+		if len(result) == limit {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+func (r *BookRepository) invalidateSimilarBooks(ctx context.Context) error {
+	return r.redis.Incr(ctx, similarGenerationKey).Err()
+}
@@ -0,0 +1,515 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/shafaalafghany/book-service/model"
+	"gitlab.com/shafaalafghany/synapsis-common/pagination"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrBookInTransit   = errors.New("book is in transit between branches")
+	ErrBookOnHold      = errors.New("book is on hold for another reader")
+	ErrBookUnavailable = errors.New("book is borrowed or already being transferred")
+	ErrHoldExists      = errors.New("reader already has an active hold on this book")
+	ErrInvalidStatus   = errors.New("the transfer or hold is not in a state that allows this")
+	ErrNoLocation      = errors.New("book has no current branch")
+	ErrSameBranch      = errors.New("book is already at the destination branch")
+)
+
+type TransferFilter struct {
+	BranchID  string
+	Direction string
+	Status    string
+}
+
+type HoldFilter struct {
+	BookID   string
+	UserID   string
+	BranchID string
+	Status   string
+}
+
+type TransferPage struct {
+	Transfers     []*model.Transfer
+	NextPageToken string
+}
+
+type HoldPage struct {
+	Holds         []*model.Hold
+	NextPageToken string
+}
+
+var activeHoldStatuses = []string{model.HoldWaiting, model.HoldInTransit, model.HoldReady}
+
+const transferBook = "transfers.*, (SELECT name FROM books WHERE books.id::text = transfers.book_id) AS book_name, (SELECT isbn FROM books WHERE books.id::text = transfers.book_id) AS book_isbn"
+
+// PlaceHold queues a hold and, when the item is free, either readies it at
+// the pickup branch or starts a transfer there.
+func (r *BookRepository) PlaceHold(ctx context.Context, data *model.Hold) error {
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+		book, err := lockBook(tx, data.BookID)
+		if err != nil {
+			return err
+		}
+
+		var active int64
+		if err := tx.Model(&model.Hold{}).
+			Where("book_id = ? AND user_id = ? AND status IN ?", data.BookID, data.UserID, activeHoldStatuses).
+			Count(&active).Error; err != nil {
+			return err
+		}
+		if active > 0 {
+			return ErrHoldExists
+		}
+
+		data.Status = model.HoldWaiting
+		if err := tx.Create(data).Error; err != nil {
+			return err
+		}
+
+		return routeItem(tx, book)
+	}); err != nil {
+		return err
+	}
+
+	return r.invalidateBook(ctx, data.BookID)
+}
+
+// CancelHold cancels an active hold. An item that was ready for the reader
+// goes to the next hold in line or back home. Transfers already started for
+// the hold continue; the item is routed again when it arrives.
+func (r *BookRepository) CancelHold(ctx context.Context, id string) error {
+	var bookId string
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+		var hold model.Hold
+		if err := tx.Where("id = ?", id).First(&hold).Error; err != nil {
+			return err
+		}
+		bookId = hold.BookID
+
+		book, err := lockBook(tx, hold.BookID)
+		if err != nil {
+			return err
+		}
+
+		result := tx.Model(&model.Hold{}).Where("id = ? AND status IN ?", id, activeHoldStatuses).Update("status", model.HoldCancelled)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrInvalidStatus
+		}
+
+		if hold.Status != model.HoldReady {
+			return nil
+		}
+		return routeItem(tx, book)
+	}); err != nil {
+		return err
+	}
+
+	return r.invalidateBook(ctx, bookId)
+}
+
+func (r *BookRepository) GetHoldById(ctx context.Context, id string) (*model.Hold, error) {
+	var hold model.Hold
+	if err := r.db.Where("id = ?", id).First(&hold).Error; err != nil {
+		return nil, err
+	}
+
+	return &hold, nil
+}
+
+func (r *BookRepository) GetHolds(ctx context.Context, filter HoldFilter, opts pagination.ListOptions) (*HoldPage, error) {
+	if err := opts.Normalize(createdAtSortColumns, "created_at", "asc"); err != nil {
+		return nil, err
+	}
+
+	base := r.db.Model(&model.Hold{})
+	if filter.BookID != "" {
+		base.Where("book_id = ?", filter.BookID)
+	}
+	if filter.UserID != "" {
+		base.Where("user_id = ?", filter.UserID)
+	}
+	if filter.BranchID != "" {
+		base.Where("pickup_branch_id = ?", filter.BranchID)
+	}
+	if filter.Status != "" {
+		base.Where("status = ?", filter.Status)
+	}
+
+	query, err := pagination.Paginate(base, opts, createdAtSortColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &HoldPage{}
+	if err := query.Find(&page.Holds).Error; err != nil {
+		return nil, err
+	}
+
+	if len(page.Holds) > opts.PageSize {
+		page.Holds = page.Holds[:opts.PageSize]
+		last := page.Holds[len(page.Holds)-1]
+		page.NextPageToken = pagination.EncodePageToken(opts, last.CreatedAt, last.ID)
+	}
+
+	return page, nil
+}
+
+// RequestTransfer starts a manual transfer of an item from its current
+// branch.
+func (r *BookRepository) RequestTransfer(ctx context.Context, data *model.Transfer) error {
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+		book, err := lockBook(tx, data.BookID)
+		if err != nil {
+			return err
+		}
+
+		if book.CurrentBranchID == "" {
+			return ErrNoLocation
+		}
+		if book.IsBorrowed || book.InTransit {
+			return ErrBookUnavailable
+		}
+		if book.CurrentBranchID == data.ToBranchID {
+			return ErrSameBranch
+		}
+
+		var ready int64
+		if err := tx.Model(&model.Hold{}).Where("book_id = ? AND status = ?", book.ID, model.HoldReady).Count(&ready).Error; err != nil {
+			return err
+		}
+		if ready > 0 {
+			return ErrBookOnHold
+		}
+
+		data.FromBranchID = book.CurrentBranchID
+		data.Status = model.TransferRequested
+		data.Reason = model.TransferReasonManual
+		if err := tx.Create(data).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&model.Book{}).Where("id = ?", book.ID).UpdateColumn("in_transit", true).Error
+	}); err != nil {
+		return err
+	}
+
+	return r.invalidateBook(ctx, data.BookID)
+}
+
+func (r *BookRepository) DispatchTransfer(ctx context.Context, id, userId string) error {
+	now := time.Now()
+	result := r.db.Model(&model.Transfer{}).
+		Where("id = ? AND status = ?", id, model.TransferRequested).
+		Updates(map[string]interface{}{
+			"status":        model.TransferInTransit,
+			"dispatched_by": userId,
+			"dispatched_at": now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrInvalidStatus
+	}
+
+	return nil
+}
+
+// ReceiveTransfer shelves the item at the destination branch. If it was sent
+// for a hold that is still waiting for it, the hold becomes ready; otherwise
+// the item is routed like a return.
+func (r *BookRepository) ReceiveTransfer(ctx context.Context, id, userId string) error {
+	var bookId string
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+		var transfer model.Transfer
+		if err := tx.Where("id = ?", id).First(&transfer).Error; err != nil {
+			return err
+		}
+		bookId = transfer.BookID
+
+		book, err := lockBook(tx, transfer.BookID)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		result := tx.Model(&model.Transfer{}).
+			Where("id = ? AND status = ?", id, model.TransferInTransit).
+			Updates(map[string]interface{}{
+				"status":      model.TransferReceived,
+				"received_by": userId,
+				"received_at": now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrInvalidStatus
+		}
+
+		book.CurrentBranchID = transfer.ToBranchID
+		book.InTransit = false
+		if err := tx.Model(&model.Book{}).Where("id = ?", book.ID).Updates(map[string]interface{}{
+			"current_branch_id": book.CurrentBranchID,
+			"in_transit":        false,
+		}).Error; err != nil {
+			return err
+		}
+
+		if transfer.HoldID != "" {
+			ready := tx.Model(&model.Hold{}).
+				Where("id = ? AND status = ? AND pickup_branch_id = ?", transfer.HoldID, model.HoldInTransit, transfer.ToBranchID).
+				Updates(map[string]interface{}{"status": model.HoldReady, "ready_at": now})
+			if ready.Error != nil {
+				return ready.Error
+			}
+			if ready.RowsAffected > 0 {
+				return nil
+			}
+		}
+
+		return routeItem(tx, book)
+	}); err != nil {
+		return err
+	}
+
+	return r.invalidateBook(ctx, bookId)
+}
+
+// CancelTransfer leaves the item at the branch it was sent from. A hold
+// waiting on the transfer goes back to the queue, and the item is routed
+// again from where it stands, so it can fill the next hold or head home.
+func (r *BookRepository) CancelTransfer(ctx context.Context, id string) error {
+	var bookId string
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+		var transfer model.Transfer
+		if err := tx.Where("id = ?", id).First(&transfer).Error; err != nil {
+			return err
+		}
+		bookId = transfer.BookID
+
+		book, err := lockBook(tx, transfer.BookID)
+		if err != nil {
+			return err
+		}
+
+		result := tx.Model(&model.Transfer{}).
+			Where("id = ? AND status IN ?", id, []string{model.TransferRequested, model.TransferInTransit}).
+			Update("status", model.TransferCancelled)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrInvalidStatus
+		}
+
+		if transfer.HoldID != "" {
+			if err := tx.Model(&model.Hold{}).
+				Where("id = ? AND status = ?", transfer.HoldID, model.HoldInTransit).
+				Update("status", model.HoldWaiting).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&model.Book{}).Where("id = ?", transfer.BookID).UpdateColumn("in_transit", false).Error; err != nil {
+			return err
+		}
+		book.InTransit = false
+
+		return routeItem(tx, book)
+	}); err != nil {
+		return err
+	}
+
+	return r.invalidateBook(ctx, bookId)
+}
+
+func (r *BookRepository) GetTransferById(ctx context.Context, id string) (*model.Transfer, error) {
+	var transfer model.Transfer
+	if err := r.db.Model(&model.Transfer{}).Select(transferBook).Where("id = ?", id).First(&transfer).Error; err != nil {
+		return nil, err
+	}
+
+	return &transfer, nil
+}
+
+// GetTransfers lists transfers touching a branch, which is the branch's
+// manifest: outgoing transfers to pull and pack, incoming ones to expect.
+func (r *BookRepository) GetTransfers(ctx context.Context, filter TransferFilter, opts pagination.ListOptions) (*TransferPage, error) {
+	if err := opts.Normalize(createdAtSortColumns, "created_at", "asc"); err != nil {
+		return nil, err
+	}
+
+	base := r.db.Model(&model.Transfer{}).Select(transferBook)
+	if filter.BranchID != "" {
+		switch filter.Direction {
+		case "outgoing":
+			base.Where("from_branch_id = ?", filter.BranchID)
+		case "incoming":
+			base.Where("to_branch_id = ?", filter.BranchID)
+		default:
+			base.Where("(from_branch_id = ? OR to_branch_id = ?)", filter.BranchID, filter.BranchID)
+		}
+	}
+	if filter.Status != "" {
+		base.Where("status = ?", filter.Status)
+	}
+
+	query, err := pagination.Paginate(base, opts, createdAtSortColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &TransferPage{}
+	if err := query.Find(&page.Transfers).Error; err != nil {
+		return nil, err
+	}
+
+	if len(page.Transfers) > opts.PageSize {
+		page.Transfers = page.Transfers[:opts.PageSize]
+		last := page.Transfers[len(page.Transfers)-1]
+		page.NextPageToken = pagination.EncodePageToken(opts, last.CreatedAt, last.ID)
+	}
+
+	return page, nil
+}
+
+// checkHolds is called while borrowing a physical item. An item ready for a
+// hold can only be borrowed by that hold's reader, which fulfils the hold.
+func checkHolds(tx *gorm.DB, book *model.Book, userId string) error {
+	if book.InTransit {
+		return ErrBookInTransit
+	}
+
+	var hold model.Hold
+	err := tx.Where("book_id = ? AND status = ?", book.ID, model.HoldReady).Order("created_at ASC").First(&hold).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if hold.UserID != userId {
+		return ErrBookOnHold
+	}
+
+	return tx.Model(&model.Hold{}).Where("id = ?", hold.ID).Update("status", model.HoldFulfilled).Error
+}
+
+// routeItem decides where a free item goes next: to the oldest waiting hold,
+// either readied on the spot or shipped to its pickup branch, or else back to
+// its home branch. It does nothing while the item is borrowed, moving or
+// already promised to a hold, or when its location is unknown.
+func routeItem(tx *gorm.DB, book *model.Book) error {
+	if !routable(book) {
+		return nil
+	}
+
+	var promised int64
+	if err := tx.Model(&model.Hold{}).
+		Where("book_id = ? AND status IN ?", book.ID, []string{model.HoldReady, model.HoldInTransit}).
+		Count(&promised).Error; err != nil {
+		return err
+	}
+	if promised > 0 {
+		return nil
+	}
+
+	var waiting *model.Hold
+	var hold model.Hold
+	err := tx.Where("book_id = ? AND status = ?", book.ID, model.HoldWaiting).Order("created_at ASC").First(&hold).Error
+	if err == nil {
+		waiting = &hold
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	to := nextStop(book, waiting)
+	switch {
+	case waiting != nil && to == book.CurrentBranchID:
+		return tx.Model(&model.Hold{}).Where("id = ?", waiting.ID).
+			Updates(map[string]interface{}{"status": model.HoldReady, "ready_at": time.Now()}).Error
+	case waiting != nil:
+		if err := tx.Model(&model.Hold{}).Where("id = ?", waiting.ID).Update("status", model.HoldInTransit).Error; err != nil {
+			return err
+		}
+		return startTransfer(tx, book, to, model.TransferReasonHold, waiting.ID)
+	case to != "":
+		return startTransfer(tx, book, to, model.TransferReasonReturn, "")
+	}
+
+	return nil
+}
+
+// routable reports whether routeItem may move the item: a physical item with
+// a known location that is neither lent nor already moving.
+func routable(book *model.Book) bool {
+	return book.ItemType != model.ItemDigital && !book.IsBorrowed && !book.InTransit && book.CurrentBranchID != ""
+}
+
+// nextStop returns the branch a free item should go to: the pickup branch of
+// the oldest waiting hold, or else its home branch. It returns the current
+// branch when the hold is picked up there, and "" when the item stays put.
+func nextStop(book *model.Book, waiting *model.Hold) string {
+	if waiting != nil {
+		return waiting.PickupBranchID
+	}
+	if book.HomeBranchID != book.CurrentBranchID {
+		return book.HomeBranchID
+	}
+
+	return ""
+}
+
+// onShelf reports whether a physical item is free to borrow where it is: not
+// lent, not on its way to another branch and not held for a reader.
+func onShelf(tx *gorm.DB, book *model.Book) (bool, error) {
+	if book.IsBorrowed || book.InTransit {
+		return false, nil
+	}
+
+	var ready int64
+	if err := tx.Model(&model.Hold{}).
+		Where("book_id = ? AND status = ?", book.ID, model.HoldReady).
+		Count(&ready).Error; err != nil {
+		return false, err
+	}
+
+	return ready == 0, nil
+}
+
+func startTransfer(tx *gorm.DB, book *model.Book, to, reason, holdId string) error {
+	if err := tx.Create(&model.Transfer{
+		BookID:       book.ID,
+		FromBranchID: book.CurrentBranchID,
+		ToBranchID:   to,
+		Status:       model.TransferRequested,
+		Reason:       reason,
+		HoldID:       holdId,
+	}).Error; err != nil {
+		return err
+	}
+
+	book.InTransit = true
+	return tx.Model(&model.Book{}).Where("id = ?", book.ID).UpdateColumn("in_transit", true).Error
+}
+
+func lockBook(tx *gorm.DB, id string) (*model.Book, error) {
+	var book model.Book
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&book, "id = ? AND deleted_at IS NULL", id).Error; err != nil {
+		return nil, err
+	}
+
+	return &book, nil
+}
@@ -0,0 +1,301 @@
+package repository
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/shafaalafghany/book-service/model"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestRoutable(t *testing.T) {
+	tests := []struct {
+		name string
+		book model.Book
+		want bool
+	}{
+		{name: "on the shelf", book: model.Book{ItemType: model.ItemPhysical, CurrentBranchID: "a"}, want: true},
+		{name: "digital", book: model.Book{ItemType: model.ItemDigital, CurrentBranchID: "a"}},
+		{name: "borrowed", book: model.Book{ItemType: model.ItemPhysical, CurrentBranchID: "a", IsBorrowed: true}},
+		{name: "in transit", book: model.Book{ItemType: model.ItemPhysical, CurrentBranchID: "a", InTransit: true}},
+		{name: "no location", book: model.Book{ItemType: model.ItemPhysical}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := routable(&tt.book); got != tt.want {
+				t.Errorf("routable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextStop(t *testing.T) {
+	tests := []struct {
+		name    string
+		home    string
+		current string
+		waiting *model.Hold
+		want    string
+	}{
+		{name: "at home", home: "a", current: "a"},
+		{name: "away from home", home: "a", current: "b", want: "a"},
+		{name: "no home branch", current: "b"},
+		{name: "hold here", home: "a", current: "b", waiting: &model.Hold{PickupBranchID: "b"}, want: "b"},
+		{name: "hold elsewhere", home: "a", current: "a", waiting: &model.Hold{PickupBranchID: "c"}, want: "c"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			book := &model.Book{HomeBranchID: tt.home, CurrentBranchID: tt.current}
+			if got := nextStop(book, tt.waiting); got != tt.want {
+				t.Errorf("nextStop() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRouteItem(t *testing.T) {
+	type hold struct {
+		pickup string
+		status string
+	}
+
+	tests := []struct {
+		name    string
+		home    string
+		current string
+		holds   []hold
+		// want lists the hold statuses after routing, in the order above.
+		want         []string
+		wantTransfer *model.Transfer
+		wantHold     int
+		wantOnShelf  bool
+	}{
+		{
+			name:        "stays at home",
+			home:        "a",
+			current:     "a",
+			wantHold:    -1,
+			wantOnShelf: true,
+		},
+		{
+			name:         "goes home",
+			home:         "a",
+			current:      "b",
+			wantTransfer: &model.Transfer{FromBranchID: "b", ToBranchID: "a", Reason: model.TransferReasonReturn},
+			wantHold:     -1,
+		},
+		{
+			name:     "readies a hold at the current branch",
+			home:     "a",
+			current:  "b",
+			holds:    []hold{{pickup: "b", status: model.HoldWaiting}},
+			want:     []string{model.HoldReady},
+			wantHold: -1,
+		},
+		{
+			name:         "sends the item to the oldest hold",
+			home:         "a",
+			current:      "a",
+			holds:        []hold{{pickup: "c", status: model.HoldWaiting}, {pickup: "a", status: model.HoldWaiting}},
+			want:         []string{model.HoldInTransit, model.HoldWaiting},
+			wantTransfer: &model.Transfer{FromBranchID: "a", ToBranchID: "c", Reason: model.TransferReasonHold},
+			wantHold:     0,
+		},
+		{
+			name:     "leaves an item promised to a hold",
+			home:     "a",
+			current:  "b",
+			holds:    []hold{{pickup: "b", status: model.HoldReady}, {pickup: "c", status: model.HoldWaiting}},
+			want:     []string{model.HoldReady, model.HoldWaiting},
+			wantHold: -1,
+		},
+		{
+			name:        "skips finished holds",
+			home:        "a",
+			current:     "a",
+			holds:       []hold{{pickup: "c", status: model.HoldCancelled}, {pickup: "c", status: model.HoldFulfilled}},
+			want:        []string{model.HoldCancelled, model.HoldFulfilled},
+			wantHold:    -1,
+			wantOnShelf: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := testTx(t)
+			book := createTestBook(t, tx, tt.home, tt.current)
+
+			var holds []*model.Hold
+			for _, v := range tt.holds {
+				holds = append(holds, createTestHold(t, tx, book.ID, "reader", v.pickup, v.status))
+			}
+
+			if err := routeItem(tx, book); err != nil {
+				t.Fatal(err)
+			}
+
+			for i, h := range holds {
+				var got model.Hold
+				if err := tx.First(&got, "id = ?", h.ID).Error; err != nil {
+					t.Fatal(err)
+				}
+				if got.Status != tt.want[i] {
+					t.Errorf("hold %d status = %q, want %q", i, got.Status, tt.want[i])
+				}
+			}
+
+			var transfers []*model.Transfer
+			if err := tx.Where("book_id = ?", book.ID).Find(&transfers).Error; err != nil {
+				t.Fatal(err)
+			}
+			switch {
+			case tt.wantTransfer == nil && len(transfers) > 0:
+				t.Errorf("got %d transfers, want none", len(transfers))
+			case tt.wantTransfer != nil && len(transfers) != 1:
+				t.Errorf("got %d transfers, want one", len(transfers))
+			case tt.wantTransfer != nil:
+				got := transfers[0]
+				wantHoldId := ""
+				if tt.wantHold >= 0 {
+					wantHoldId = holds[tt.wantHold].ID
+				}
+				if got.FromBranchID != tt.wantTransfer.FromBranchID || got.ToBranchID != tt.wantTransfer.ToBranchID ||
+					got.Reason != tt.wantTransfer.Reason || got.Status != model.TransferRequested || got.HoldID != wantHoldId {
+					t.Errorf("transfer = %s -> %s (%s, %s, hold %q), want %s -> %s (%s, %s, hold %q)",
+						got.FromBranchID, got.ToBranchID, got.Reason, got.Status, got.HoldID,
+						tt.wantTransfer.FromBranchID, tt.wantTransfer.ToBranchID, tt.wantTransfer.Reason, model.TransferRequested, wantHoldId)
+				}
+			}
+
+			var stored model.Book
+			if err := tx.First(&stored, "id = ?", book.ID).Error; err != nil {
+				t.Fatal(err)
+			}
+			if stored.InTransit != (tt.wantTransfer != nil) || book.InTransit != stored.InTransit {
+				t.Errorf("in_transit = %v (in memory %v), want %v", stored.InTransit, book.InTransit, tt.wantTransfer != nil)
+			}
+
+			free, err := onShelf(tx, book)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if free != tt.wantOnShelf {
+				t.Errorf("onShelf() = %v, want %v", free, tt.wantOnShelf)
+			}
+		})
+	}
+}
+
+func TestCheckHolds(t *testing.T) {
+	tests := []struct {
+		name      string
+		inTransit bool
+		holder    string
+		borrower  string
+		err       error
+		want      string
+	}{
+		{name: "no hold", borrower: "reader"},
+		{name: "ready for the borrower", holder: "reader", borrower: "reader", want: model.HoldFulfilled},
+		{name: "ready for someone else", holder: "other", borrower: "reader", err: ErrBookOnHold, want: model.HoldReady},
+		{name: "in transit", inTransit: true, borrower: "reader", err: ErrBookInTransit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := testTx(t)
+			book := createTestBook(t, tx, "a", "a")
+			book.InTransit = tt.inTransit
+
+			var hold *model.Hold
+			if tt.holder != "" {
+				hold = createTestHold(t, tx, book.ID, tt.holder, "a", model.HoldReady)
+			}
+
+			if err := checkHolds(tx, book, tt.borrower); !errors.Is(err, tt.err) {
+				t.Fatalf("checkHolds() = %v, want %v", err, tt.err)
+			}
+
+			if hold != nil {
+				var got model.Hold
+				if err := tx.First(&got, "id = ?", hold.ID).Error; err != nil {
+					t.Fatal(err)
+				}
+				if got.Status != tt.want {
+					t.Errorf("hold status = %q, want %q", got.Status, tt.want)
+				}
+			}
+		})
+	}
+}
+
+// testTx opens the database named by BOOK_TEST_DATABASE_URL and returns a
+// transaction that is rolled back when the test ends. Tests that need it are
+// skipped when the variable is not set.
+func testTx(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv("BOOK_TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("BOOK_TEST_DATABASE_URL is not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := db.Exec("CREATE EXTENSION IF NOT EXISTS \"uuid-ossp\"").Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&model.Book{}, &model.Hold{}, &model.Transfer{}); err != nil {
+		t.Fatal(err)
+	}
+
+	tx := db.Begin()
+	if tx.Error != nil {
+		t.Fatal(tx.Error)
+	}
+	t.Cleanup(func() { tx.Rollback() })
+
+	return tx
+}
+
+func createTestBook(t *testing.T, tx *gorm.DB, home, current string) *model.Book {
+	t.Helper()
+
+	book := &model.Book{
+		Name:            "The Hobbit",
+		AuthorID:        "author",
+		CategoryID:      "category",
+		CreatedBy:       "librarian",
+		ItemType:        model.ItemPhysical,
+		HomeBranchID:    home,
+		CurrentBranchID: current,
+	}
+	if err := tx.Create(book).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	return book
+}
+
+func createTestHold(t *testing.T, tx *gorm.DB, bookId, userId, pickup, status string) *model.Hold {
+	t.Helper()
+
+	hold := &model.Hold{BookID: bookId, UserID: userId, PickupBranchID: pickup, Status: status}
+	if err := tx.Create(hold).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	return hold
+}
@@ -0,0 +1,233 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/shafaalafghany/book-service/model"
+)
+
+const (
+	trendingRetentionDays = 365
+	trendingWindowTTL     = time.Minute
+)
+
+var TrendingWindows = []int{7, 30, 365}
+
+type TrendingQuery struct {
+	WindowDays int
+	CategoryID string
+	AuthorID   string
+	Limit      int
+}
+
+type TrendingBook struct {
+	Book    *model.Book
+	Borrows int64
+}
+
+type trendingCount struct {
+	Day        string
+	BookID     string
+	AuthorID   string
+	CategoryID string
+	Borrows    int64
+}
+
+func (q TrendingQuery) scope() string {
+	switch {
+	case q.CategoryID != "":
+		return ":category:" + q.CategoryID
+	case q.AuthorID != "":
+		return ":author:" + q.AuthorID
+	default:
+		return ""
+	}
+}
+
+func trendingDayKey(day, scope string) string {
+	return "trending:day:" + day + scope
+}
+
+// trendingRebuildKey holds a day set while RebuildTrending fills it in.
+func trendingRebuildKey(dayKey string) string {
+	return "trending:rebuild:" + strings.TrimPrefix(dayKey, "trending:")
+}
+
+// trendingKeyDay parses the day out of a day set key.
+func trendingKeyDay(key string) (time.Time, bool) {
+	day, _, _ := strings.Cut(strings.TrimPrefix(key, "trending:day:"), ":")
+	t, err := time.Parse("20060102", day)
+	return t, err == nil
+}
+
+func trendingWindowKey(window int, day, scope string) string {
+	return fmt.Sprintf("trending:window:%d:%s%s", window, day, scope)
+}
+
+func trendingScopes(authorId, categoryId string) []string {
+	return []string{"", ":category:" + categoryId, ":author:" + authorId}
+}
+
+func (r *BookRepository) Trending(ctx context.Context, query TrendingQuery) ([]*TrendingBook, error) {
+	today := time.Now().UTC()
+	scope := query.scope()
+
+	dest := trendingWindowKey(query.WindowDays, today.Format("20060102"), scope)
+	exists, err := r.redis.Exists(ctx, dest).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	if exists == 0 {
+		keys := make([]string, 0, query.WindowDays)
+		for i := 0; i < query.WindowDays; i++ {
+			keys = append(keys, trendingDayKey(today.AddDate(0, 0, -i).Format("20060102"), scope))
+		}
+
+		pipe := r.redis.TxPipeline()
+		pipe.ZUnionStore(ctx, dest, &redis.ZStore{Keys: keys, Aggregate: "SUM"})
+		pipe.Expire(ctx, dest, trendingWindowTTL)
+		if _, err := pipe.Exec(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	entries, err := r.redis.ZRevRangeWithScores(ctx, dest, 0, int64(query.Limit*2-1)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entries) == 0 {
+		return []*TrendingBook{}, nil
+	}
+
+	ids := make([]string, 0, len(entries))
+	for _, v := range entries {
+		ids = append(ids, v.Member.(string))
+	}
+
+	books, err := r.GetByIds(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	found := map[string]*model.Book{}
+	for _, v := range books {
+		found[v.ID] = v
+	}
+
+	result := []*TrendingBook{}
+	for _, v := range entries {
+		book, ok := found[v.Member.(string)]
+		if !ok {
+			continue
+		}
+
+		result = append(result, &TrendingBook{Book: book, Borrows: int64(v.Score)})
+		if len(result) == query.Limit {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+func (r *BookRepository) recordTrending(ctx context.Context, book *model.Book, borrowedAt time.Time) error {
+	day := borrowedAt.UTC()
+
+	pipe := r.redis.TxPipeline()
+	for _, scope := range trendingScopes(book.AuthorID, book.CategoryID) {
+		key := trendingDayKey(day.Format("20060102"), scope)
+		pipe.ZIncrBy(ctx, key, 1, book.ID)
+		pipe.ExpireAt(ctx, key, day.AddDate(0, 0, trendingRetentionDays+1))
+
+		for _, window := range TrendingWindows {
+			pipe.Del(ctx, trendingWindowKey(window, time.Now().UTC().Format("20060102"), scope))
+		}
+	}
+	_, err := pipe.Exec(ctx)
+
+	return err
+}
+
+// RebuildTrending recomputes the day sets before today from the borrow
+// records. Each set is built under a temporary key and renamed over the live
+// one, so readers never see a missing day. Today's sets are left to the
+// increments made on every borrow, which a snapshot could not include.
+func (r *BookRepository) RebuildTrending(ctx context.Context) (int64, error) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	since := today.AddDate(0, 0, -trendingRetentionDays)
+
+	var counts []trendingCount
+	if err := r.db.WithContext(ctx).
+		Table("borrow_records").
+		Select("to_char(borrow_records.borrowed_at AT TIME ZONE 'UTC', 'YYYYMMDD') AS day, borrow_records.book_id, books.author_id, books.category_id, COUNT(*) AS borrows").
+		Joins("JOIN books ON books.id::text = borrow_records.book_id").
+		Where("borrow_records.deleted_at IS NULL AND borrow_records.borrowed_at >= ? AND borrow_records.borrowed_at < ?", since, today).
+		Group("day, borrow_records.book_id, books.author_id, books.category_id").
+		Scan(&counts).Error; err != nil {
+		return 0, err
+	}
+
+	rebuilt := map[string]bool{}
+	pipe := r.redis.TxPipeline()
+	for _, v := range counts {
+		day, err := time.Parse("20060102", v.Day)
+		if err != nil {
+			return 0, err
+		}
+
+		for _, scope := range trendingScopes(v.AuthorID, v.CategoryID) {
+			key := trendingDayKey(v.Day, scope)
+			tmp := trendingRebuildKey(key)
+			if !rebuilt[key] {
+				pipe.Del(ctx, tmp)
+				rebuilt[key] = true
+			}
+			pipe.ZIncrBy(ctx, tmp, float64(v.Borrows), v.BookID)
+			pipe.ExpireAt(ctx, tmp, day.AddDate(0, 0, trendingRetentionDays+1))
+		}
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+
+	// Only past days the rebuild found no borrows for are stale. Sets for
+	// today, or for a day that began while the rebuild ran, keep their live
+	// increments.
+	var stale []string
+	iter := r.redis.Scan(ctx, 0, "trending:day:*", 100).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+		if day, ok := trendingKeyDay(key); ok && day.Before(today) && !rebuilt[key] {
+			stale = append(stale, key)
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return 0, err
+	}
+	iter = r.redis.Scan(ctx, 0, "trending:window:*", 100).Iterator()
+	for iter.Next(ctx) {
+		stale = append(stale, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return 0, err
+	}
+
+	pipe = r.redis.TxPipeline()
+	for key := range rebuilt {
+		pipe.Rename(ctx, trendingRebuildKey(key), key)
+	}
+	if len(stale) > 0 {
+		pipe.Del(ctx, stale...)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+
+	return int64(len(counts)), nil
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/shafaalafghany/book-service/model"
+	"gitlab.com/shafaalafghany/synapsis-common/pagination"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type BranchRepositoryInterface interface {
+	Create(*model.Branch) error
+	GetById(string) (*model.Branch, error)
+	GetByCode(string) (*model.Branch, error)
+	Get(pagination.ListOptions) (*BranchPage, error)
+	Update(*model.Branch, string) error
+	Delete(string) error
+}
+
+var ErrBranchInUse = errors.New("branch is still the home or location of books")
+
+type BranchRepository struct {
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+type BranchPage struct {
+	Branches      []*model.Branch
+	NextPageToken string
+	TotalCount    int64
+}
+
+var branchSortColumns = map[string]pagination.SortKind{
+	"name":       pagination.SortString,
+	"code":       pagination.SortString,
+	"created_at": pagination.SortTime,
+}
+
+const branchBookCount = "branches.*, (SELECT COUNT(*) FROM books WHERE books.home_branch_id = branches.id::text AND books.deleted_at IS NULL) AS book_count"
+
+func NewBranchRepository(db *gorm.DB, logger *zap.Logger) BranchRepositoryInterface {
+	return &BranchRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *BranchRepository) Create(data *model.Branch) error {
+	if err := r.db.Create(data).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *BranchRepository) GetById(id string) (*model.Branch, error) {
+	var branch model.Branch
+	if err := r.db.Model(&model.Branch{}).
+		Select(branchBookCount).
+		Where("id = ? AND deleted_at IS NULL", id).
+		First(&branch).Error; err != nil {
+		return nil, err
+	}
+
+	return &branch, nil
+}
+
+func (r *BranchRepository) GetByCode(code string) (*model.Branch, error) {
+	var branch model.Branch
+	if err := r.db.Where("code = ? AND deleted_at IS NULL", code).First(&branch).Error; err != nil {
+		return nil, err
+	}
+
+	return &branch, nil
+}
+
+func (r *BranchRepository) Get(opts pagination.ListOptions) (*BranchPage, error) {
+	if err := opts.Normalize(branchSortColumns, "name", "asc"); err != nil {
+		return nil, err
+	}
+
+	base := r.db.Model(&model.Branch{}).Where("deleted_at IS NULL")
+
+	if opts.Search != "" {
+		base.Where("name ILIKE ? OR code ILIKE ?", "%"+opts.Search+"%", "%"+opts.Search+"%")
+	}
+
+	page := &BranchPage{}
+	if opts.IncludeTotal {
+		if err := base.Session(&gorm.Session{}).Count(&page.TotalCount).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	query, err := pagination.Paginate(base.Select(branchBookCount), opts, branchSortColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := query.Find(&page.Branches).Error; err != nil {
+		return nil, err
+	}
+
+	if len(page.Branches) > opts.PageSize {
+		page.Branches = page.Branches[:opts.PageSize]
+		last := page.Branches[len(page.Branches)-1]
+		var value interface{}
+		switch opts.SortBy {
+		case "code":
+			value = last.Code
+		case "created_at":
+			value = last.CreatedAt
+		default:
+			value = last.Name
+		}
+		page.NextPageToken = pagination.EncodePageToken(opts, value, last.ID)
+	}
+
+	return page, nil
+}
+
+func (r *BranchRepository) Update(data *model.Branch, id string) error {
+	updatedData := map[string]interface{}{
+		"name":    data.Name,
+		"code":    data.Code,
+		"address": data.Address,
+		"phone":   data.Phone,
+	}
+
+	if err := r.db.Model(&model.Branch{}).Where("id = ? AND deleted_at IS NULL", id).Updates(updatedData).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// Delete soft-deletes a branch. It fails with ErrBranchInUse while any book
+// still has the branch as its home or current location.
+func (r *BranchRepository) Delete(id string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&model.Book{}).
+			Where("(home_branch_id = ? OR current_branch_id = ?) AND deleted_at IS NULL", id, id).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrBranchInUse
+		}
+
+		return tx.Model(&model.Branch{}).Where("id = ? AND deleted_at IS NULL", id).Update("deleted_at", time.Now()).Error
+	})
+}
@@ -0,0 +1,220 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shafaalafghany/book-service/model"
+	"gitlab.com/shafaalafghany/synapsis-common/pagination"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReadingListRepositoryInterface interface {
+	Create(*model.ReadingList) error
+	GetById(string) (*model.ReadingList, error)
+	GetByShareToken(string) (*model.ReadingList, error)
+	GetByUser(string, string, pagination.ListOptions) (*ReadingListPage, error)
+	Update(*model.ReadingList, string) error
+	Delete(string) error
+	GetItems(string) ([]*model.ReadingListItem, error)
+	AddItem(*model.ReadingListItem) error
+	RemoveItem(string, string) (int64, error)
+	Reorder(string, []string) error
+	GetNotifications(string, pagination.ListOptions) (*NotificationPage, error)
+}
+
+type ReadingListRepository struct {
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+type ReadingListPage struct {
+	Lists         []*model.ReadingList
+	NextPageToken string
+}
+
+type NotificationPage struct {
+	Notifications []*model.Notification
+	NextPageToken string
+}
+
+var createdAtSortColumns = map[string]pagination.SortKind{
+	"created_at": pagination.SortTime,
+}
+
+func NewReadingListRepository(db *gorm.DB, logger *zap.Logger) ReadingListRepositoryInterface {
+	return &ReadingListRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *ReadingListRepository) Create(data *model.ReadingList) error {
+	if err := r.db.Create(data).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *ReadingListRepository) GetById(id string) (*model.ReadingList, error) {
+	var list model.ReadingList
+	if err := r.db.Where("id = ? AND deleted_at IS NULL", id).First(&list).Error; err != nil {
+		return nil, err
+	}
+
+	return &list, nil
+}
+
+func (r *ReadingListRepository) GetByShareToken(token string) (*model.ReadingList, error) {
+	var list model.ReadingList
+	if err := r.db.Where("share_token = ? AND visibility = ? AND deleted_at IS NULL", token, model.ListLink).First(&list).Error; err != nil {
+		return nil, err
+	}
+
+	return &list, nil
+}
+
+func (r *ReadingListRepository) GetByUser(userId, kind string, opts pagination.ListOptions) (*ReadingListPage, error) {
+	if err := opts.Normalize(createdAtSortColumns, "created_at", "desc"); err != nil {
+		return nil, err
+	}
+
+	base := r.db.Model(&model.ReadingList{}).Where("user_id = ? AND deleted_at IS NULL", userId)
+
+	if kind != "" {
+		base.Where("kind = ?", kind)
+	}
+
+	query, err := pagination.Paginate(base, opts, createdAtSortColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &ReadingListPage{}
+	if err := query.Find(&page.Lists).Error; err != nil {
+		return nil, err
+	}
+
+	if len(page.Lists) > opts.PageSize {
+		page.Lists = page.Lists[:opts.PageSize]
+		last := page.Lists[len(page.Lists)-1]
+		page.NextPageToken = pagination.EncodePageToken(opts, last.CreatedAt, last.ID)
+	}
+
+	return page, nil
+}
+
+func (r *ReadingListRepository) Update(data *model.ReadingList, id string) error {
+	updatedData := map[string]interface{}{
+		"name":        data.Name,
+		"visibility":  data.Visibility,
+		"share_token": data.ShareToken,
+	}
+
+	if err := r.db.Model(&model.ReadingList{}).Where("id = ? AND deleted_at IS NULL", id).Updates(updatedData).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *ReadingListRepository) Delete(id string) error {
+	if err := r.db.Model(&model.ReadingList{}).Where("id = ? AND deleted_at IS NULL", id).Update("deleted_at", time.Now()).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *ReadingListRepository) GetItems(listId string) ([]*model.ReadingListItem, error) {
+	var items []*model.ReadingListItem
+	if err := r.db.Where("list_id = ?", listId).Order("position ASC").Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (r *ReadingListRepository) AddItem(data *model.ReadingListItem) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var list model.ReadingList
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&list, "id = ?", data.ListID).Error; err != nil {
+			return err
+		}
+
+		var position int
+		if err := tx.Model(&model.ReadingListItem{}).Where("list_id = ?", data.ListID).
+			Select("COALESCE(MAX(position), 0)").Scan(&position).Error; err != nil {
+			return err
+		}
+
+		data.Position = position + 1
+		return tx.Create(data).Error
+	})
+}
+
+func (r *ReadingListRepository) RemoveItem(listId, bookId string) (int64, error) {
+	result := r.db.Where("list_id = ? AND book_id = ?", listId, bookId).Delete(&model.ReadingListItem{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+func (r *ReadingListRepository) Reorder(listId string, bookIds []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i, bookId := range bookIds {
+			if err := tx.Model(&model.ReadingListItem{}).
+				Where("list_id = ? AND book_id = ?", listId, bookId).
+				Update("position", i+1).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *ReadingListRepository) GetNotifications(userId string, opts pagination.ListOptions) (*NotificationPage, error) {
+	if err := opts.Normalize(createdAtSortColumns, "created_at", "desc"); err != nil {
+		return nil, err
+	}
+
+	base := r.db.Model(&model.Notification{}).Where("user_id = ?", userId)
+
+	query, err := pagination.Paginate(base, opts, createdAtSortColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &NotificationPage{}
+	if err := query.Find(&page.Notifications).Error; err != nil {
+		return nil, err
+	}
+
+	if len(page.Notifications) > opts.PageSize {
+		page.Notifications = page.Notifications[:opts.PageSize]
+		last := page.Notifications[len(page.Notifications)-1]
+		page.NextPageToken = pagination.EncodePageToken(opts, last.CreatedAt, last.ID)
+	}
+
+	return page, nil
+}
+
+func notifyAvailable(tx *gorm.DB, book *model.Book) error {
+	if err := tx.Exec(`
+		INSERT INTO notifications (id, user_id, book_id, message, created_at)
+		SELECT uuid_generate_v4(), waiting.user_id, ?, ?, NOW()
+		FROM (
+			SELECT DISTINCT reading_lists.user_id FROM reading_list_items
+			JOIN reading_lists ON reading_lists.id::text = reading_list_items.list_id
+			WHERE reading_list_items.book_id = ? AND reading_list_items.notify_when_available AND reading_lists.deleted_at IS NULL
+		) AS waiting`,
+		book.ID, fmt.Sprintf("%s is available to borrow", book.Name), book.ID).Error; err != nil {
+		return err
+	}
+
+	return tx.Model(&model.ReadingListItem{}).
+		Where("book_id = ? AND notify_when_available", book.ID).
+		Update("notify_when_available", false).Error
+}
@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/shafaalafghany/book-service/model"
+	"gitlab.com/shafaalafghany/synapsis-common/pagination"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type ReviewRepositoryInterface interface {
+	Create(*model.Review) error
+	GetById(string) (*model.Review, error)
+	GetByBookAndUser(string, string) (*model.Review, error)
+	Get(ReviewQuery, pagination.ListOptions) (*ReviewPage, error)
+	Update(*model.Review, string) error
+	Delete(string) error
+	Moderate(*model.Review, string) error
+	HasReturnedBorrow(string, string) (bool, error)
+}
+
+type ReviewRepository struct {
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+type ReviewQuery struct {
+	BookID        string
+	IncludeHidden bool
+}
+
+type ReviewPage struct {
+	Reviews       []*model.Review
+	NextPageToken string
+	TotalCount    int64
+}
+
+var reviewSortColumns = map[string]pagination.SortKind{
+	"rating":     pagination.SortInt,
+	"created_at": pagination.SortTime,
+	"updated_at": pagination.SortTime,
+}
+
+func NewReviewRepository(db *gorm.DB, logger *zap.Logger) ReviewRepositoryInterface {
+	return &ReviewRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *ReviewRepository) Create(data *model.Review) error {
+	if err := r.db.Create(data).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *ReviewRepository) GetById(id string) (*model.Review, error) {
+	var review model.Review
+	if err := r.db.Where("id = ? AND deleted_at IS NULL", id).First(&review).Error; err != nil {
+		return nil, err
+	}
+
+	return &review, nil
+}
+
+func (r *ReviewRepository) GetByBookAndUser(bookId, userId string) (*model.Review, error) {
+	var review model.Review
+	if err := r.db.Where("book_id = ? AND user_id = ? AND deleted_at IS NULL", bookId, userId).First(&review).Error; err != nil {
+		return nil, err
+	}
+
+	return &review, nil
+}
+
+func (r *ReviewRepository) Get(query ReviewQuery, opts pagination.ListOptions) (*ReviewPage, error) {
+	if err := opts.Normalize(reviewSortColumns, "created_at", "desc"); err != nil {
+		return nil, err
+	}
+
+	base := r.db.Model(&model.Review{}).Where("book_id = ? AND deleted_at IS NULL", query.BookID)
+
+	if !query.IncludeHidden {
+		base.Where("status <> ?", model.ReviewHidden)
+	}
+
+	page := &ReviewPage{}
+
+	if opts.IncludeTotal {
+		if err := base.Session(&gorm.Session{}).Count(&page.TotalCount).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	paged, err := pagination.Paginate(base.Session(&gorm.Session{}), opts, reviewSortColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := paged.Find(&page.Reviews).Error; err != nil {
+		return nil, err
+	}
+
+	if len(page.Reviews) > opts.PageSize {
+		page.Reviews = page.Reviews[:opts.PageSize]
+		last := page.Reviews[len(page.Reviews)-1]
+		page.NextPageToken = pagination.EncodePageToken(opts, reviewSortValue(last, opts.SortBy), last.ID)
+	}
+
+	return page, nil
+}
+
+func (r *ReviewRepository) Update(data *model.Review, id string) error {
+	updatedData := map[string]interface{}{
+		"rating": data.Rating,
+		"body":   data.Body,
+	}
+
+	if err := r.db.Model(&model.Review{}).Where("id = ? AND deleted_at IS NULL", id).Updates(updatedData).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *ReviewRepository) Delete(id string) error {
+	if err := r.db.Model(&model.Review{}).Where("id = ? AND deleted_at IS NULL", id).Update("deleted_at", time.Now()).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *ReviewRepository) Moderate(data *model.Review, id string) error {
+	moderatedData := map[string]interface{}{
+		"status":          data.Status,
+		"moderation_note": data.ModerationNote,
+		"moderated_by":    data.ModeratedBy,
+	}
+
+	if err := r.db.Model(&model.Review{}).Where("id = ? AND deleted_at IS NULL", id).Updates(moderatedData).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *ReviewRepository) HasReturnedBorrow(bookId, userId string) (bool, error) {
+	var count int64
+	if err := r.db.Model(&model.BorrowRecord{}).
+		Where("book_id = ? AND user_id = ? AND returned_at IS NOT NULL AND deleted_at IS NULL", bookId, userId).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func reviewSortValue(data *model.Review, sortBy string) interface{} {
+	switch sortBy {
+	case "rating":
+		return data.Rating
+	case "updated_at":
+		return data.UpdatedAt
+	default:
+		return data.CreatedAt
+	}
+}
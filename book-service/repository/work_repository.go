@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"github.com/shafaalafghany/book-service/model"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type WorkRepositoryInterface interface {
+	CreateWork(*model.Work) error
+	GetWorkById(string) (*model.Work, error)
+	UpdateWork(*model.Work, string) error
+	CreateSeries(*model.Series) error
+	GetSeriesById(string) (*model.Series, error)
+	UpdateSeries(*model.Series, string) error
+}
+
+type WorkRepository struct {
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+func NewWorkRepository(db *gorm.DB, logger *zap.Logger) WorkRepositoryInterface {
+	return &WorkRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *WorkRepository) CreateWork(data *model.Work) error {
+	if err := r.db.Create(data).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *WorkRepository) GetWorkById(id string) (*model.Work, error) {
+	var work model.Work
+	if err := r.db.Model(&model.Work{}).
+		Select("works.*, (SELECT COUNT(*) FROM books WHERE books.work_id = works.id::text AND books.deleted_at IS NULL) AS edition_count").
+		Where("id = ? AND deleted_at IS NULL", id).
+		First(&work).Error; err != nil {
+		return nil, err
+	}
+
+	return &work, nil
+}
+
+func (r *WorkRepository) UpdateWork(data *model.Work, id string) error {
+	updatedData := map[string]interface{}{
+		"title":       data.Title,
+		"description": data.Description,
+	}
+
+	if err := r.db.Model(&model.Work{}).Where("id = ? AND deleted_at IS NULL", id).Updates(updatedData).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *WorkRepository) CreateSeries(data *model.Series) error {
+	if err := r.db.Create(data).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *WorkRepository) GetSeriesById(id string) (*model.Series, error) {
+	var series model.Series
+	if err := r.db.Model(&model.Series{}).
+		Select("series.*, (SELECT COUNT(*) FROM books WHERE books.series_id = series.id::text AND books.deleted_at IS NULL) AS book_count").
+		Where("id = ? AND deleted_at IS NULL", id).
+		First(&series).Error; err != nil {
+		return nil, err
+	}
+
+	return &series, nil
+}
+
+func (r *WorkRepository) UpdateSeries(data *model.Series, id string) error {
+	updatedData := map[string]interface{}{
+		"name":        data.Name,
+		"description": data.Description,
+	}
+
+	if err := r.db.Model(&model.Series{}).Where("id = ? AND deleted_at IS NULL", id).Updates(updatedData).Error; err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,302 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/shafaalafghany/book-service/marc"
+	"github.com/shafaalafghany/book-service/model"
+	"gitlab.com/shafaalafghany/synapsis-common/pagination"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/book"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const exportChunkSize = 64 << 10
+
+var exportContentTypes = map[book.CatalogFormat]string{
+	book.CatalogFormat_FORMAT_CSV:     "text/csv",
+	book.CatalogFormat_FORMAT_MARC21:  "application/marc",
+	book.CatalogFormat_FORMAT_MARCXML: "application/marcxml+xml",
+}
+
+// ExportBooks streams the requested books, or every book matching the filter,
+// in the same formats ImportBooks accepts.
+func (s *BookService) ExportBooks(body *book.ExportBooksRequest, stream book.BookService_ExportBooksServer) error {
+	ctx := stream.Context()
+
+	contentType, ok := exportContentTypes[body.GetFormat()]
+	if !ok {
+		return status.Error(codes.InvalidArgument, "unknown export format")
+	}
+
+	out := &exportStream{stream: stream, contentType: contentType}
+	var w bookWriter
+	switch body.GetFormat() {
+	case book.CatalogFormat_FORMAT_CSV:
+		w = newCSVBookWriter(out)
+	case book.CatalogFormat_FORMAT_MARC21:
+		w = &marcBookWriter{w: marc.NewBinaryWriter(out)}
+	case book.CatalogFormat_FORMAT_MARCXML:
+		w = &marcBookWriter{w: marc.NewXMLWriter(out)}
+	}
+
+	if len(body.GetIds()) > 0 {
+		ids, invalid, err := batchIds(body.GetIds())
+		if err != nil {
+			return err
+		}
+		if len(invalid) > 0 {
+			return status.Error(codes.NotFound, fmt.Sprintf("book %s not found", invalid[0]))
+		}
+
+		books, err := s.repo.GetByIds(ctx, ids)
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+
+		byId := map[string]*model.Book{}
+		for _, v := range books {
+			byId[v.ID] = v
+		}
+		for _, id := range ids {
+			if _, ok := byId[id]; !ok {
+				return status.Error(codes.NotFound, fmt.Sprintf("book %s not found", id))
+			}
+		}
+
+		for _, id := range ids {
+			if err := w.Write(byId[id]); err != nil {
+				return exportError(byId[id], err)
+			}
+		}
+	} else {
+		filter, err := bookFilter(body.GetFilter())
+		if err != nil {
+			return err
+		}
+
+		opts := pagination.ListOptions{PageSize: maxBatchSize}
+		for {
+			page, err := s.repo.Get(ctx, filter, opts)
+			if err != nil {
+				return listError(err)
+			}
+
+			for _, v := range page.Books {
+				if err := w.Write(v); err != nil {
+					return exportError(v, err)
+				}
+			}
+
+			if page.NextPageToken == "" {
+				break
+			}
+			opts.PageToken = page.NextPageToken
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	return out.Flush()
+}
+
+func exportError(data *model.Book, err error) error {
+	if errors.Is(err, marc.ErrInvalidRecord) {
+		return status.Error(codes.FailedPrecondition, fmt.Sprintf("book %s cannot be exported: %v", data.ID, err))
+	}
+	return err
+}
+
+// exportStream buffers written bytes into ExportChunk messages. The first
+// chunk carries the content type.
+type exportStream struct {
+	stream      book.BookService_ExportBooksServer
+	contentType string
+	buf         bytes.Buffer
+	sent        bool
+}
+
+func (e *exportStream) Write(p []byte) (int, error) {
+	e.buf.Write(p)
+	for e.buf.Len() >= exportChunkSize {
+		if err := e.send(e.buf.Next(exportChunkSize)); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+func (e *exportStream) Flush() error {
+	if e.buf.Len() == 0 && e.sent {
+		return nil
+	}
+	return e.send(e.buf.Next(e.buf.Len()))
+}
+
+func (e *exportStream) send(data []byte) error {
+	chunk := &book.ExportChunk{Data: data}
+	if !e.sent {
+		chunk.ContentType = e.contentType
+		e.sent = true
+	}
+	return e.stream.Send(chunk)
+}
+
+type bookWriter interface {
+	Write(*model.Book) error
+	Close() error
+}
+
+type marcBookWriter struct {
+	w marc.Writer
+}
+
+func (m *marcBookWriter) Write(data *model.Book) error {
+	return m.w.Write(toMarcBook(data).Record())
+}
+
+func (m *marcBookWriter) Close() error {
+	return m.w.Close()
+}
+
+// csvBookWriter writes the columns ImportBooks reads, with authors and the
+// category by name so the file can be loaded into another catalog.
+type csvBookWriter struct {
+	w      *csv.Writer
+	header bool
+}
+
+func newCSVBookWriter(w io.Writer) *csvBookWriter {
+	return &csvBookWriter{w: csv.NewWriter(w)}
+}
+
+func (c *csvBookWriter) Write(data *model.Book) error {
+	if !c.header {
+		if err := c.w.Write(importColumns); err != nil {
+			return err
+		}
+		c.header = true
+	}
+
+	var authors []string
+	for _, v := range data.Contributors {
+		if v.Role == model.ContributorAuthor {
+			authors = append(authors, v.AuthorName)
+		}
+	}
+
+	return c.w.Write([]string{
+		data.Name,
+		strings.Join(authors, "; "),
+		data.CategoryName,
+		data.ISBN,
+		data.Publisher,
+		optionalInt(data.PublishedYear),
+		data.Language,
+		optionalInt(data.PageCount),
+		data.Edition,
+		data.Description,
+	})
+}
+
+func (c *csvBookWriter) Close() error {
+	if !c.header {
+		if err := c.w.Write(importColumns); err != nil {
+			return err
+		}
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func optionalInt(v int) string {
+	if v == 0 {
+		return ""
+	}
+	return strconv.Itoa(v)
+}
+
+func toMarcBook(data *model.Book) *marc.Book {
+	res := &marc.Book{
+		ID:            data.ID,
+		Title:         data.Name,
+		ISBN:          data.ISBN,
+		Publisher:     data.Publisher,
+		PublishedYear: data.PublishedYear,
+		Language:      data.Language,
+		Edition:       data.Edition,
+		PageCount:     data.PageCount,
+		Description:   data.Description,
+	}
+
+	for _, v := range data.Contributors {
+		res.Contributors = append(res.Contributors, marc.Contributor{Name: v.AuthorName, Role: v.Role})
+	}
+	if data.CategoryName != "" {
+		res.Subjects = []string{data.CategoryName}
+	}
+
+	return res
+}
+
+// parseMarcImport turns each bibliographic record into an import row. The
+// first 650 subject is used as the category, and contributors with roles the
+// catalog does not know are skipped. Rows are numbered by record.
+func parseMarcImport(reader marc.Reader) ([]*importRow, error) {
+	var rows []*importRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+
+		if len(rows) == maxImportRows {
+			return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("import cannot have more than %d records", maxImportRows))
+		}
+
+		row := newImportRow(len(rows) + 1)
+		rows = append(rows, row)
+
+		if !record.ValidUTF8() {
+			row.fail("record is not UTF-8 encoded")
+			continue
+		}
+
+		data := marc.ParseBook(record)
+		for _, v := range data.Contributors {
+			if validContributorRole(v.Role) {
+				row.addContributor(v.Name, v.Role)
+			}
+		}
+		if len(data.Subjects) > 0 {
+			row.category = data.Subjects[0]
+		}
+
+		row.validate(&book.Book{
+			Name:          data.Title,
+			Isbn:          data.ISBN,
+			Publisher:     data.Publisher,
+			PublishedYear: int32(data.PublishedYear),
+			Language:      data.Language,
+			PageCount:     int32(data.PageCount),
+			Edition:       data.Edition,
+			Description:   data.Description,
+		})
+	}
+
+	if len(rows) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "import has no records")
+	}
+
+	return rows, nil
+}
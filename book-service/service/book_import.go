@@ -0,0 +1,580 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/shafaalafghany/book-service/marc"
+	"github.com/shafaalafghany/book-service/model"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/author"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/book"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/category"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"gorm.io/gorm"
+)
+
+const (
+	maxImportBytes = 10 << 20
+	maxImportRows  = 5000
+
+	importCreated = "created"
+	importValid   = "valid"
+	importFailed  = "failed"
+)
+
+var importColumns = []string{"name", "authors", "category", "isbn", "publisher", "published_year", "language", "page_count", "edition", "description"}
+
+var requiredImportColumns = []string{"name", "authors", "category"}
+
+type importRow struct {
+	result       *book.ImportRowResult
+	data         *model.Book
+	contributors []importContributor
+	category     string
+}
+
+// importContributor references an author by id or by name.
+type importContributor struct {
+	ref  string
+	role string
+}
+
+func newImportRow(row int) *importRow {
+	return &importRow{result: &book.ImportRowResult{Row: int32(row)}}
+}
+
+func (r *importRow) fail(format string, args ...any) {
+	r.result.Errors = append(r.result.Errors, fmt.Sprintf(format, args...))
+}
+
+func (r *importRow) ok() bool {
+	return len(r.result.Errors) == 0
+}
+
+func (r *importRow) addContributor(ref, role string) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return
+	}
+
+	c := importContributor{ref: ref, role: role}
+	if !slices.Contains(r.contributors, c) {
+		r.contributors = append(r.contributors, c)
+	}
+}
+
+// validate applies the checks CreateBook makes to a parsed row, whatever
+// format it came from.
+func (r *importRow) validate(body *book.Book) {
+	r.result.Name = body.GetName()
+	if body.GetName() == "" {
+		r.fail("name cannot be empty")
+	}
+
+	if !slices.ContainsFunc(r.contributors, func(c importContributor) bool { return c.role == model.ContributorAuthor }) {
+		r.fail("at least one author is required")
+	}
+	if len(r.contributors) > maxContributors {
+		r.fail("a book cannot have more than %d contributors", maxContributors)
+	}
+
+	if r.category == "" {
+		r.fail("category cannot be empty")
+	}
+
+	r.data = &model.Book{Name: body.GetName()}
+	if err := applyMetadata(r.data, body); err != nil {
+		r.fail("%s", status.Convert(err).Message())
+	}
+}
+
+func (r *importRow) authorRefs() []string {
+	refs := make([]string, 0, len(r.contributors))
+	for _, c := range r.contributors {
+		refs = append(refs, c.ref)
+	}
+	return refs
+}
+
+func (r *importRow) categoryRefs() []string {
+	return []string{r.category}
+}
+
+// ImportBooks reads a CSV, MARC21 or MARCXML file streamed in chunks and
+// validates every row. Authors and categories may be referenced by id or by
+// name; unknown names are created only when the import is committed. Nothing
+// is written unless the first message sets commit.
+func (s *BookService) ImportBooks(stream book.BookService_ImportBooksServer) error {
+	ctx := stream.Context()
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return status.Error(codes.Internal, "missing outgoing metadata")
+	}
+	outbondCtx := metadata.NewOutgoingContext(ctx, md)
+
+	userData, err := s.userSvc.GetUser(outbondCtx, &emptypb.Empty{})
+	if err != nil {
+		return status.Error(codes.Unauthenticated, "invalid user")
+	}
+	if !isModerator(userData.GetRole()) {
+		return status.Error(codes.PermissionDenied, "only librarians and admins can import books")
+	}
+
+	first, raw, err := receiveImport(stream)
+	if err != nil {
+		return err
+	}
+
+	var rows []*importRow
+	switch first.GetFormat() {
+	case book.CatalogFormat_FORMAT_CSV:
+		rows, err = parseCSVImport(raw)
+	case book.CatalogFormat_FORMAT_MARC21:
+		rows, err = parseMarcImport(marc.NewBinaryReader(bytes.NewReader(raw)))
+	case book.CatalogFormat_FORMAT_MARCXML:
+		rows, err = parseMarcImport(marc.NewXMLReader(bytes.NewReader(raw)))
+	default:
+		err = status.Error(codes.InvalidArgument, "unknown import format")
+	}
+	if err != nil {
+		return err
+	}
+
+	authors, err := s.lookupImportAuthors(outbondCtx, rows)
+	if err != nil {
+		return err
+	}
+	categories, err := s.lookupImportCategories(outbondCtx, rows)
+	if err != nil {
+		return err
+	}
+
+	if err := s.checkImportISBNs(ctx, rows); err != nil {
+		return err
+	}
+
+	newAuthors := missingImportNames(rows, authors, (*importRow).authorRefs)
+	newCategories := missingImportNames(rows, categories, (*importRow).categoryRefs)
+
+	res := &book.ImportBooksResponse{
+		TotalRows:         int32(len(rows)),
+		CreatedAuthors:    newAuthors,
+		CreatedCategories: newCategories,
+		Rows:              make([]*book.ImportRowResult, 0, len(rows)),
+	}
+
+	var valid []*importRow
+	for _, row := range rows {
+		if row.ok() {
+			row.result.Status = importValid
+			valid = append(valid, row)
+		} else {
+			row.result.Status = importFailed
+			res.FailedRows++
+		}
+		res.Rows = append(res.Rows, row.result)
+	}
+	res.ValidRows = int32(len(valid))
+
+	if !first.GetCommit() || len(valid) == 0 {
+		return stream.SendAndClose(res)
+	}
+
+	for _, names := range chunk(newAuthors) {
+		created, err := s.authorSvc.Resolve(outbondCtx, &author.ResolveAuthorsRequest{Names: names, CreateMissing: true})
+		if err != nil {
+			return status.Error(codes.Internal, "failed to create authors")
+		}
+		for _, v := range created.GetAuthors() {
+			authors[strings.ToLower(v.GetName())] = importRef{id: v.GetId(), name: v.GetName()}
+		}
+	}
+	for _, names := range chunk(newCategories) {
+		created, err := s.categorySvc.Resolve(outbondCtx, &category.ResolveCategoriesRequest{Names: names, CreateMissing: true})
+		if err != nil {
+			return status.Error(codes.Internal, "failed to create categories")
+		}
+		for _, v := range created.GetCategories() {
+			categories[strings.ToLower(v.GetName())] = importRef{id: v.GetId(), name: v.GetName()}
+		}
+	}
+
+	books := make([]*model.Book, 0, len(valid))
+	for _, row := range valid {
+		data := row.data
+		data.CreatedBy = userData.GetId()
+
+		for i, c := range row.contributors {
+			a := authors[importKey(c.ref)]
+			data.Contributors = append(data.Contributors, &model.BookContributor{
+				AuthorID:   a.id,
+				AuthorName: a.name,
+				Role:       c.role,
+				Position:   i + 1,
+			})
+		}
+		primary := primaryAuthor(data.Contributors)
+		data.AuthorID = primary.AuthorID
+		data.AuthorName = primary.AuthorName
+
+		c := categories[importKey(row.category)]
+		data.CategoryID = c.id
+		data.CategoryName = c.name
+
+		books = append(books, data)
+	}
+
+	failed, err := s.repo.CreateBatch(ctx, books)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	for i, row := range valid {
+		if err := failed[i]; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				row.fail("a book with the same isbn was created while importing")
+			} else {
+				row.fail("%s", err.Error())
+			}
+			row.result.Status = importFailed
+			res.FailedRows++
+			continue
+		}
+
+		row.result.Status = importCreated
+		row.result.BookId = row.data.ID
+		res.CreatedBooks++
+	}
+	res.Committed = true
+	res.UnusedAuthors = unusedImportNames(newAuthors, valid, (*importRow).authorRefs)
+	res.UnusedCategories = unusedImportNames(newCategories, valid, (*importRow).categoryRefs)
+
+	return stream.SendAndClose(res)
+}
+
+// receiveImport collects the streamed file. Options are read from the first
+// message.
+func receiveImport(stream book.BookService_ImportBooksServer) (*book.ImportBooksRequest, []byte, error) {
+	var first *book.ImportBooksRequest
+	var data bytes.Buffer
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if first == nil {
+			first = msg
+		}
+		if data.Len()+len(msg.GetData()) > maxImportBytes {
+			return nil, nil, status.Error(codes.ResourceExhausted, fmt.Sprintf("import cannot be larger than %d bytes", maxImportBytes))
+		}
+		data.Write(msg.GetData())
+	}
+
+	if data.Len() == 0 {
+		return nil, nil, status.Error(codes.InvalidArgument, "no import data received")
+	}
+
+	return first, data.Bytes(), nil
+}
+
+// parseCSVImport reads the header and every row. Row numbers are CSV line
+// numbers so they match what the user sees in a spreadsheet.
+func parseCSVImport(data []byte) ([]*importRow, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\ufeff"))))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, status.Error(codes.InvalidArgument, "import is missing a header row")
+	}
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid csv: %v", err))
+	}
+
+	columns := map[string]int{}
+	for i, v := range header {
+		name := strings.ToLower(strings.TrimSpace(v))
+		if !slices.Contains(importColumns, name) {
+			return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("unknown column %q, expected any of %s", v, strings.Join(importColumns, ", ")))
+		}
+		if _, ok := columns[name]; ok {
+			return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("column %q is listed more than once", name))
+		}
+		columns[name] = i
+	}
+	for _, v := range requiredImportColumns {
+		if _, ok := columns[v]; !ok {
+			return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("import is missing the %s column", v))
+		}
+	}
+
+	var rows []*importRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid csv: %v", err))
+		}
+
+		if len(rows) == maxImportRows {
+			return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("import cannot have more than %d rows", maxImportRows))
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := newImportRow(line)
+		rows = append(rows, row)
+
+		if len(record) != len(header) {
+			row.fail("expected %d fields, got %d", len(header), len(record))
+			continue
+		}
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		number := func(name string) int32 {
+			v := field(name)
+			if v == "" {
+				return 0
+			}
+			n, err := strconv.ParseInt(v, 10, 32)
+			if err != nil {
+				row.fail("%s must be a whole number", name)
+			}
+			return int32(n)
+		}
+
+		for _, v := range strings.Split(field("authors"), ";") {
+			row.addContributor(v, model.ContributorAuthor)
+		}
+		row.category = field("category")
+
+		row.validate(&book.Book{
+			Name:          field("name"),
+			Isbn:          field("isbn"),
+			Publisher:     field("publisher"),
+			PublishedYear: number("published_year"),
+			Language:      field("language"),
+			PageCount:     number("page_count"),
+			Edition:       field("edition"),
+			Description:   field("description"),
+		})
+	}
+
+	if len(rows) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "import has no rows")
+	}
+
+	return rows, nil
+}
+
+// importRef is an author or category found for an import, keyed by id or by
+// lower-cased name.
+type importRef struct {
+	id   string
+	name string
+}
+
+func importKey(ref string) string {
+	if isUUID(ref) {
+		return ref
+	}
+	return strings.ToLower(ref)
+}
+
+func (s *BookService) lookupImportAuthors(ctx context.Context, rows []*importRow) (map[string]importRef, error) {
+	ids, names := importRefs(rows, (*importRow).authorRefs)
+	found := map[string]importRef{}
+
+	for _, batch := range chunk(ids) {
+		res, err := s.authorSvc.BatchGet(ctx, &author.BatchGetAuthorsRequest{Ids: batch})
+		if err != nil {
+			return nil, status.Error(codes.Internal, "failed to load authors")
+		}
+		for _, v := range res.GetAuthors() {
+			found[v.GetId()] = importRef{id: v.GetId(), name: v.GetName()}
+		}
+	}
+	for _, batch := range chunk(names) {
+		res, err := s.authorSvc.Resolve(ctx, &author.ResolveAuthorsRequest{Names: batch})
+		if err != nil {
+			return nil, status.Error(codes.Internal, "failed to load authors")
+		}
+		for _, v := range res.GetAuthors() {
+			found[strings.ToLower(v.GetName())] = importRef{id: v.GetId(), name: v.GetName()}
+		}
+	}
+
+	for _, row := range rows {
+		for _, ref := range row.authorRefs() {
+			if _, ok := found[ref]; !ok && isUUID(ref) {
+				row.fail("author %s not found", ref)
+			}
+		}
+	}
+
+	return found, nil
+}
+
+func (s *BookService) lookupImportCategories(ctx context.Context, rows []*importRow) (map[string]importRef, error) {
+	ids, names := importRefs(rows, (*importRow).categoryRefs)
+	found := map[string]importRef{}
+
+	for _, batch := range chunk(ids) {
+		res, err := s.categorySvc.BatchGet(ctx, &category.BatchGetCategoriesRequest{Ids: batch})
+		if err != nil {
+			return nil, status.Error(codes.Internal, "failed to load categories")
+		}
+		for _, v := range res.GetCategories() {
+			found[v.GetId()] = importRef{id: v.GetId(), name: v.GetName()}
+		}
+	}
+	for _, batch := range chunk(names) {
+		res, err := s.categorySvc.Resolve(ctx, &category.ResolveCategoriesRequest{Names: batch})
+		if err != nil {
+			return nil, status.Error(codes.Internal, "failed to load categories")
+		}
+		for _, v := range res.GetCategories() {
+			found[strings.ToLower(v.GetName())] = importRef{id: v.GetId(), name: v.GetName()}
+		}
+	}
+
+	for _, row := range rows {
+		if _, ok := found[row.category]; !ok && isUUID(row.category) {
+			row.fail("category %s not found", row.category)
+		}
+	}
+
+	return found, nil
+}
+
+// checkImportISBNs rejects ISBNs used twice in the file or already in the
+// catalog.
+func (s *BookService) checkImportISBNs(ctx context.Context, rows []*importRow) error {
+	var isbns []string
+	firstRow := map[string]int32{}
+	for _, row := range rows {
+		if row.data == nil || row.data.ISBN == "" {
+			continue
+		}
+
+		if first, ok := firstRow[row.data.ISBN]; ok {
+			row.fail("isbn %s is already used on row %d", row.data.ISBN, first)
+			continue
+		}
+		firstRow[row.data.ISBN] = row.result.Row
+		isbns = append(isbns, row.data.ISBN)
+	}
+
+	existing := map[string]bool{}
+	for _, batch := range chunk(isbns) {
+		res, err := s.repo.ExistingISBNs(ctx, batch)
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		for _, v := range res {
+			existing[v] = true
+		}
+	}
+
+	for _, row := range rows {
+		if row.data != nil && existing[row.data.ISBN] {
+			row.fail("a book with isbn %s already exists", row.data.ISBN)
+		}
+	}
+
+	return nil
+}
+
+// importRefs splits the references used across all rows into ids and names,
+// each listed once.
+func importRefs(rows []*importRow, refs func(*importRow) []string) ([]string, []string) {
+	var ids, names []string
+	seen := map[string]bool{}
+	for _, row := range rows {
+		for _, ref := range refs(row) {
+			key := importKey(ref)
+			if ref == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			if isUUID(ref) {
+				ids = append(ids, ref)
+			} else {
+				names = append(names, ref)
+			}
+		}
+	}
+	return ids, names
+}
+
+// missingImportNames lists the names referenced by valid rows that do not
+// exist yet and will be created when the import is committed.
+func missingImportNames(rows []*importRow, found map[string]importRef, refs func(*importRow) []string) []string {
+	missing := []string{}
+	seen := map[string]bool{}
+	for _, row := range rows {
+		if !row.ok() {
+			continue
+		}
+		for _, ref := range refs(row) {
+			key := importKey(ref)
+			if _, ok := found[key]; ok || seen[key] {
+				continue
+			}
+			seen[key] = true
+			missing = append(missing, ref)
+		}
+	}
+	return missing
+}
+
+// unusedImportNames returns the created names that no row ended up using,
+// because every row referencing them failed to insert.
+func unusedImportNames(created []string, rows []*importRow, refs func(*importRow) []string) []string {
+	used := map[string]bool{}
+	for _, row := range rows {
+		if !row.ok() {
+			continue
+		}
+		for _, ref := range refs(row) {
+			used[importKey(ref)] = true
+		}
+	}
+
+	unused := []string{}
+	for _, name := range created {
+		if !used[importKey(name)] {
+			unused = append(unused, name)
+		}
+	}
+	return unused
+}
+
+func isUUID(v string) bool {
+	_, err := uuid.Parse(v)
+	return err == nil
+}
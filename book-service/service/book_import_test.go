@@ -0,0 +1,120 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/status"
+)
+
+type wantImportRow struct {
+	row      int32
+	errors   []string
+	name     string
+	authors  []string
+	category string
+	isbn     string
+	year     int
+}
+
+func TestParseCSVImport(t *testing.T) {
+	tests := []struct {
+		name string
+		csv  string
+		err  string
+		rows []wantImportRow
+	}{
+		{
+			name: "valid rows",
+			csv: "name,authors,category,isbn,published_year\n" +
+				"The Hobbit,J. R. R. Tolkien; Christopher Tolkien,Fantasy,978-0-261-10334-4,1937\n" +
+				"Pride and Prejudice,Jane Austen,Romance,,\n",
+			rows: []wantImportRow{
+				{row: 2, name: "The Hobbit", authors: []string{"J. R. R. Tolkien", "Christopher Tolkien"}, category: "Fantasy", isbn: "9780261103344", year: 1937},
+				{row: 3, name: "Pride and Prejudice", authors: []string{"Jane Austen"}, category: "Romance"},
+			},
+		},
+		{
+			name: "byte order mark and header case",
+			csv:  "\ufeffName, Authors, Category\nEmma,Jane Austen,Romance\n",
+			rows: []wantImportRow{
+				{row: 2, name: "Emma", authors: []string{"Jane Austen"}, category: "Romance"},
+			},
+		},
+		{
+			name: "line numbers after a multi-line field",
+			csv:  "name,authors,category,description\nEmma,Jane Austen,Romance,\"First line\nsecond line\"\nPersuasion,Jane Austen,Romance,\n",
+			rows: []wantImportRow{
+				{row: 2, name: "Emma", authors: []string{"Jane Austen"}, category: "Romance"},
+				{row: 4, name: "Persuasion", authors: []string{"Jane Austen"}, category: "Romance"},
+			},
+		},
+		{
+			name: "row errors",
+			csv: "name,authors,category,isbn,page_count\n" +
+				",Jane Austen,Romance,,\n" +
+				"Emma,,,,\n" +
+				"Emma,Jane Austen,Romance,12345,\n" +
+				"Emma,Jane Austen,Romance,,many\n" +
+				"Emma,Jane Austen\n",
+			rows: []wantImportRow{
+				{row: 2, errors: []string{"name cannot be empty"}, authors: []string{"Jane Austen"}, category: "Romance"},
+				{row: 3, errors: []string{"at least one author is required", "category cannot be empty"}, name: "Emma"},
+				{row: 4, errors: []string{errInvalidISBN.Error()}, name: "Emma", authors: []string{"Jane Austen"}, category: "Romance"},
+				{row: 5, errors: []string{"page_count must be a whole number"}, name: "Emma", authors: []string{"Jane Austen"}, category: "Romance"},
+				{row: 6, errors: []string{"expected 5 fields, got 2"}},
+			},
+		},
+		{name: "empty", csv: "", err: "import is missing a header row"},
+		{name: "header only", csv: "name,authors,category\n", err: "import has no rows"},
+		{name: "unknown column", csv: "name,authors,category,price\n", err: `unknown column "price"`},
+		{name: "duplicate column", csv: "name,authors,category,Name\n", err: `column "name" is listed more than once`},
+		{name: "missing required column", csv: "name,authors\nEmma,Jane Austen\n", err: "import is missing the category column"},
+		{name: "malformed csv", csv: "name,authors,category\n\"Emma,Jane Austen,Romance\n", err: "invalid csv"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := parseCSVImport([]byte(tt.csv))
+			if tt.err != "" {
+				if err == nil {
+					t.Fatalf("expected an error containing %q", tt.err)
+				}
+				if msg := status.Convert(err).Message(); !strings.Contains(msg, tt.err) {
+					t.Fatalf("error %q does not contain %q", msg, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(rows) != len(tt.rows) {
+				t.Fatalf("got %d rows, want %d", len(rows), len(tt.rows))
+			}
+			for i, want := range tt.rows {
+				if got := importRowSummary(rows[i]); !reflect.DeepEqual(got, want) {
+					t.Errorf("row %d:\ngot  %+v\nwant %+v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func importRowSummary(r *importRow) wantImportRow {
+	res := wantImportRow{
+		row:      r.result.GetRow(),
+		errors:   r.result.GetErrors(),
+		category: r.category,
+	}
+	for _, c := range r.contributors {
+		res.authors = append(res.authors, c.ref)
+	}
+	if r.data != nil {
+		res.name = r.data.Name
+		res.isbn = r.data.ISBN
+		res.year = r.data.PublishedYear
+	}
+	return res
+}
@@ -2,51 +2,98 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 	"github.com/shafaalafghany/book-service/model"
 	"github.com/shafaalafghany/book-service/repository"
+	"gitlab.com/shafaalafghany/synapsis-common/pagination"
 	"gitlab.com/shafaalafghany/synapsis-proto/go/author"
 	"gitlab.com/shafaalafghany/synapsis-proto/go/book"
 	"gitlab.com/shafaalafghany/synapsis-proto/go/category"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/publisher"
 	"gitlab.com/shafaalafghany/synapsis-proto/go/user"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"gorm.io/gorm"
+)
+
+const (
+	roleAdmin    = "admin"
+	maxBatchSize = 100
+
+	defaultRecommendations = 5
+	maxRecommendations     = 50
+
+	defaultTrendingWindow = 7
+	defaultTrending       = 10
+	maxTrending           = 50
+
+	defaultSimilar = 10
+	maxSimilar     = 50
+
+	maxDescription = 10000
 )
 
 type BookServiceInterface interface {
 	CreateBook(context.Context, *book.Book) (*book.CommonBookResponse, error)
 	GetBook(context.Context, *book.Book) (*book.Book, error)
+	GetBookByISBN(context.Context, *book.Book) (*book.Book, error)
 	GetBooks(context.Context, *book.BookRequest) (*book.BooksResponse, error)
+	BatchGetBooks(context.Context, *book.BatchGetBooksRequest) (*book.BatchGetBooksResponse, error)
+	ReferencedIds(context.Context, *book.ReferencedIdsRequest) (*book.ReferencedIdsResponse, error)
 	UpdateBook(context.Context, *book.Book) (*book.CommonBookResponse, error)
 	DeleteBook(context.Context, *book.Book) (*book.CommonBookResponse, error)
+	ListDeletedBooks(context.Context, *book.BookRequest) (*book.BooksResponse, error)
+	RestoreBook(context.Context, *book.Book) (*book.CommonBookResponse, error)
 
+	SearchBooks(context.Context, *book.SearchBooksRequest) (*book.SearchBooksResponse, error)
 	GetRecommendation(context.Context, *book.BookRequest) (*book.BooksResponse, error)
+	GetTrending(context.Context, *book.TrendingRequest) (*book.TrendingResponse, error)
+	GetSimilarBooks(context.Context, *book.SimilarBooksRequest) (*book.SimilarBooksResponse, error)
+
+	ImportBooks(book.BookService_ImportBooksServer) error
+	ExportBooks(*book.ExportBooksRequest, book.BookService_ExportBooksServer) error
 
 	BorrowBook(context.Context, *book.BorrowRecord) (*book.CommonBorrowRecordResponse, error)
 	ReturnBook(context.Context, *book.BorrowRecord) (*book.CommonBorrowRecordResponse, error)
+	GetDigitalAccess(context.Context, *book.DigitalAccessRequest) (*book.DigitalAccess, error)
+	AddLicense(context.Context, *book.DigitalLicense) (*book.CommonBookResponse, error)
+	ListLicenses(context.Context, *book.Book) (*book.LicensesResponse, error)
+	RevokeLicense(context.Context, *book.DigitalLicense) (*book.CommonBookResponse, error)
 }
 
 type BookService struct {
-	repo        repository.BookRepositoryInterface
-	log         *zap.Logger
-	userSvc     user.UserServiceClient
-	authorSvc   author.AuthorServiceClient
-	categorySvc category.CategoryServiceClient
+	repo         repository.BookRepositoryInterface
+	workRepo     repository.WorkRepositoryInterface
+	branchRepo   repository.BranchRepositoryInterface
+	log          *zap.Logger
+	userSvc      user.UserServiceClient
+	authorSvc    author.AuthorServiceClient
+	categorySvc  category.CategoryServiceClient
+	publisherSvc publisher.PublisherServiceClient
+	weights      repository.SimilarityWeights
 }
 
-func NewBookService(repo repository.BookRepositoryInterface, log *zap.Logger, userSvc user.UserServiceClient, authorSvc author.AuthorServiceClient, categorySvc category.CategoryServiceClient) BookServiceInterface {
+func NewBookService(repo repository.BookRepositoryInterface, workRepo repository.WorkRepositoryInterface, branchRepo repository.BranchRepositoryInterface, log *zap.Logger, userSvc user.UserServiceClient, authorSvc author.AuthorServiceClient, categorySvc category.CategoryServiceClient, publisherSvc publisher.PublisherServiceClient, weights repository.SimilarityWeights) BookServiceInterface {
 	return &BookService{
-		repo:        repo,
-		log:         log,
-		userSvc:     userSvc,
-		authorSvc:   authorSvc,
-		categorySvc: categorySvc,
+		repo:         repo,
+		workRepo:     workRepo,
+		branchRepo:   branchRepo,
+		log:          log,
+		userSvc:      userSvc,
+		authorSvc:    authorSvc,
+		categorySvc:  categorySvc,
+		publisherSvc: publisherSvc,
+		weights:      weights,
 	}
 }
 
@@ -62,10 +109,11 @@ func (s *BookService) CreateBook(ctx context.Context, body *book.Book) (*book.Co
 		return nil, status.Error(codes.Internal, "invalid user")
 	}
 
-	authorData, err := s.authorSvc.Get(outbondCtx, &author.Author{Id: body.GetAuthorId()})
+	contributors, err := s.resolveContributors(outbondCtx, body)
 	if err != nil {
-		return nil, status.Error(codes.Internal, "invalid author")
+		return nil, err
 	}
+	primary := primaryAuthor(contributors)
 
 	categoryData, err := s.categorySvc.Get(outbondCtx, &category.Category{Id: body.GetCategoryId()})
 	if err != nil {
@@ -74,21 +122,50 @@ func (s *BookService) CreateBook(ctx context.Context, body *book.Book) (*book.Co
 
 	id := uuid.NewString()
 	data := &model.Book{
-		ID:         id,
-		Name:       body.GetName(),
-		CreatedBy:  userData.GetId(),
-		AuthorID:   authorData.GetId(),
-		CategoryID: categoryData.GetId(),
-		IsBorrowed: false,
-		Borrows:    0,
+		ID:           id,
+		Name:         body.GetName(),
+		CreatedBy:    userData.GetId(),
+		AuthorID:     primary.AuthorID,
+		CategoryID:   categoryData.GetId(),
+		AuthorName:   primary.AuthorName,
+		CategoryName: categoryData.GetName(),
+		IsBorrowed:   false,
+		Borrows:      0,
+		Contributors: contributors,
+	}
+	if err := applyMetadata(data, body); err != nil {
+		return nil, err
 	}
 
-	if err := s.repo.Create(data); err != nil {
+	if err := s.applyPublisher(outbondCtx, data, body); err != nil {
+		return nil, err
+	}
+
+	if err := s.applyGrouping(data, body); err != nil {
+		return nil, err
+	}
+
+	if err := s.applyBranches(data, body, data.ItemType); err != nil {
+		return nil, err
+	}
+	// A new item is shelved at its home branch unless told otherwise.
+	if data.CurrentBranchID == "" {
+		data.CurrentBranchID = data.HomeBranchID
+	}
+
+	if err := s.checkISBN(ctx, data.ISBN, id); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, data); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, status.Error(codes.AlreadyExists, fmt.Sprintf("a book with isbn %s already exists", data.ISBN))
+		}
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	response := fmt.Sprintf("create new book successfully with id %v", id)
-	return &book.CommonBookResponse{Message: response}, nil
+	response := fmt.Sprintf("create new book successfully with id %v", data.ID)
+	return &book.CommonBookResponse{Message: response, Id: data.ID}, nil
 }
 
 func (s *BookService) GetBook(ctx context.Context, body *book.Book) (*book.Book, error) {
@@ -98,24 +175,49 @@ func (s *BookService) GetBook(ctx context.Context, body *book.Book) (*book.Book,
 
 	data, err := s.repo.GetById(ctx, &model.Book{ID: body.Id})
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Error(codes.NotFound, "book not found")
+		}
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	res := &book.Book{
-		Id:         data.ID,
-		Name:       data.Name,
-		AuthorId:   data.AuthorID,
-		CategoryId: data.CategoryID,
-		IsBorrowed: data.IsBorrowed,
-		Borrows:    int32(data.Borrows),
-		CreatedBy:  data.CreatedBy,
-		CreatedAt:  data.CreatedAt.String(),
-		UpdatedAt:  data.UpdatedAt.String(),
+	res := toBookResponse(data)
+	if body.GetView() == book.BookView_FULL {
+		md, ok := metadata.FromIncomingContext(ctx)
+		if !ok {
+			return nil, status.Error(codes.Internal, "missing outgoing metadata")
+		}
+		outbondCtx := metadata.NewOutgoingContext(ctx, md)
+
+		if err := newBookLoader(s.authorSvc, s.categorySvc).load(outbondCtx, []*book.Book{res}); err != nil {
+			return nil, err
+		}
 	}
 
 	return res, nil
 }
 
+func (s *BookService) GetBookByISBN(ctx context.Context, body *book.Book) (*book.Book, error) {
+	if body.GetIsbn() == "" {
+		return nil, status.Error(codes.InvalidArgument, "isbn cannot be empty")
+	}
+
+	isbn, err := normalizeISBN(body.GetIsbn())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	data, err := s.repo.GetByISBN(ctx, isbn)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Error(codes.NotFound, "book not found")
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return s.GetBook(ctx, &book.Book{Id: data.ID, View: body.GetView()})
+}
+
 func (s *BookService) GetBooks(ctx context.Context, body *book.BookRequest) (*book.BooksResponse, error) {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
@@ -128,32 +230,121 @@ func (s *BookService) GetBooks(ctx context.Context, body *book.BookRequest) (*bo
 		return nil, status.Error(codes.Unauthenticated, "invalid user")
 	}
 
-	data, err := s.repo.Get(ctx, body.GetSearch())
+	filter, err := bookFilter(body.GetFilter())
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, err
+	}
+
+	data, err := s.repo.Get(ctx, filter, listOptions(body))
+	if err != nil {
+		return nil, listError(err)
 	}
 
 	books := []*book.Book{}
 
-	if len(data) > 0 {
-		for _, v := range data {
-			temp := &book.Book{
-				Id:         v.ID,
-				Name:       v.Name,
-				AuthorId:   v.AuthorID,
-				CategoryId: v.CategoryID,
-				IsBorrowed: v.IsBorrowed,
-				Borrows:    int32(v.Borrows),
-				CreatedBy:  v.CreatedBy,
-				CreatedAt:  v.CreatedAt.String(),
-				UpdatedAt:  v.UpdatedAt.String(),
-			}
+	if len(data.Books) > 0 {
+		for _, v := range data.Books {
+			books = append(books, toBookResponse(v))
+		}
+	}
 
-			books = append(books, temp)
+	if body.GetView() == book.BookView_FULL {
+		if err := newBookLoader(s.authorSvc, s.categorySvc).load(outbondCtx, books); err != nil {
+			return nil, err
 		}
 	}
 
-	return &book.BooksResponse{Books: books}, nil
+	return &book.BooksResponse{
+		Books:         books,
+		NextPageToken: data.NextPageToken,
+		TotalCount:    data.TotalCount,
+	}, nil
+}
+
+// ReferencedIds tells other services which of their records books still
+// point at, so their purge jobs can keep those. It is called with a service
+// token, so there is no user to look up.
+func (s *BookService) ReferencedIds(ctx context.Context, body *book.ReferencedIdsRequest) (*book.ReferencedIdsResponse, error) {
+	var refs repository.BookReferences
+	var err error
+	if refs.AuthorIDs, _, err = batchIds(body.GetAuthorIds()); err != nil {
+		return nil, err
+	}
+	if refs.CategoryIDs, _, err = batchIds(body.GetCategoryIds()); err != nil {
+		return nil, err
+	}
+	if refs.PublisherIDs, _, err = batchIds(body.GetPublisherIds()); err != nil {
+		return nil, err
+	}
+	if refs.UserIDs, _, err = batchIds(body.GetUserIds()); err != nil {
+		return nil, err
+	}
+
+	data, err := s.repo.ReferencedIds(ctx, refs)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &book.ReferencedIdsResponse{
+		AuthorIds:    data.AuthorIDs,
+		CategoryIds:  data.CategoryIDs,
+		PublisherIds: data.PublisherIDs,
+		UserIds:      data.UserIDs,
+	}, nil
+}
+
+func (s *BookService) BatchGetBooks(ctx context.Context, body *book.BatchGetBooksRequest) (*book.BatchGetBooksResponse, error) {
+	ids, invalid, err := batchIds(body.GetIds())
+	if err != nil {
+		return nil, err
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Internal, "missing outgoing metadata")
+	}
+	outbondCtx := metadata.NewOutgoingContext(ctx, md)
+
+	_, err = s.userSvc.GetUser(outbondCtx, &emptypb.Empty{})
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid user")
+	}
+
+	if len(ids) == 0 {
+		return &book.BatchGetBooksResponse{Books: []*book.Book{}, MissingIds: invalid}, nil
+	}
+
+	data, err := s.repo.GetByIds(ctx, ids)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	found := map[string]*model.Book{}
+	for _, v := range data {
+		found[v.ID] = v
+	}
+
+	books := []*book.Book{}
+	missing := invalid
+	for _, id := range ids {
+		v, ok := found[id]
+		if !ok {
+			missing = append(missing, id)
+			continue
+		}
+		books = append(books, toBookResponse(v))
+	}
+
+	if body.GetView() == book.BookView_FULL {
+		if err := newBookLoader(s.authorSvc, s.categorySvc).load(outbondCtx, books); err != nil {
+			return nil, err
+		}
+	}
+
+	return &book.BatchGetBooksResponse{
+		Books:      books,
+		MissingIds: missing,
+	}, nil
 }
 
 func (s *BookService) UpdateBook(ctx context.Context, body *book.Book) (*book.CommonBookResponse, error) {
@@ -172,17 +363,65 @@ func (s *BookService) UpdateBook(ctx context.Context, body *book.Book) (*book.Co
 		return nil, status.Error(codes.Unauthenticated, "invalid user")
 	}
 
-	_, err = s.repo.GetById(ctx, &model.Book{ID: body.Id})
+	existing, err := s.repo.GetById(ctx, &model.Book{ID: body.Id})
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
+	contributors, err := s.resolveContributors(outbondCtx, body)
+	if err != nil {
+		return nil, err
+	}
+	primary := primaryAuthor(contributors)
+
+	categoryData, err := s.categorySvc.Get(outbondCtx, &category.Category{Id: body.GetCategoryId()})
+	if err != nil {
+		return nil, status.Error(codes.Internal, "invalid category")
+	}
+
 	updateData := &model.Book{
-		Name:       body.GetName(),
-		AuthorID:   body.GetAuthorId(),
-		CategoryID: body.GetCategoryId(),
+		Name:         body.GetName(),
+		AuthorID:     primary.AuthorID,
+		CategoryID:   categoryData.GetId(),
+		AuthorName:   primary.AuthorName,
+		CategoryName: categoryData.GetName(),
+		Contributors: contributors,
+	}
+	if err := applyMetadata(updateData, body); err != nil {
+		return nil, err
+	}
+
+	if err := s.applyPublisher(outbondCtx, updateData, body); err != nil {
+		return nil, err
+	}
+
+	if err := s.applyGrouping(updateData, body); err != nil {
+		return nil, err
+	}
+
+	itemType := updateData.ItemType
+	if itemType == "" {
+		itemType = existing.ItemType
 	}
+	if err := s.applyBranches(updateData, body, itemType); err != nil {
+		return nil, err
+	}
+	if existing.InTransit && body.GetCurrentBranchId() != "" && updateData.CurrentBranchID != existing.CurrentBranchID {
+		return nil, status.Error(codes.FailedPrecondition, "current_branch_id cannot change while the book is in transit")
+	}
+
+	if err := s.checkISBN(ctx, updateData.ISBN, body.GetId()); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkItemType(ctx, existing, updateData.ItemType); err != nil {
+		return nil, err
+	}
+
 	if err := s.repo.Update(ctx, updateData, body.GetId()); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, status.Error(codes.AlreadyExists, fmt.Sprintf("a book with isbn %s already exists", updateData.ISBN))
+		}
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
@@ -217,6 +456,68 @@ func (s *BookService) DeleteBook(ctx context.Context, body *book.Book) (*book.Co
 	return &book.CommonBookResponse{Message: "delete book successfully"}, nil
 }
 
+func (s *BookService) ListDeletedBooks(ctx context.Context, body *book.BookRequest) (*book.BooksResponse, error) {
+	if _, err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	data, err := s.repo.GetDeleted(ctx, listOptions(body))
+	if err != nil {
+		return nil, listError(err)
+	}
+
+	books := []*book.Book{}
+	for _, v := range data.Books {
+		books = append(books, toBookResponse(v))
+	}
+
+	return &book.BooksResponse{
+		Books:         books,
+		NextPageToken: data.NextPageToken,
+		TotalCount:    data.TotalCount,
+	}, nil
+}
+
+func (s *BookService) RestoreBook(ctx context.Context, body *book.Book) (*book.CommonBookResponse, error) {
+	if body.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id cannot be empty")
+	}
+
+	outbondCtx, err := s.requireAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deleted, err := s.repo.GetDeletedById(ctx, body.GetId())
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Error(codes.NotFound, "deleted book not found")
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if _, err := s.authorSvc.Get(outbondCtx, &author.Author{Id: deleted.AuthorID}); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, fmt.Sprintf("author %s no longer exists", deleted.AuthorID))
+	}
+
+	if _, err := s.categorySvc.Get(outbondCtx, &category.Category{Id: deleted.CategoryID}); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, fmt.Sprintf("category %s no longer exists", deleted.CategoryID))
+	}
+
+	if err := s.checkISBN(ctx, deleted.ISBN, deleted.ID); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Restore(ctx, deleted.ID); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, status.Error(codes.AlreadyExists, fmt.Sprintf("a book with isbn %s already exists", deleted.ISBN))
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &book.CommonBookResponse{Message: "restore book successfully"}, nil
+}
+
 func (s *BookService) BorrowBook(ctx context.Context, body *book.BorrowRecord) (*book.CommonBorrowRecordResponse, error) {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
@@ -224,23 +525,41 @@ func (s *BookService) BorrowBook(ctx context.Context, body *book.BorrowRecord) (
 	}
 	outbondCtx := metadata.NewOutgoingContext(ctx, md)
 
-	_, err := s.userSvc.GetUser(outbondCtx, &emptypb.Empty{})
+	userData, err := s.userSvc.GetUser(outbondCtx, &emptypb.Empty{})
 	if err != nil {
 		return nil, status.Error(codes.Internal, "invalid user")
 	}
 
-	if _, err = s.repo.GetById(ctx, &model.Book{ID: body.GetBookId()}); err != nil {
+	bookData, err := s.repo.GetById(ctx, &model.Book{ID: body.GetBookId()})
+	if err != nil {
 		return nil, status.Error(codes.NotFound, "book not found")
 	}
 
+	if bookData.ItemType == model.ItemDigital {
+		return s.borrowDigital(ctx, bookData, userData.GetId())
+	}
+
+	if body.GetBranchId() != "" {
+		if err := s.checkBranch(body.GetBranchId()); err != nil {
+			return nil, err
+		}
+		if bookData.CurrentBranchID != "" && bookData.CurrentBranchID != body.GetBranchId() {
+			return nil, status.Error(codes.FailedPrecondition, fmt.Sprintf("book is at branch %s", bookData.CurrentBranchID))
+		}
+	}
+
 	borrowRecord := &model.BorrowRecord{
-		ID:         uuid.NewString(),
-		BookID:     body.GetBookId(),
-		UserID:     body.GetUserId(),
-		BorrowedAt: time.Now(),
+		ID:             uuid.NewString(),
+		BookID:         body.GetBookId(),
+		UserID:         body.GetUserId(),
+		BorrowedAt:     time.Now(),
+		BorrowBranchID: body.GetBranchId(),
 	}
 
 	if err = s.repo.Borrow(ctx, borrowRecord); err != nil {
+		if errors.Is(err, repository.ErrBookInTransit) || errors.Is(err, repository.ErrBookOnHold) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
 		return nil, err
 	}
 
@@ -254,18 +573,30 @@ func (s *BookService) ReturnBook(ctx context.Context, body *book.BorrowRecord) (
 	}
 	outbondCtx := metadata.NewOutgoingContext(ctx, md)
 
-	_, err := s.userSvc.GetUser(outbondCtx, &emptypb.Empty{})
+	userData, err := s.userSvc.GetUser(outbondCtx, &emptypb.Empty{})
 	if err != nil {
 		return nil, status.Error(codes.Internal, "invalid user")
 	}
 
-	if _, err = s.repo.GetById(ctx, &model.Book{ID: body.GetBookId()}); err != nil {
+	bookData, err := s.repo.GetById(ctx, &model.Book{ID: body.GetBookId()})
+	if err != nil {
 		return nil, status.Error(codes.NotFound, "book not found")
 	}
 
+	if bookData.ItemType == model.ItemDigital {
+		return s.returnDigital(ctx, bookData, userData.GetId())
+	}
+
+	if body.GetBranchId() != "" {
+		if err := s.checkBranch(body.GetBranchId()); err != nil {
+			return nil, err
+		}
+	}
+
 	borrowRecord := &model.BorrowRecord{
-		BookID: body.GetBookId(),
-		UserID: body.GetUserId(),
+		BookID:         body.GetBookId(),
+		UserID:         body.GetUserId(),
+		ReturnBranchID: body.GetBranchId(),
 	}
 
 	if err = s.repo.ReturnBook(ctx, borrowRecord); err != nil {
@@ -275,7 +606,11 @@ func (s *BookService) ReturnBook(ctx context.Context, body *book.BorrowRecord) (
 	return &book.CommonBorrowRecordResponse{Message: "return book successfully"}, nil
 }
 
-func (s *BookService) GetRecommendation(ctx context.Context, body *book.BookRequest) (*book.BooksResponse, error) {
+func (s *BookService) SearchBooks(ctx context.Context, body *book.SearchBooksRequest) (*book.SearchBooksResponse, error) {
+	if strings.TrimSpace(body.GetQuery()) == "" {
+		return nil, status.Error(codes.InvalidArgument, "query cannot be empty")
+	}
+
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
 		return nil, status.Error(codes.Internal, "missing outgoing metadata")
@@ -287,29 +622,537 @@ func (s *BookService) GetRecommendation(ctx context.Context, body *book.BookRequ
 		return nil, status.Error(codes.Unauthenticated, "invalid user")
 	}
 
-	data, err := s.repo.MostBorrows(body.GetSearch())
+	filter, err := bookFilter(body.GetFilter())
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.repo.Search(ctx, filter, pagination.ListOptions{
+		Search:    body.GetQuery(),
+		PageSize:  int(body.GetPageSize()),
+		PageToken: body.GetPageToken(),
+	})
+	if err != nil {
+		return nil, listError(err)
+	}
+
+	hits := []*book.BookSearchHit{}
+	for _, v := range data.Hits {
+		hits = append(hits, &book.BookSearchHit{
+			Book:    toBookResponse(&v.Book),
+			Score:   v.Rank,
+			Snippet: v.Snippet,
+		})
+	}
+
+	return &book.SearchBooksResponse{
+		Hits:          hits,
+		NextPageToken: data.NextPageToken,
+		Fuzzy:         data.Fuzzy,
+	}, nil
+}
+
+func (s *BookService) GetRecommendation(ctx context.Context, body *book.BookRequest) (*book.BooksResponse, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Internal, "missing outgoing metadata")
+	}
+	outbondCtx := metadata.NewOutgoingContext(ctx, md)
+
+	userData, err := s.userSvc.GetUser(outbondCtx, &emptypb.Empty{})
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid user")
+	}
+
+	limit := defaultRecommendations
+	if body.GetPageSize() > 0 {
+		limit = min(int(body.GetPageSize()), maxRecommendations)
+	}
+
+	data, err := s.repo.Recommend(userData.GetId(), body.GetSearch(), limit)
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
+	if len(data) < limit {
+		exclude, err := s.repo.BorrowedBookIds(userData.GetId())
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		for _, v := range data {
+			exclude = append(exclude, v.ID)
+		}
+
+		popular, err := s.repo.MostBorrows(body.GetSearch(), exclude, limit)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+
+		works := map[string]bool{}
+		for _, v := range data {
+			works[v.WorkID] = true
+		}
+		for _, v := range popular {
+			if len(data) == limit {
+				break
+			}
+			if v.WorkID != "" && works[v.WorkID] {
+				continue
+			}
+			works[v.WorkID] = true
+			data = append(data, v)
+		}
+	}
+
 	books := []*book.Book{}
 	if len(data) > 0 {
 		for _, v := range data {
-			temp := &book.Book{
-				Id:         v.ID,
-				Name:       v.Name,
-				AuthorId:   v.Name,
-				CategoryId: v.CategoryID,
-				IsBorrowed: v.IsBorrowed,
-				Borrows:    int32(v.Borrows),
-				CreatedBy:  v.CreatedBy,
-				CreatedAt:  v.CreatedAt.String(),
-				UpdatedAt:  v.UpdatedAt.String(),
+			books = append(books, toBookResponse(v))
+		}
+	}
+
+	return &book.BooksResponse{Books: books}, nil
+}
+
+func (s *BookService) GetTrending(ctx context.Context, body *book.TrendingRequest) (*book.TrendingResponse, error) {
+	window := body.GetWindowDays()
+	if window == 0 {
+		window = defaultTrendingWindow
+	}
+	if !slices.Contains(repository.TrendingWindows, int(window)) {
+		return nil, status.Error(codes.InvalidArgument, "window_days must be 7, 30 or 365")
+	}
+
+	if body.GetCategoryId() != "" && body.GetAuthorId() != "" {
+		return nil, status.Error(codes.InvalidArgument, "scope by either category_id or author_id, not both")
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Internal, "missing outgoing metadata")
+	}
+	outbondCtx := metadata.NewOutgoingContext(ctx, md)
+
+	_, err := s.userSvc.GetUser(outbondCtx, &emptypb.Empty{})
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid user")
+	}
+
+	limit := defaultTrending
+	if body.GetLimit() > 0 {
+		limit = min(int(body.GetLimit()), maxTrending)
+	}
+
+	data, err := s.repo.Trending(ctx, repository.TrendingQuery{
+		WindowDays: int(window),
+		CategoryID: body.GetCategoryId(),
+		AuthorID:   body.GetAuthorId(),
+		Limit:      limit,
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	books := []*book.Book{}
+	trending := []*book.TrendingBook{}
+	for _, v := range data {
+		res := toBookResponse(v.Book)
+		books = append(books, res)
+		trending = append(trending, &book.TrendingBook{Book: res, Borrows: v.Borrows})
+	}
+
+	if body.GetView() == book.BookView_FULL {
+		if err := newBookLoader(s.authorSvc, s.categorySvc).load(outbondCtx, books); err != nil {
+			return nil, err
+		}
+	}
+
+	return &book.TrendingResponse{
+		Books:      trending,
+		WindowDays: window,
+	}, nil
+}
+
+func (s *BookService) GetSimilarBooks(ctx context.Context, body *book.SimilarBooksRequest) (*book.SimilarBooksResponse, error) {
+	if body.GetBookId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "book_id cannot be empty")
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Internal, "missing outgoing metadata")
+	}
+	outbondCtx := metadata.NewOutgoingContext(ctx, md)
+
+	_, err := s.userSvc.GetUser(outbondCtx, &emptypb.Empty{})
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid user")
+	}
+
+	limit := defaultSimilar
+	if body.GetLimit() > 0 {
+		limit = min(int(body.GetLimit()), maxSimilar)
+	}
+
+	source, err := s.repo.GetById(ctx, &model.Book{ID: body.GetBookId()})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Error(codes.NotFound, "book not found")
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	data, cached, err := s.repo.GetCachedSimilarBooks(ctx, source.ID, limit)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if !cached {
+		ancestors := []string{}
+		ancestorData, err := s.categorySvc.GetAncestors(outbondCtx, &category.Category{Id: source.CategoryID})
+		if err != nil {
+			s.log.Warn("failed to load category ancestors", zap.String("category_id", source.CategoryID), zap.Error(err))
+		}
+		for _, v := range ancestorData.GetCategories() {
+			ancestors = append(ancestors, v.GetId())
+		}
+
+		data, err = s.repo.ScoreSimilarBooks(ctx, source, ancestors, s.weights, limit)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	books := []*book.Book{}
+	similar := []*book.SimilarBook{}
+	for _, v := range data {
+		res := toBookResponse(v.Book)
+		books = append(books, res)
+		similar = append(similar, &book.SimilarBook{Book: res, Score: v.Score})
+	}
+
+	if body.GetView() == book.BookView_FULL {
+		if err := newBookLoader(s.authorSvc, s.categorySvc).load(outbondCtx, books); err != nil {
+			return nil, err
+		}
+	}
+
+	return &book.SimilarBooksResponse{Books: similar}, nil
+}
+
+func (s *BookService) requireAdmin(ctx context.Context) (context.Context, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Internal, "missing outgoing metadata")
+	}
+	outbondCtx := metadata.NewOutgoingContext(ctx, md)
+
+	userData, err := s.userSvc.GetUser(outbondCtx, &emptypb.Empty{})
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid user")
+	}
+
+	if userData.GetRole() != roleAdmin {
+		return nil, status.Error(codes.PermissionDenied, "admin role required")
+	}
+
+	return outbondCtx, nil
+}
+
+func listOptions(body *book.BookRequest) pagination.ListOptions {
+	return pagination.ListOptions{
+		Search:       body.GetSearch(),
+		PageSize:     int(body.GetPageSize()),
+		PageToken:    body.GetPageToken(),
+		SortBy:       body.GetSortBy(),
+		SortOrder:    body.GetSortOrder(),
+		IncludeTotal: body.GetIncludeTotal(),
+	}
+}
+
+func bookFilter(body *book.BookFilter) (repository.BookFilter, error) {
+	filter := repository.BookFilter{
+		AuthorIDs:   body.GetAuthorIds(),
+		CategoryIDs: body.GetCategoryIds(),
+		CreatedBy:   body.GetCreatedBy(),
+
+		ContributorIDs:  body.GetContributorIds(),
+		ContributorRole: strings.ToLower(strings.TrimSpace(body.GetContributorRole())),
+
+		BranchIDs: body.GetBranchIds(),
+	}
+
+	if filter.ContributorRole != "" && !validContributorRole(filter.ContributorRole) {
+		return filter, status.Error(codes.InvalidArgument, "contributor_role must be author, editor, translator or illustrator")
+	}
+
+	if body != nil && body.IsBorrowed != nil {
+		isBorrowed := body.GetIsBorrowed()
+		filter.IsBorrowed = &isBorrowed
+	}
+	if body != nil && body.Available != nil {
+		available := body.GetAvailable()
+		filter.Available = &available
+	}
+
+	if body.GetCreatedAfter() != "" {
+		after, err := time.Parse(time.RFC3339, body.GetCreatedAfter())
+		if err != nil {
+			return filter, status.Error(codes.InvalidArgument, "created_after must be an RFC 3339 timestamp")
+		}
+		filter.CreatedAfter = &after
+	}
+
+	if body.GetCreatedBefore() != "" {
+		before, err := time.Parse(time.RFC3339, body.GetCreatedBefore())
+		if err != nil {
+			return filter, status.Error(codes.InvalidArgument, "created_before must be an RFC 3339 timestamp")
+		}
+		filter.CreatedBefore = &before
+	}
+
+	if filter.CreatedAfter != nil && filter.CreatedBefore != nil && !filter.CreatedAfter.Before(*filter.CreatedBefore) {
+		return filter, status.Error(codes.InvalidArgument, "created_after must be before created_before")
+	}
+
+	return filter, nil
+}
+
+func listError(err error) error {
+	if errors.Is(err, pagination.ErrInvalidPageToken) || errors.Is(err, pagination.ErrInvalidSort) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return status.Error(codes.Internal, err.Error())
+}
+
+// batchIds dedupes the requested ids and splits off the ones that are not
+// UUIDs, which cannot match a row and would fail the query on the uuid column.
+func batchIds(ids []string) ([]string, []string, error) {
+	seen := map[string]bool{}
+	result := []string{}
+	invalid := []string{}
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		if !isUUID(id) {
+			invalid = append(invalid, id)
+			continue
+		}
+		result = append(result, id)
+	}
+
+	if len(result)+len(invalid) > maxBatchSize {
+		return nil, nil, status.Error(codes.InvalidArgument, fmt.Sprintf("at most %d ids can be requested at once", maxBatchSize))
+	}
+
+	return result, invalid, nil
+}
+
+func (s *BookService) checkISBN(ctx context.Context, isbn, id string) error {
+	if isbn == "" {
+		return nil
+	}
+
+	existing, err := s.repo.GetByISBN(ctx, isbn)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	if existing.ID != id {
+		return status.Error(codes.AlreadyExists, fmt.Sprintf("isbn %s is already used by book %s", isbn, existing.ID))
+	}
+
+	return nil
+}
+
+func (s *BookService) applyPublisher(ctx context.Context, data *model.Book, body *book.Book) error {
+	if body.GetPublisherId() == "" {
+		return nil
+	}
+
+	publisherData, err := s.publisherSvc.Get(ctx, &publisher.Publisher{Id: body.GetPublisherId()})
+	if err != nil {
+		return status.Error(codes.Internal, "invalid publisher")
+	}
+
+	data.PublisherID = publisherData.GetId()
+	data.Publisher = publisherData.GetName()
+
+	return nil
+}
+
+// applyBranches sets the home and current branches that are given. Digital
+// books have no location.
+func (s *BookService) applyBranches(data *model.Book, body *book.Book, itemType string) error {
+	home := strings.TrimSpace(body.GetHomeBranchId())
+	current := strings.TrimSpace(body.GetCurrentBranchId())
+	if home == "" && current == "" {
+		return nil
+	}
+
+	if itemType == model.ItemDigital {
+		return status.Error(codes.InvalidArgument, "digital books cannot be assigned to a branch")
+	}
+
+	for _, id := range []string{home, current} {
+		if id == "" {
+			continue
+		}
+		if err := s.checkBranch(id); err != nil {
+			return err
+		}
+	}
+
+	data.HomeBranchID = home
+	data.CurrentBranchID = current
+
+	return nil
+}
+
+func (s *BookService) checkBranch(id string) error {
+	if _, err := s.branchRepo.GetById(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return status.Error(codes.InvalidArgument, fmt.Sprintf("branch %s not found", id))
+		}
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	return nil
+}
+
+func (s *BookService) applyGrouping(data *model.Book, body *book.Book) error {
+	if body.GetWorkId() != "" {
+		if _, err := s.workRepo.GetWorkById(body.GetWorkId()); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return status.Error(codes.InvalidArgument, fmt.Sprintf("work %s not found", body.GetWorkId()))
 			}
+			return status.Error(codes.Internal, err.Error())
+		}
+	}
 
-			books = append(books, temp)
+	if body.GetSeriesId() == "" && body.GetSeriesVolume() != 0 {
+		return status.Error(codes.InvalidArgument, "series_volume requires series_id")
+	}
+	if body.GetSeriesVolume() < 0 {
+		return status.Error(codes.InvalidArgument, "series_volume cannot be negative")
+	}
+	if body.GetSeriesId() != "" {
+		if _, err := s.workRepo.GetSeriesById(body.GetSeriesId()); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return status.Error(codes.InvalidArgument, fmt.Sprintf("series %s not found", body.GetSeriesId()))
+			}
+			return status.Error(codes.Internal, err.Error())
 		}
 	}
 
-	return &book.BooksResponse{Books: books}, nil
+	data.WorkID = body.GetWorkId()
+	data.SeriesID = body.GetSeriesId()
+	data.SeriesVolume = int(body.GetSeriesVolume())
+
+	return nil
+}
+
+func applyMetadata(data *model.Book, body *book.Book) error {
+	if body.GetIsbn() != "" {
+		isbn, err := normalizeISBN(body.GetIsbn())
+		if err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		data.ISBN = isbn
+	}
+
+	if body.GetPublishedYear() < 0 || int(body.GetPublishedYear()) > time.Now().Year()+1 {
+		return status.Error(codes.InvalidArgument, "published_year is out of range")
+	}
+
+	if body.GetPageCount() < 0 {
+		return status.Error(codes.InvalidArgument, "page_count cannot be negative")
+	}
+
+	language := strings.ToLower(strings.TrimSpace(body.GetLanguage()))
+	if language != "" && !validLanguage(language) {
+		return status.Error(codes.InvalidArgument, "language must be an ISO 639 code such as en or ind")
+	}
+
+	description := strings.TrimSpace(body.GetDescription())
+	if utf8.RuneCountInString(description) > maxDescription {
+		return status.Error(codes.InvalidArgument, fmt.Sprintf("description cannot be longer than %d characters", maxDescription))
+	}
+
+	itemType := strings.ToLower(strings.TrimSpace(body.GetItemType()))
+	if itemType != "" && itemType != model.ItemPhysical && itemType != model.ItemDigital {
+		return status.Error(codes.InvalidArgument, "item_type must be physical or digital")
+	}
+
+	data.ItemType = itemType
+	data.Publisher = strings.TrimSpace(body.GetPublisher())
+	data.PublishedYear = int(body.GetPublishedYear())
+	data.Language = language
+	data.PageCount = int(body.GetPageCount())
+	data.Edition = strings.TrimSpace(body.GetEdition())
+	data.Description = description
+
+	return nil
+}
+
+func validLanguage(code string) bool {
+	if len(code) < 2 || len(code) > 3 {
+		return false
+	}
+
+	for _, c := range code {
+		if c < 'a' || c > 'z' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func toBookResponse(data *model.Book) *book.Book {
+	res := &book.Book{
+		Id:              data.ID,
+		Name:            data.Name,
+		AuthorId:        data.AuthorID,
+		CategoryId:      data.CategoryID,
+		IsBorrowed:      data.IsBorrowed,
+		Borrows:         int32(data.Borrows),
+		CreatedBy:       data.CreatedBy,
+		CreatedAt:       data.CreatedAt.String(),
+		UpdatedAt:       data.UpdatedAt.String(),
+		AuthorName:      data.AuthorName,
+		CategoryName:    data.CategoryName,
+		AverageRating:   data.AverageRating,
+		RatingCount:     int32(data.RatingCount),
+		Isbn:            data.ISBN,
+		Publisher:       data.Publisher,
+		PublisherId:     data.PublisherID,
+		HasCover:        data.CoverType != "",
+		ItemType:        data.ItemType,
+		HomeBranchId:    data.HomeBranchID,
+		CurrentBranchId: data.CurrentBranchID,
+		InTransit:       data.InTransit,
+		PublishedYear:   int32(data.PublishedYear),
+		Language:        data.Language,
+		PageCount:       int32(data.PageCount),
+		Edition:         data.Edition,
+		Description:     data.Description,
+		Contributors:    toContributorsResponse(data.Contributors),
+		WorkId:          data.WorkID,
+		SeriesId:        data.SeriesID,
+		SeriesVolume:    int32(data.SeriesVolume),
+	}
+
+	if data.DeletedAt != nil {
+		res.DeletedAt = data.DeletedAt.String()
+	}
+
+	return res
 }
@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/shafaalafghany/book-service/model"
+	"github.com/shafaalafghany/book-service/repository"
+	"gitlab.com/shafaalafghany/synapsis-common/pagination"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/book"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/user"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"gorm.io/gorm"
+)
+
+const (
+	maxBranchName = 255
+	maxBranchCode = 32
+)
+
+type BranchServiceInterface interface {
+	CreateBranch(context.Context, *book.Branch) (*book.CommonBranchResponse, error)
+	UpdateBranch(context.Context, *book.Branch) (*book.CommonBranchResponse, error)
+	GetBranch(context.Context, *book.Branch) (*book.Branch, error)
+	ListBranches(context.Context, *book.BranchRequest) (*book.BranchesResponse, error)
+	DeleteBranch(context.Context, *book.Branch) (*book.CommonBranchResponse, error)
+}
+
+type BranchService struct {
+	repo    repository.BranchRepositoryInterface
+	log     *zap.Logger
+	userSvc user.UserServiceClient
+}
+
+func NewBranchService(repo repository.BranchRepositoryInterface, log *zap.Logger, userSvc user.UserServiceClient) BranchServiceInterface {
+	return &BranchService{
+		repo:    repo,
+		log:     log,
+		userSvc: userSvc,
+	}
+}
+
+func (s *BranchService) CreateBranch(ctx context.Context, body *book.Branch) (*book.CommonBranchResponse, error) {
+	data, err := validateBranch(body)
+	if err != nil {
+		return nil, err
+	}
+
+	userData, err := s.authenticate(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkCode(data.Code, ""); err != nil {
+		return nil, err
+	}
+
+	data.CreatedBy = userData.GetId()
+	if err := s.repo.Create(data); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, status.Error(codes.AlreadyExists, fmt.Sprintf("branch code %s is already used", data.Code))
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &book.CommonBranchResponse{
+		Message: fmt.Sprintf("create new branch successfully with id %s", data.ID),
+		Id:      data.ID,
+	}, nil
+}
+
+func (s *BranchService) UpdateBranch(ctx context.Context, body *book.Branch) (*book.CommonBranchResponse, error) {
+	if body.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id cannot be empty")
+	}
+
+	data, err := validateBranch(body)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.authenticate(ctx, true); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.branch(body.GetId()); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkCode(data.Code, body.GetId()); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(data, body.GetId()); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, status.Error(codes.AlreadyExists, fmt.Sprintf("branch code %s is already used", data.Code))
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &book.CommonBranchResponse{Message: "update branch successfully", Id: body.GetId()}, nil
+}
+
+func (s *BranchService) GetBranch(ctx context.Context, body *book.Branch) (*book.Branch, error) {
+	if body.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id cannot be empty")
+	}
+
+	if _, err := s.authenticate(ctx, false); err != nil {
+		return nil, err
+	}
+
+	data, err := s.branch(body.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	return toBranchResponse(data), nil
+}
+
+func (s *BranchService) ListBranches(ctx context.Context, body *book.BranchRequest) (*book.BranchesResponse, error) {
+	if _, err := s.authenticate(ctx, false); err != nil {
+		return nil, err
+	}
+
+	page, err := s.repo.Get(pagination.ListOptions{
+		Search:       body.GetSearch(),
+		PageSize:     int(body.GetPageSize()),
+		PageToken:    body.GetPageToken(),
+		SortBy:       body.GetSortBy(),
+		SortOrder:    body.GetSortOrder(),
+		IncludeTotal: body.GetIncludeTotal(),
+	})
+	if err != nil {
+		return nil, listError(err)
+	}
+
+	res := &book.BranchesResponse{NextPageToken: page.NextPageToken, TotalCount: page.TotalCount}
+	for _, v := range page.Branches {
+		res.Branches = append(res.Branches, toBranchResponse(v))
+	}
+
+	return res, nil
+}
+
+func (s *BranchService) DeleteBranch(ctx context.Context, body *book.Branch) (*book.CommonBranchResponse, error) {
+	if body.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id cannot be empty")
+	}
+
+	if _, err := s.authenticate(ctx, true); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.branch(body.GetId()); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Delete(body.GetId()); err != nil {
+		if errors.Is(err, repository.ErrBranchInUse) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &book.CommonBranchResponse{Message: "delete branch successfully", Id: body.GetId()}, nil
+}
+
+// authenticate loads the caller; branches are managed by admins only.
+func (s *BranchService) authenticate(ctx context.Context, admin bool) (*user.User, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Internal, "missing outgoing metadata")
+	}
+	outbondCtx := metadata.NewOutgoingContext(ctx, md)
+
+	userData, err := s.userSvc.GetUser(outbondCtx, &emptypb.Empty{})
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid user")
+	}
+
+	if admin && userData.GetRole() != roleAdmin {
+		return nil, status.Error(codes.PermissionDenied, "admin role required")
+	}
+
+	return userData, nil
+}
+
+func (s *BranchService) branch(id string) (*model.Branch, error) {
+	data, err := s.repo.GetById(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Error(codes.NotFound, "branch not found")
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return data, nil
+}
+
+func (s *BranchService) checkCode(code, id string) error {
+	existing, err := s.repo.GetByCode(code)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	if existing.ID != id {
+		return status.Error(codes.AlreadyExists, fmt.Sprintf("branch code %s is already used by branch %s", code, existing.ID))
+	}
+
+	return nil
+}
+
+func validateBranch(body *book.Branch) (*model.Branch, error) {
+	name := strings.TrimSpace(body.GetName())
+	if name == "" {
+		return nil, status.Error(codes.InvalidArgument, "name cannot be empty")
+	}
+	if utf8.RuneCountInString(name) > maxBranchName {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("name cannot be longer than %d characters", maxBranchName))
+	}
+
+	code := strings.ToUpper(strings.TrimSpace(body.GetCode()))
+	if code == "" {
+		return nil, status.Error(codes.InvalidArgument, "code cannot be empty")
+	}
+	if len(code) > maxBranchCode || strings.ContainsAny(code, " \t\n") {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("code must be a single word of at most %d characters", maxBranchCode))
+	}
+
+	return &model.Branch{
+		Name:    name,
+		Code:    code,
+		Address: strings.TrimSpace(body.GetAddress()),
+		Phone:   strings.TrimSpace(body.GetPhone()),
+	}, nil
+}
+
+func toBranchResponse(data *model.Branch) *book.Branch {
+	return &book.Branch{
+		Id:        data.ID,
+		Name:      data.Name,
+		Code:      data.Code,
+		Address:   data.Address,
+		Phone:     data.Phone,
+		BookCount: data.BookCount,
+		CreatedBy: data.CreatedBy,
+		CreatedAt: data.CreatedAt.String(),
+		UpdatedAt: data.UpdatedAt.String(),
+	}
+}
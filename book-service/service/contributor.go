@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/shafaalafghany/book-service/model"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/author"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/book"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const maxContributors = 50
+
+func validContributorRole(role string) bool {
+	switch role {
+	case model.ContributorAuthor, model.ContributorEditor, model.ContributorTranslator, model.ContributorIllustrator:
+		return true
+	}
+	return false
+}
+
+// resolveContributors validates the requested contributors against
+// author-service. Requests that only set the deprecated author_id are treated
+// as a single author.
+func (s *BookService) resolveContributors(ctx context.Context, body *book.Book) ([]*model.BookContributor, error) {
+	requested := body.GetContributors()
+	if len(requested) == 0 && body.GetAuthorId() != "" {
+		requested = []*book.BookContributor{{AuthorId: body.GetAuthorId(), Role: model.ContributorAuthor}}
+	}
+
+	if len(requested) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "at least one contributor is required")
+	}
+	if len(requested) > maxContributors {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("a book cannot have more than %d contributors", maxContributors))
+	}
+
+	contributors := make([]*model.BookContributor, 0, len(requested))
+	seen := map[string]bool{}
+	var ids []string
+	hasAuthor := false
+	for i, v := range requested {
+		id := strings.TrimSpace(v.GetAuthorId())
+		if id == "" {
+			return nil, status.Error(codes.InvalidArgument, "contributor author_id cannot be empty")
+		}
+
+		role := strings.ToLower(strings.TrimSpace(v.GetRole()))
+		if role == "" {
+			role = model.ContributorAuthor
+		}
+		if !validContributorRole(role) {
+			return nil, status.Error(codes.InvalidArgument, "contributor role must be author, editor, translator or illustrator")
+		}
+
+		key := id + "/" + role
+		if seen[key] {
+			return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("author %s is listed more than once as %s", id, role))
+		}
+		seen[key] = true
+		if role == model.ContributorAuthor {
+			hasAuthor = true
+		}
+
+		ids = append(ids, id)
+		contributors = append(contributors, &model.BookContributor{
+			AuthorID: id,
+			Role:     role,
+			Position: i + 1,
+		})
+	}
+
+	if !hasAuthor {
+		return nil, status.Error(codes.InvalidArgument, "at least one contributor must have the author role")
+	}
+
+	res, err := s.authorSvc.BatchGet(ctx, &author.BatchGetAuthorsRequest{Ids: ids})
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to load authors")
+	}
+	if len(res.GetMissingIds()) > 0 {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("authors not found: %s", strings.Join(res.GetMissingIds(), ", ")))
+	}
+
+	names := map[string]string{}
+	for _, v := range res.GetAuthors() {
+		names[v.GetId()] = v.GetName()
+	}
+	for _, v := range contributors {
+		v.AuthorName = names[v.AuthorID]
+	}
+
+	return contributors, nil
+}
+
+// primaryAuthor returns the first contributor with the author role, which is
+// kept on the book's deprecated author columns.
+func primaryAuthor(contributors []*model.BookContributor) *model.BookContributor {
+	for _, v := range contributors {
+		if v.Role == model.ContributorAuthor {
+			return v
+		}
+	}
+	return nil
+}
+
+func toContributorsResponse(contributors []*model.BookContributor) []*book.BookContributor {
+	res := make([]*book.BookContributor, 0, len(contributors))
+	for _, v := range contributors {
+		res = append(res, &book.BookContributor{
+			AuthorId:   v.AuthorID,
+			Role:       v.Role,
+			Position:   int32(v.Position),
+			AuthorName: v.AuthorName,
+		})
+	}
+	return res
+}
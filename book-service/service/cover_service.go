@@ -0,0 +1,312 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+
+	"github.com/shafaalafghany/book-service/model"
+	"github.com/shafaalafghany/book-service/repository"
+	"github.com/shafaalafghany/book-service/storage"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/book"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/user"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultMaxCoverBytes = 5 << 20
+
+	maxCoverPixels   = 40_000_000
+	coverChunkSize   = 32 << 10
+	thumbnailType    = "image/jpeg"
+	thumbnailQuality = 85
+)
+
+var coverTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+}
+
+var thumbnailWidths = map[book.CoverSize]int{
+	book.CoverSize_COVER_SMALL:  120,
+	book.CoverSize_COVER_MEDIUM: 300,
+	book.CoverSize_COVER_LARGE:  600,
+}
+
+type CoverServiceInterface interface {
+	UploadCover(book.BookService_UploadCoverServer) error
+	DownloadCover(*book.CoverRequest, book.BookService_DownloadCoverServer) error
+	OpenCover(context.Context, string, book.CoverSize) (io.ReadCloser, string, error)
+}
+
+type CoverService struct {
+	bookRepo repository.BookRepositoryInterface
+	store    storage.BlobStore
+	log      *zap.Logger
+	userSvc  user.UserServiceClient
+	maxBytes int
+}
+
+func NewCoverService(bookRepo repository.BookRepositoryInterface, store storage.BlobStore, log *zap.Logger, userSvc user.UserServiceClient, maxBytes int) CoverServiceInterface {
+	return &CoverService{
+		bookRepo: bookRepo,
+		store:    store,
+		log:      log,
+		userSvc:  userSvc,
+		maxBytes: maxBytes,
+	}
+}
+
+func (s *CoverService) UploadCover(stream book.BookService_UploadCoverServer) error {
+	ctx := stream.Context()
+
+	first, err := stream.Recv()
+	if err == io.EOF {
+		return status.Error(codes.InvalidArgument, "no cover data received")
+	}
+	if err != nil {
+		return err
+	}
+
+	bookId := first.GetBookId()
+	if bookId == "" {
+		return status.Error(codes.InvalidArgument, "book_id cannot be empty")
+	}
+
+	if err := s.authenticate(ctx); err != nil {
+		return err
+	}
+
+	if _, err := s.bookRepo.GetById(ctx, &model.Book{ID: bookId}); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return status.Error(codes.NotFound, "book not found")
+		}
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	var data bytes.Buffer
+	chunk := first
+	for {
+		if data.Len()+len(chunk.GetData()) > s.maxBytes {
+			return status.Error(codes.ResourceExhausted, fmt.Sprintf("cover cannot be larger than %d bytes", s.maxBytes))
+		}
+		data.Write(chunk.GetData())
+
+		chunk, err = stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if data.Len() == 0 {
+		return status.Error(codes.InvalidArgument, "no cover data received")
+	}
+
+	contentType := http.DetectContentType(data.Bytes())
+	if !coverTypes[contentType] {
+		return status.Error(codes.InvalidArgument, "cover must be a JPEG, PNG or GIF image")
+	}
+	if declared := first.GetContentType(); declared != "" && declared != contentType {
+		return status.Error(codes.InvalidArgument, fmt.Sprintf("content_type %s does not match the uploaded %s data", declared, contentType))
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data.Bytes()))
+	if err != nil {
+		return status.Error(codes.InvalidArgument, "cover image cannot be decoded")
+	}
+	if config.Width*config.Height > maxCoverPixels {
+		return status.Error(codes.InvalidArgument, "cover image dimensions are too large")
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data.Bytes()))
+	if err != nil {
+		return status.Error(codes.InvalidArgument, "cover image cannot be decoded")
+	}
+
+	sizes := []string{book.CoverSize_COVER_ORIGINAL.String()}
+	if err := s.store.Put(ctx, coverKey(bookId, book.CoverSize_COVER_ORIGINAL), bytes.NewReader(data.Bytes())); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	flat := flatten(img)
+	for _, size := range []book.CoverSize{book.CoverSize_COVER_SMALL, book.CoverSize_COVER_MEDIUM, book.CoverSize_COVER_LARGE} {
+		var thumb bytes.Buffer
+		if err := jpeg.Encode(&thumb, resize(flat, thumbnailWidths[size]), &jpeg.Options{Quality: thumbnailQuality}); err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		if err := s.store.Put(ctx, coverKey(bookId, size), &thumb); err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		sizes = append(sizes, size.String())
+	}
+
+	if err := s.bookRepo.SetCover(ctx, bookId, contentType); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	return stream.SendAndClose(&book.CoverResponse{
+		Message:     "upload cover successfully",
+		BookId:      bookId,
+		ContentType: contentType,
+		Size:        int64(data.Len()),
+		Sizes:       sizes,
+	})
+}
+
+func (s *CoverService) DownloadCover(body *book.CoverRequest, stream book.BookService_DownloadCoverServer) error {
+	ctx := stream.Context()
+
+	if body.GetBookId() == "" {
+		return status.Error(codes.InvalidArgument, "book_id cannot be empty")
+	}
+
+	if err := s.authenticate(ctx); err != nil {
+		return err
+	}
+
+	blob, contentType, err := s.OpenCover(ctx, body.GetBookId(), body.GetSize())
+	if err != nil {
+		return err
+	}
+	defer blob.Close()
+
+	buf := make([]byte, coverChunkSize)
+	first := true
+	for {
+		n, err := blob.Read(buf)
+		if n > 0 {
+			chunk := &book.CoverChunk{Data: buf[:n]}
+			if first {
+				chunk.BookId = body.GetBookId()
+				chunk.ContentType = contentType
+				first = false
+			}
+			if err := stream.Send(chunk); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+	}
+}
+
+// OpenCover returns a stored cover and its content type. Callers must close
+// the reader.
+func (s *CoverService) OpenCover(ctx context.Context, bookId string, size book.CoverSize) (io.ReadCloser, string, error) {
+	if _, ok := thumbnailWidths[size]; !ok && size != book.CoverSize_COVER_ORIGINAL {
+		return nil, "", status.Error(codes.InvalidArgument, "unknown cover size")
+	}
+
+	data, err := s.bookRepo.GetById(ctx, &model.Book{ID: bookId})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", status.Error(codes.NotFound, "book not found")
+		}
+		return nil, "", status.Error(codes.Internal, err.Error())
+	}
+	if data.CoverType == "" {
+		return nil, "", status.Error(codes.NotFound, "book has no cover")
+	}
+
+	contentType := data.CoverType
+	if size != book.CoverSize_COVER_ORIGINAL {
+		contentType = thumbnailType
+	}
+
+	blob, err := s.store.Get(ctx, coverKey(data.ID, size))
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, "", status.Error(codes.NotFound, "book has no cover")
+		}
+		return nil, "", status.Error(codes.Internal, err.Error())
+	}
+
+	return blob, contentType, nil
+}
+
+func (s *CoverService) authenticate(ctx context.Context) error {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return status.Error(codes.Internal, "missing outgoing metadata")
+	}
+	outbondCtx := metadata.NewOutgoingContext(ctx, md)
+
+	if _, err := s.userSvc.GetUser(outbondCtx, &emptypb.Empty{}); err != nil {
+		return status.Error(codes.Unauthenticated, "invalid user")
+	}
+
+	return nil
+}
+
+func coverKey(bookId string, size book.CoverSize) string {
+	if size == book.CoverSize_COVER_ORIGINAL {
+		return fmt.Sprintf("covers/%s/original", bookId)
+	}
+	return fmt.Sprintf("covers/%s/%d.jpg", bookId, thumbnailWidths[size])
+}
+
+// flatten draws the image over a white background so transparent PNG and GIF
+// covers still look right once encoded as JPEG thumbnails.
+func flatten(src image.Image) *image.RGBA {
+	b := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), src, b.Min, draw.Over)
+	return dst
+}
+
+// resize scales the image down to the given width with a box filter, keeping
+// the aspect ratio. Images narrower than the width are left at their size.
+func resize(src *image.RGBA, width int) *image.RGBA {
+	sw, sh := src.Bounds().Dx(), src.Bounds().Dy()
+	if width > sw {
+		width = sw
+	}
+	height := max(1, sh*width/sw)
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := y * sh / height
+		y1 := max(y0+1, (y+1)*sh/height)
+		for x := 0; x < width; x++ {
+			x0 := x * sw / width
+			x1 := max(x0+1, (x+1)*sw/width)
+
+			var r, g, b, n int
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					c := src.RGBAAt(sx, sy)
+					r += int(c.R)
+					g += int(c.G)
+					b += int(c.B)
+					n++
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{R: uint8(r / n), G: uint8(g / n), B: uint8(b / n), A: 0xff})
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,292 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shafaalafghany/book-service/model"
+	"github.com/shafaalafghany/book-service/repository"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/book"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultLoanDays = 14
+	maxLoanDays     = 365
+)
+
+// borrowDigital lends a digital book to the signed-in user on one of its
+// licenses and issues an access token for the loan. The token is returned
+// only here; just its hash is stored.
+func (s *BookService) borrowDigital(ctx context.Context, data *model.Book, userId string) (*book.CommonBorrowRecordResponse, error) {
+	token, tokenHash, err := newAccessToken()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	borrowRecord := &model.BorrowRecord{
+		BookID:     data.ID,
+		UserID:     userId,
+		BorrowedAt: time.Now(),
+		TokenHash:  tokenHash,
+	}
+
+	if err := s.repo.BorrowDigital(ctx, borrowRecord); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrAlreadyOnLoan):
+			return nil, status.Error(codes.AlreadyExists, err.Error())
+		case errors.Is(err, repository.ErrNoLicenseAvailable):
+			return nil, status.Error(codes.ResourceExhausted, err.Error())
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, status.Error(codes.NotFound, "book not found")
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &book.CommonBorrowRecordResponse{
+		Message:     "borrow book successfully",
+		Id:          borrowRecord.ID,
+		AccessToken: token,
+		ExpiresAt:   borrowRecord.ExpiresAt.UTC().Format(time.RFC3339),
+	}, nil
+}
+
+func (s *BookService) returnDigital(ctx context.Context, data *model.Book, userId string) (*book.CommonBorrowRecordResponse, error) {
+	if err := s.repo.ReturnDigital(ctx, &model.BorrowRecord{BookID: data.ID, UserID: userId}); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Error(codes.NotFound, "book is not on loan to this user")
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &book.CommonBorrowRecordResponse{Message: "return book successfully"}, nil
+}
+
+// GetDigitalAccess checks an access token issued by BorrowBook. Content
+// servers call it before serving a digital book; it fails once the loan has
+// been returned or has ended.
+func (s *BookService) GetDigitalAccess(ctx context.Context, body *book.DigitalAccessRequest) (*book.DigitalAccess, error) {
+	if body.GetAccessToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "access_token cannot be empty")
+	}
+
+	data, err := s.repo.GetLoanByToken(ctx, hashAccessToken(body.GetAccessToken()))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Error(codes.PermissionDenied, "access token is invalid or has expired")
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &book.DigitalAccess{
+		BorrowId:   data.ID,
+		BookId:     data.BookID,
+		UserId:     data.UserID,
+		LicenseId:  data.LicenseID,
+		BorrowedAt: data.BorrowedAt.UTC().Format(time.RFC3339),
+		ExpiresAt:  data.ExpiresAt.UTC().Format(time.RFC3339),
+	}, nil
+}
+
+func (s *BookService) AddLicense(ctx context.Context, body *book.DigitalLicense) (*book.CommonBookResponse, error) {
+	if body.GetBookId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "book_id cannot be empty")
+	}
+	if body.GetConcurrency() < 1 {
+		return nil, status.Error(codes.InvalidArgument, "concurrency must be at least 1")
+	}
+	if body.GetCheckoutLimit() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "checkout_limit cannot be negative")
+	}
+
+	loanDays := int(body.GetLoanDays())
+	if loanDays == 0 {
+		loanDays = defaultLoanDays
+	}
+	if loanDays < 1 || loanDays > maxLoanDays {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("loan_days must be between 1 and %d", maxLoanDays))
+	}
+
+	var expiresAt *time.Time
+	if body.GetExpiresAt() != "" {
+		t, err := time.Parse(time.RFC3339, body.GetExpiresAt())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "expires_at must be an RFC 3339 timestamp")
+		}
+		if !t.After(time.Now()) {
+			return nil, status.Error(codes.InvalidArgument, "expires_at must be in the future")
+		}
+		expiresAt = &t
+	}
+
+	userId, err := s.requireModerator(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.repo.GetById(ctx, &model.Book{ID: body.GetBookId()})
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "book not found")
+	}
+	if data.ItemType != model.ItemDigital {
+		return nil, status.Error(codes.FailedPrecondition, "licenses can only be added to digital books")
+	}
+
+	license := &model.DigitalLicense{
+		BookID:        data.ID,
+		Concurrency:   int(body.GetConcurrency()),
+		CheckoutLimit: int(body.GetCheckoutLimit()),
+		LoanDays:      loanDays,
+		ExpiresAt:     expiresAt,
+		Reference:     strings.TrimSpace(body.GetReference()),
+		CreatedBy:     userId,
+	}
+	if err := s.repo.CreateLicense(ctx, license); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &book.CommonBookResponse{
+		Message: fmt.Sprintf("add license successfully with id %s", license.ID),
+		Id:      license.ID,
+	}, nil
+}
+
+func (s *BookService) ListLicenses(ctx context.Context, body *book.Book) (*book.LicensesResponse, error) {
+	if body.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id cannot be empty")
+	}
+
+	if _, err := s.requireModerator(ctx); err != nil {
+		return nil, err
+	}
+
+	licenses, err := s.repo.GetLicenses(ctx, body.GetId())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	res := &book.LicensesResponse{}
+	for _, v := range licenses {
+		res.Licenses = append(res.Licenses, toLicenseResponse(v))
+	}
+
+	return res, nil
+}
+
+// RevokeLicense stops new loans on a license and ends the ones already
+// issued on it.
+func (s *BookService) RevokeLicense(ctx context.Context, body *book.DigitalLicense) (*book.CommonBookResponse, error) {
+	if body.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id cannot be empty")
+	}
+
+	if _, err := s.requireModerator(ctx); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.GetLicenseById(ctx, body.GetId()); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Error(codes.NotFound, "license not found")
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if err := s.repo.RevokeLicense(ctx, body.GetId()); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &book.CommonBookResponse{Message: "revoke license successfully", Id: body.GetId()}, nil
+}
+
+// checkItemType refuses to switch a book between physical and digital while
+// it is on loan, since the two are lent through different records.
+func (s *BookService) checkItemType(ctx context.Context, data *model.Book, itemType string) error {
+	if itemType == "" || itemType == data.ItemType {
+		return nil
+	}
+
+	if data.IsBorrowed {
+		return status.Error(codes.FailedPrecondition, "item_type cannot change while the book is borrowed")
+	}
+
+	count, err := s.repo.ActiveLoans(ctx, data.ID)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	if count > 0 {
+		return status.Error(codes.FailedPrecondition, "item_type cannot change while the book has active loans")
+	}
+
+	return nil
+}
+
+func (s *BookService) requireModerator(ctx context.Context) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", status.Error(codes.Internal, "missing outgoing metadata")
+	}
+	outbondCtx := metadata.NewOutgoingContext(ctx, md)
+
+	userData, err := s.userSvc.GetUser(outbondCtx, &emptypb.Empty{})
+	if err != nil {
+		return "", status.Error(codes.Unauthenticated, "invalid user")
+	}
+
+	if !isModerator(userData.GetRole()) {
+		return "", status.Error(codes.PermissionDenied, "only librarians and admins can manage licenses")
+	}
+
+	return userData.GetId(), nil
+}
+
+func newAccessToken() (string, string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", "", err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(raw)
+	return token, hashAccessToken(token), nil
+}
+
+func hashAccessToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func toLicenseResponse(data *model.DigitalLicense) *book.DigitalLicense {
+	res := &book.DigitalLicense{
+		Id:                 data.ID,
+		BookId:             data.BookID,
+		Concurrency:        int32(data.Concurrency),
+		CheckoutLimit:      int32(data.CheckoutLimit),
+		CheckoutsUsed:      int32(data.CheckoutsUsed),
+		RemainingCheckouts: -1,
+		ActiveLoans:        int32(data.ActiveLoans),
+		LoanDays:           int32(data.LoanDays),
+		Reference:          data.Reference,
+		CreatedBy:          data.CreatedBy,
+		CreatedAt:          data.CreatedAt.String(),
+		UpdatedAt:          data.UpdatedAt.String(),
+	}
+
+	if data.CheckoutLimit > 0 {
+		res.RemainingCheckouts = int32(max(data.CheckoutLimit-data.CheckoutsUsed, 0))
+	}
+	if data.ExpiresAt != nil {
+		res.ExpiresAt = data.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+
+	return res
+}
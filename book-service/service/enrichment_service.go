@@ -0,0 +1,522 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/shafaalafghany/book-service/enrichment"
+	"github.com/shafaalafghany/book-service/model"
+	"github.com/shafaalafghany/book-service/repository"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/author"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/book"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/category"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/user"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"gorm.io/gorm"
+)
+
+const (
+	maxCandidates       = 5
+	maxEnrichedSubjects = 20
+)
+
+type EnrichmentServiceInterface interface {
+	LookupISBN(context.Context, *book.LookupISBNRequest) (*book.ISBNLookupResponse, error)
+	CreateDraft(context.Context, *book.CreateDraftRequest) (*book.BookDraft, error)
+	ListDrafts(context.Context, *book.BookRequest) (*book.BookDraftsResponse, error)
+	PublishDraft(context.Context, *book.PublishDraftRequest) (*book.CommonBookResponse, error)
+	DeleteDraft(context.Context, *book.BookDraft) (*book.CommonBookResponse, error)
+}
+
+type EnrichmentService struct {
+	repo        repository.BookDraftRepositoryInterface
+	bookRepo    repository.BookRepositoryInterface
+	books       BookServiceInterface
+	provider    enrichment.Provider
+	log         *zap.Logger
+	userSvc     user.UserServiceClient
+	authorSvc   author.AuthorServiceClient
+	categorySvc category.CategoryServiceClient
+}
+
+func NewEnrichmentService(repo repository.BookDraftRepositoryInterface, bookRepo repository.BookRepositoryInterface, books BookServiceInterface, provider enrichment.Provider, log *zap.Logger, userSvc user.UserServiceClient, authorSvc author.AuthorServiceClient, categorySvc category.CategoryServiceClient) EnrichmentServiceInterface {
+	return &EnrichmentService{
+		repo:        repo,
+		bookRepo:    bookRepo,
+		books:       books,
+		provider:    provider,
+		log:         log,
+		userSvc:     userSvc,
+		authorSvc:   authorSvc,
+		categorySvc: categorySvc,
+	}
+}
+
+// LookupISBN fetches metadata from the configured provider and proposes
+// existing authors and categories for every name it returned. Nothing is
+// written.
+func (s *EnrichmentService) LookupISBN(ctx context.Context, body *book.LookupISBNRequest) (*book.ISBNLookupResponse, error) {
+	isbn, err := normalizeISBN(body.GetIsbn())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	outbondCtx, _, err := s.requireModerator(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.lookup(ctx, isbn)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &book.ISBNLookupResponse{
+		Isbn:          data.ISBN,
+		Title:         data.Title,
+		Publisher:     data.Publisher,
+		PublishedYear: int32(data.PublishedYear),
+		PageCount:     int32(data.PageCount),
+		Language:      data.Language,
+		Description:   data.Description,
+		CoverUrl:      data.CoverURL,
+		Provider:      s.provider.Name(),
+	}
+
+	if existing, err := s.bookRepo.GetByISBN(ctx, isbn); err == nil {
+		res.ExistingBookId = existing.ID
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if draft, err := s.repo.GetByISBN(isbn); err == nil {
+		res.ExistingDraftId = draft.ID
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	for _, name := range limit(data.Authors, maxContributors) {
+		candidates, err := s.authorCandidates(outbondCtx, name)
+		if err != nil {
+			return nil, err
+		}
+		res.Authors = append(res.Authors, &book.EnrichmentMatch{Name: name, Candidates: candidates})
+	}
+
+	for _, name := range limit(data.Subjects, maxEnrichedSubjects) {
+		candidates, err := s.categoryCandidates(outbondCtx, name)
+		if err != nil {
+			return nil, err
+		}
+		res.Subjects = append(res.Subjects, &book.EnrichmentMatch{Name: name, Candidates: candidates})
+	}
+
+	return res, nil
+}
+
+// CreateDraft looks up an ISBN and stores the result as a draft. Authors and
+// the category are matched by exact name; with create_missing, unknown
+// authors and the first subject are created in author- and category-service.
+func (s *EnrichmentService) CreateDraft(ctx context.Context, body *book.CreateDraftRequest) (*book.BookDraft, error) {
+	isbn, err := normalizeISBN(body.GetIsbn())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	outbondCtx, userData, err := s.requireModerator(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing, err := s.bookRepo.GetByISBN(ctx, isbn); err == nil {
+		return nil, status.Error(codes.AlreadyExists, fmt.Sprintf("isbn %s is already used by book %s", isbn, existing.ID))
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if draft, err := s.repo.GetByISBN(isbn); err == nil {
+		return nil, status.Error(codes.AlreadyExists, fmt.Sprintf("isbn %s already has draft %s", isbn, draft.ID))
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	data, err := s.lookup(ctx, isbn)
+	if err != nil {
+		return nil, err
+	}
+	if data.Title == "" {
+		return nil, status.Error(codes.FailedPrecondition, fmt.Sprintf("%s has no title for isbn %s", s.provider.Name(), isbn))
+	}
+
+	draft := &model.BookDraft{
+		ISBN:          isbn,
+		Name:          data.Title,
+		Publisher:     data.Publisher,
+		PublishedYear: data.PublishedYear,
+		PageCount:     data.PageCount,
+		Description:   truncate(data.Description, maxDescription),
+		CoverURL:      data.CoverURL,
+		Provider:      s.provider.Name(),
+		CreatedBy:     userData.GetId(),
+	}
+	if language := strings.ToLower(data.Language); validLanguage(language) {
+		draft.Language = language
+	}
+
+	if draft.AuthorIDs, draft.UnmatchedAuthors, err = s.resolveAuthors(outbondCtx, limit(data.Authors, maxContributors), body.GetCreateMissing()); err != nil {
+		return nil, err
+	}
+	if draft.CategoryID, draft.UnmatchedSubjects, err = s.resolveCategory(outbondCtx, limit(data.Subjects, maxEnrichedSubjects), body.GetCreateMissing()); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(draft); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, status.Error(codes.AlreadyExists, fmt.Sprintf("isbn %s already has a draft", isbn))
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return toDraftResponse(draft), nil
+}
+
+func (s *EnrichmentService) ListDrafts(ctx context.Context, body *book.BookRequest) (*book.BookDraftsResponse, error) {
+	if _, _, err := s.requireModerator(ctx); err != nil {
+		return nil, err
+	}
+
+	page, err := s.repo.Get(listOptions(body))
+	if err != nil {
+		return nil, listError(err)
+	}
+
+	res := &book.BookDraftsResponse{NextPageToken: page.NextPageToken}
+	for _, v := range page.Drafts {
+		res.Drafts = append(res.Drafts, toDraftResponse(v))
+	}
+
+	return res, nil
+}
+
+// PublishDraft creates a book from a draft through the regular CreateBook
+// validation and removes the draft. Fields set on the request's book replace
+// the draft's values, which is how a librarian fills in a category or authors
+// the lookup could not match.
+func (s *EnrichmentService) PublishDraft(ctx context.Context, body *book.PublishDraftRequest) (*book.CommonBookResponse, error) {
+	if body.GetDraftId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "draft_id cannot be empty")
+	}
+
+	if _, _, err := s.requireModerator(ctx); err != nil {
+		return nil, err
+	}
+
+	draft, err := s.draft(body.GetDraftId())
+	if err != nil {
+		return nil, err
+	}
+
+	data := draftBook(draft, body.GetBook())
+	if data.GetCategoryId() == "" {
+		return nil, status.Error(codes.FailedPrecondition, "draft has no category, set book.category_id to publish it")
+	}
+	if len(data.GetContributors()) == 0 && data.GetAuthorId() == "" {
+		return nil, status.Error(codes.FailedPrecondition, "draft has no authors, set book.contributors to publish it")
+	}
+
+	res, err := s.books.CreateBook(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Delete(draft.ID); err != nil {
+		s.log.Error("failed to delete published draft", zap.String("draft_id", draft.ID), zap.Error(err))
+	}
+
+	return &book.CommonBookResponse{
+		Message: fmt.Sprintf("publish draft successfully as book %s", res.GetId()),
+		Id:      res.GetId(),
+	}, nil
+}
+
+func (s *EnrichmentService) DeleteDraft(ctx context.Context, body *book.BookDraft) (*book.CommonBookResponse, error) {
+	if body.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id cannot be empty")
+	}
+
+	if _, _, err := s.requireModerator(ctx); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.draft(body.GetId()); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Delete(body.GetId()); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &book.CommonBookResponse{Message: "delete draft successfully", Id: body.GetId()}, nil
+}
+
+func (s *EnrichmentService) requireModerator(ctx context.Context) (context.Context, *user.User, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, nil, status.Error(codes.Internal, "missing outgoing metadata")
+	}
+	outbondCtx := metadata.NewOutgoingContext(ctx, md)
+
+	userData, err := s.userSvc.GetUser(outbondCtx, &emptypb.Empty{})
+	if err != nil {
+		return nil, nil, status.Error(codes.Unauthenticated, "invalid user")
+	}
+
+	if !isModerator(userData.GetRole()) {
+		return nil, nil, status.Error(codes.PermissionDenied, "only librarians and admins can catalog from an isbn")
+	}
+
+	return outbondCtx, userData, nil
+}
+
+func (s *EnrichmentService) lookup(ctx context.Context, isbn string) (*enrichment.Metadata, error) {
+	data, err := s.provider.Lookup(ctx, isbn)
+	if err != nil {
+		if errors.Is(err, enrichment.ErrNotFound) {
+			return nil, status.Error(codes.NotFound, fmt.Sprintf("%s has no record for isbn %s", s.provider.Name(), isbn))
+		}
+		s.log.Error("isbn lookup failed", zap.String("provider", s.provider.Name()), zap.String("isbn", isbn), zap.Error(err))
+		return nil, status.Error(codes.Unavailable, "isbn lookup failed")
+	}
+
+	return data, nil
+}
+
+func (s *EnrichmentService) draft(id string) (*model.BookDraft, error) {
+	data, err := s.repo.GetById(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Error(codes.NotFound, "draft not found")
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return data, nil
+}
+
+// authorCandidates searches author-service for a name. Providers often spell
+// names differently ("J.R.R. Tolkien" and "J. R. R. Tolkien"), so when the
+// full name finds nothing the last word is tried as a surname.
+func (s *EnrichmentService) authorCandidates(ctx context.Context, name string) ([]*book.EnrichmentCandidate, error) {
+	var candidates []*book.EnrichmentCandidate
+	for _, query := range searchQueries(name) {
+		res, err := s.authorSvc.GetList(ctx, &author.AuthorRequest{Search: query, PageSize: maxCandidates})
+		if err != nil {
+			return nil, status.Error(codes.Internal, "failed to search authors")
+		}
+		for _, v := range res.GetAuthors() {
+			candidates = append(candidates, &book.EnrichmentCandidate{Id: v.GetId(), Name: v.GetName(), Exact: strings.EqualFold(v.GetName(), name)})
+		}
+		if len(candidates) > 0 {
+			break
+		}
+	}
+
+	return rankCandidates(candidates), nil
+}
+
+func (s *EnrichmentService) categoryCandidates(ctx context.Context, name string) ([]*book.EnrichmentCandidate, error) {
+	res, err := s.categorySvc.GetList(ctx, &category.CategoryRequest{Search: name, PageSize: maxCandidates})
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to search categories")
+	}
+
+	var candidates []*book.EnrichmentCandidate
+	for _, v := range res.GetCategories() {
+		candidates = append(candidates, &book.EnrichmentCandidate{Id: v.GetId(), Name: v.GetName(), Exact: strings.EqualFold(v.GetName(), name)})
+	}
+
+	return rankCandidates(candidates), nil
+}
+
+func (s *EnrichmentService) resolveAuthors(ctx context.Context, names []string, createMissing bool) ([]string, []string, error) {
+	if len(names) == 0 {
+		return []string{}, []string{}, nil
+	}
+
+	res, err := s.authorSvc.Resolve(ctx, &author.ResolveAuthorsRequest{Names: names, CreateMissing: createMissing})
+	if err != nil {
+		return nil, nil, status.Error(codes.Internal, "failed to resolve authors")
+	}
+
+	found := map[string]string{}
+	for _, v := range res.GetAuthors() {
+		found[strings.ToLower(v.GetName())] = v.GetId()
+	}
+
+	ids, unmatched := []string{}, []string{}
+	for _, name := range names {
+		if id, ok := found[strings.ToLower(name)]; ok {
+			ids = append(ids, id)
+		} else {
+			unmatched = append(unmatched, name)
+		}
+	}
+
+	return ids, unmatched, nil
+}
+
+// resolveCategory picks the first subject, in provider order, that names an
+// existing category. The remaining subjects are kept on the draft for the
+// librarian to review.
+func (s *EnrichmentService) resolveCategory(ctx context.Context, subjects []string, createMissing bool) (string, []string, error) {
+	if len(subjects) == 0 {
+		return "", []string{}, nil
+	}
+
+	res, err := s.categorySvc.Resolve(ctx, &category.ResolveCategoriesRequest{Names: subjects})
+	if err != nil {
+		return "", nil, status.Error(codes.Internal, "failed to resolve categories")
+	}
+
+	found := map[string]string{}
+	for _, v := range res.GetCategories() {
+		found[strings.ToLower(v.GetName())] = v.GetId()
+	}
+
+	for i, subject := range subjects {
+		if id, ok := found[strings.ToLower(subject)]; ok {
+			return id, without(subjects, i), nil
+		}
+	}
+
+	if !createMissing {
+		return "", subjects, nil
+	}
+
+	created, err := s.categorySvc.Resolve(ctx, &category.ResolveCategoriesRequest{Names: subjects[:1], CreateMissing: true})
+	if err != nil || len(created.GetCategories()) == 0 {
+		return "", nil, status.Error(codes.Internal, "failed to create category")
+	}
+
+	return created.GetCategories()[0].GetId(), without(subjects, 0), nil
+}
+
+// draftBook turns a draft into a CreateBook request, letting any field set
+// on overrides win.
+func draftBook(draft *model.BookDraft, overrides *book.Book) *book.Book {
+	data := &book.Book{
+		Name:          draft.Name,
+		CategoryId:    draft.CategoryID,
+		Isbn:          draft.ISBN,
+		Publisher:     draft.Publisher,
+		PublishedYear: int32(draft.PublishedYear),
+		PageCount:     int32(draft.PageCount),
+		Language:      draft.Language,
+		Description:   draft.Description,
+	}
+	for i, id := range draft.AuthorIDs {
+		data.Contributors = append(data.Contributors, &book.BookContributor{AuthorId: id, Role: model.ContributorAuthor, Position: int32(i + 1)})
+	}
+
+	if overrides == nil {
+		return data
+	}
+
+	if overrides.GetName() != "" {
+		data.Name = overrides.GetName()
+	}
+	if overrides.GetCategoryId() != "" {
+		data.CategoryId = overrides.GetCategoryId()
+	}
+	if len(overrides.GetContributors()) > 0 || overrides.GetAuthorId() != "" {
+		data.Contributors = overrides.GetContributors()
+		data.AuthorId = overrides.GetAuthorId()
+	}
+	if overrides.GetPublisher() != "" {
+		data.Publisher = overrides.GetPublisher()
+	}
+	if overrides.GetPublishedYear() != 0 {
+		data.PublishedYear = overrides.GetPublishedYear()
+	}
+	if overrides.GetPageCount() != 0 {
+		data.PageCount = overrides.GetPageCount()
+	}
+	if overrides.GetLanguage() != "" {
+		data.Language = overrides.GetLanguage()
+	}
+	if overrides.GetDescription() != "" {
+		data.Description = overrides.GetDescription()
+	}
+	data.PublisherId = overrides.GetPublisherId()
+	data.Edition = overrides.GetEdition()
+	data.WorkId = overrides.GetWorkId()
+	data.SeriesId = overrides.GetSeriesId()
+	data.SeriesVolume = overrides.GetSeriesVolume()
+
+	return data
+}
+
+func toDraftResponse(data *model.BookDraft) *book.BookDraft {
+	return &book.BookDraft{
+		Id:                data.ID,
+		Isbn:              data.ISBN,
+		Name:              data.Name,
+		AuthorIds:         data.AuthorIDs,
+		CategoryId:        data.CategoryID,
+		Publisher:         data.Publisher,
+		PublishedYear:     int32(data.PublishedYear),
+		PageCount:         int32(data.PageCount),
+		Language:          data.Language,
+		Description:       data.Description,
+		CoverUrl:          data.CoverURL,
+		UnmatchedAuthors:  data.UnmatchedAuthors,
+		UnmatchedSubjects: data.UnmatchedSubjects,
+		Provider:          data.Provider,
+		CreatedBy:         data.CreatedBy,
+		CreatedAt:         data.CreatedAt.String(),
+		UpdatedAt:         data.UpdatedAt.String(),
+	}
+}
+
+func searchQueries(name string) []string {
+	queries := []string{name}
+	if words := strings.Fields(name); len(words) > 1 {
+		queries = append(queries, words[len(words)-1])
+	}
+	return queries
+}
+
+func rankCandidates(candidates []*book.EnrichmentCandidate) []*book.EnrichmentCandidate {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].GetExact() && !candidates[j].GetExact()
+	})
+	return candidates
+}
+
+func limit(values []string, n int) []string {
+	if len(values) > n {
+		return values[:n]
+	}
+	return values
+}
+
+func without(values []string, i int) []string {
+	result := make([]string, 0, len(values)-1)
+	result = append(result, values[:i]...)
+	return append(result, values[i+1:]...)
+}
+
+func truncate(value string, n int) string {
+	runes := []rune(value)
+	if len(runes) > n {
+		return string(runes[:n])
+	}
+	return value
+}
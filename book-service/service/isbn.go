@@ -0,0 +1,90 @@
+package service
+
+import (
+	"errors"
+	"strings"
+)
+
+var errInvalidISBN = errors.New("isbn must be a valid ISBN-10 or ISBN-13")
+
+func normalizeISBN(raw string) (string, error) {
+	var digits strings.Builder
+	for _, c := range strings.ToUpper(raw) {
+		switch {
+		case c >= '0' && c <= '9', c == 'X':
+			digits.WriteRune(c)
+		case c == '-' || c == ' ':
+		default:
+			return "", errInvalidISBN
+		}
+	}
+
+	isbn := digits.String()
+	switch len(isbn) {
+	case 10:
+		if !validISBN10(isbn) {
+			return "", errInvalidISBN
+		}
+		return isbn10To13(isbn), nil
+	case 13:
+		if !validISBN13(isbn) {
+			return "", errInvalidISBN
+		}
+		return isbn, nil
+	default:
+		return "", errInvalidISBN
+	}
+}
+
+func validISBN10(isbn string) bool {
+	sum := 0
+	for i, c := range isbn {
+		var v int
+		switch {
+		case c == 'X' && i == 9:
+			v = 10
+		case c >= '0' && c <= '9':
+			v = int(c - '0')
+		default:
+			return false
+		}
+		sum += v * (10 - i)
+	}
+
+	return sum%11 == 0
+}
+
+func validISBN13(isbn string) bool {
+	if !strings.HasPrefix(isbn, "978") && !strings.HasPrefix(isbn, "979") {
+		return false
+	}
+
+	sum := 0
+	for i, c := range isbn {
+		if c < '0' || c > '9' {
+			return false
+		}
+		if i%2 == 0 {
+			sum += int(c - '0')
+		} else {
+			sum += 3 * int(c-'0')
+		}
+	}
+
+	return sum%10 == 0
+}
+
+func isbn10To13(isbn string) string {
+	body := "978" + isbn[:9]
+
+	sum := 0
+	for i, c := range body {
+		if i%2 == 0 {
+			sum += int(c - '0')
+		} else {
+			sum += 3 * int(c-'0')
+		}
+	}
+
+	return body + string(rune('0'+(10-sum%10)%10))
+}
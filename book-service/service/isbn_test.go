@@ -0,0 +1,44 @@
+package service
+
+import "testing"
+
+func TestNormalizeISBN(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string
+		err  bool
+	}{
+		{name: "isbn-13", raw: "9780261103344", want: "9780261103344"},
+		{name: "isbn-13 with hyphens", raw: "978-0-261-10334-4", want: "9780261103344"},
+		{name: "isbn-13 with spaces", raw: "978 0 14 143951 8", want: "9780141439518"},
+		{name: "isbn-10", raw: "0261103342", want: "9780261103344"},
+		{name: "isbn-10 with check digit x", raw: "0-8044-2957-X", want: "9780804429573"},
+		{name: "isbn-10 with lower-case x", raw: "080442957x", want: "9780804429573"},
+		{name: "isbn-13 bad check digit", raw: "9780261103345", err: true},
+		{name: "isbn-10 bad check digit", raw: "0261103343", err: true},
+		{name: "isbn-13 without bookland prefix", raw: "1234567890128", err: true},
+		{name: "x outside the check digit", raw: "02611033X2", err: true},
+		{name: "letters", raw: "97802611033a4", err: true},
+		{name: "too short", raw: "978026110", err: true},
+		{name: "empty", raw: "", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeISBN(tt.raw)
+			if tt.err {
+				if err == nil {
+					t.Fatalf("normalizeISBN(%q) = %q, want an error", tt.raw, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("normalizeISBN(%q) failed: %v", tt.raw, err)
+			}
+			if got != tt.want {
+				t.Errorf("normalizeISBN(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+
+	"gitlab.com/shafaalafghany/synapsis-proto/go/author"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/book"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/category"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type bookLoader struct {
+	authorSvc   author.AuthorServiceClient
+	categorySvc category.CategoryServiceClient
+	authors     map[string]*author.Author
+	categories  map[string]*category.Category
+}
+
+func newBookLoader(authorSvc author.AuthorServiceClient, categorySvc category.CategoryServiceClient) *bookLoader {
+	return &bookLoader{
+		authorSvc:   authorSvc,
+		categorySvc: categorySvc,
+		authors:     map[string]*author.Author{},
+		categories:  map[string]*category.Category{},
+	}
+}
+
+func (l *bookLoader) load(ctx context.Context, books []*book.Book) error {
+	var authorIds, categoryIds []string
+	pendingAuthors := map[string]bool{}
+	pendingCategories := map[string]bool{}
+	for _, b := range books {
+		ids := []string{b.GetAuthorId()}
+		for _, c := range b.GetContributors() {
+			ids = append(ids, c.GetAuthorId())
+		}
+		for _, id := range ids {
+			if id != "" && !pendingAuthors[id] {
+				if _, ok := l.authors[id]; !ok {
+					pendingAuthors[id] = true
+					authorIds = append(authorIds, id)
+				}
+			}
+		}
+		if id := b.GetCategoryId(); id != "" && !pendingCategories[id] {
+			if _, ok := l.categories[id]; !ok {
+				pendingCategories[id] = true
+				categoryIds = append(categoryIds, id)
+			}
+		}
+	}
+
+	for _, ids := range chunk(authorIds) {
+		res, err := l.authorSvc.BatchGet(ctx, &author.BatchGetAuthorsRequest{Ids: ids})
+		if err != nil {
+			return status.Error(codes.Internal, "failed to load authors")
+		}
+		for _, v := range res.GetAuthors() {
+			l.authors[v.GetId()] = v
+		}
+		for _, id := range res.GetMissingIds() {
+			l.authors[id] = nil
+		}
+	}
+
+	for _, ids := range chunk(categoryIds) {
+		res, err := l.categorySvc.BatchGet(ctx, &category.BatchGetCategoriesRequest{Ids: ids})
+		if err != nil {
+			return status.Error(codes.Internal, "failed to load categories")
+		}
+		for _, v := range res.GetCategories() {
+			l.categories[v.GetId()] = v
+		}
+		for _, id := range res.GetMissingIds() {
+			l.categories[id] = nil
+		}
+	}
+
+	for _, b := range books {
+		b.Author = l.authors[b.GetAuthorId()]
+		for _, c := range b.GetContributors() {
+			c.Author = l.authors[c.GetAuthorId()]
+		}
+		b.Category = l.categories[b.GetCategoryId()]
+	}
+
+	return nil
+}
+
+func chunk(ids []string) [][]string {
+	var result [][]string
+	for len(ids) > maxBatchSize {
+		result = append(result, ids[:maxBatchSize])
+		ids = ids[maxBatchSize:]
+	}
+	if len(ids) > 0 {
+		result = append(result, ids)
+	}
+	return result
+}
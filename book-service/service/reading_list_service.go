@@ -0,0 +1,460 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shafaalafghany/book-service/model"
+	"github.com/shafaalafghany/book-service/repository"
+	"gitlab.com/shafaalafghany/synapsis-common/pagination"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/book"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/user"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"gorm.io/gorm"
+)
+
+const maxListName = 100
+
+type ReadingListServiceInterface interface {
+	CreateList(context.Context, *book.ReadingList) (*book.CommonListResponse, error)
+	UpdateList(context.Context, *book.ReadingList) (*book.CommonListResponse, error)
+	DeleteList(context.Context, *book.ReadingList) (*book.CommonListResponse, error)
+	GetList(context.Context, *book.ReadingList) (*book.ReadingList, error)
+	ListMyLists(context.Context, *book.ReadingListRequest) (*book.ReadingListsResponse, error)
+	AddToList(context.Context, *book.ReadingListItem) (*book.CommonListResponse, error)
+	RemoveFromList(context.Context, *book.ReadingListItem) (*book.CommonListResponse, error)
+	ReorderList(context.Context, *book.ReorderListRequest) (*book.CommonListResponse, error)
+	ListNotifications(context.Context, *book.NotificationRequest) (*book.NotificationsResponse, error)
+}
+
+type ReadingListService struct {
+	repo     repository.ReadingListRepositoryInterface
+	bookRepo repository.BookRepositoryInterface
+	log      *zap.Logger
+	userSvc  user.UserServiceClient
+}
+
+func NewReadingListService(repo repository.ReadingListRepositoryInterface, bookRepo repository.BookRepositoryInterface, log *zap.Logger, userSvc user.UserServiceClient) ReadingListServiceInterface {
+	return &ReadingListService{
+		repo:     repo,
+		bookRepo: bookRepo,
+		log:      log,
+		userSvc:  userSvc,
+	}
+}
+
+func (s *ReadingListService) CreateList(ctx context.Context, body *book.ReadingList) (*book.CommonListResponse, error) {
+	name, err := validateListName(body.GetName())
+	if err != nil {
+		return nil, err
+	}
+
+	kind := body.GetKind()
+	if kind == "" {
+		kind = model.ListReading
+	}
+	if kind != model.ListReading && kind != model.ListWishlist {
+		return nil, status.Error(codes.InvalidArgument, "kind must be reading or wishlist")
+	}
+
+	userData, err := s.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &model.ReadingList{
+		UserID: userData.GetId(),
+		Name:   name,
+		Kind:   kind,
+	}
+	visibility := body.GetVisibility()
+	if visibility == "" {
+		visibility = model.ListPrivate
+	}
+	if err := applyVisibility(data, visibility); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(data); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &book.CommonListResponse{
+		Message: fmt.Sprintf("create list %s successfully with id %s", name, data.ID),
+		Id:      data.ID,
+	}, nil
+}
+
+func (s *ReadingListService) UpdateList(ctx context.Context, body *book.ReadingList) (*book.CommonListResponse, error) {
+	name, err := validateListName(body.GetName())
+	if err != nil {
+		return nil, err
+	}
+
+	userData, err := s.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.ownList(body.GetId(), userData.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	existing.Name = name
+	if err := applyVisibility(existing, body.GetVisibility()); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(existing, existing.ID); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &book.CommonListResponse{Message: "update list successfully", Id: existing.ID}, nil
+}
+
+func (s *ReadingListService) DeleteList(ctx context.Context, body *book.ReadingList) (*book.CommonListResponse, error) {
+	userData, err := s.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.ownList(body.GetId(), userData.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Delete(existing.ID); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &book.CommonListResponse{Message: "delete list successfully", Id: existing.ID}, nil
+}
+
+func (s *ReadingListService) GetList(ctx context.Context, body *book.ReadingList) (*book.ReadingList, error) {
+	if body.GetId() == "" && body.GetShareToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id or share_token is required")
+	}
+
+	userData, err := s.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var data *model.ReadingList
+	if body.GetShareToken() != "" {
+		data, err = s.repo.GetByShareToken(body.GetShareToken())
+	} else {
+		data, err = s.repo.GetById(body.GetId())
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Error(codes.NotFound, "list not found")
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if body.GetShareToken() == "" && data.UserID != userData.GetId() && data.Visibility != model.ListPublic {
+		return nil, status.Error(codes.NotFound, "list not found")
+	}
+
+	items, err := s.repo.GetItems(data.ID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	ids := make([]string, 0, len(items))
+	for _, v := range items {
+		ids = append(ids, v.BookID)
+	}
+
+	books := []*model.Book{}
+	if len(ids) > 0 {
+		if books, err = s.bookRepo.GetByIds(ctx, ids); err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	found := map[string]*model.Book{}
+	for _, v := range books {
+		found[v.ID] = v
+	}
+
+	res := toListResponse(data, data.UserID == userData.GetId())
+	for _, v := range items {
+		item := &book.ReadingListItem{
+			Id:                  v.ID,
+			ListId:              v.ListID,
+			BookId:              v.BookID,
+			Position:            int32(v.Position),
+			NotifyWhenAvailable: v.NotifyWhenAvailable,
+			CreatedAt:           v.CreatedAt.String(),
+		}
+		if b, ok := found[v.BookID]; ok {
+			item.Book = toBookResponse(b)
+		}
+		res.Items = append(res.Items, item)
+	}
+
+	return res, nil
+}
+
+func (s *ReadingListService) ListMyLists(ctx context.Context, body *book.ReadingListRequest) (*book.ReadingListsResponse, error) {
+	userData, err := s.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.repo.GetByUser(userData.GetId(), body.GetKind(), pagination.ListOptions{
+		PageSize:  int(body.GetPageSize()),
+		PageToken: body.GetPageToken(),
+	})
+	if err != nil {
+		return nil, listError(err)
+	}
+
+	lists := []*book.ReadingList{}
+	for _, v := range data.Lists {
+		lists = append(lists, toListResponse(v, true))
+	}
+
+	return &book.ReadingListsResponse{
+		Lists:         lists,
+		NextPageToken: data.NextPageToken,
+	}, nil
+}
+
+func (s *ReadingListService) AddToList(ctx context.Context, body *book.ReadingListItem) (*book.CommonListResponse, error) {
+	if body.GetBookId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "book_id cannot be empty")
+	}
+
+	userData, err := s.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := s.ownList(body.GetListId(), userData.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if body.GetNotifyWhenAvailable() && list.Kind != model.ListWishlist {
+		return nil, status.Error(codes.InvalidArgument, "availability notifications are only available on wishlists")
+	}
+
+	if _, err := s.bookRepo.GetById(ctx, &model.Book{ID: body.GetBookId()}); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Error(codes.NotFound, "book not found")
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	item := &model.ReadingListItem{
+		ListID:              list.ID,
+		BookID:              body.GetBookId(),
+		NotifyWhenAvailable: body.GetNotifyWhenAvailable(),
+	}
+	if err := s.repo.AddItem(item); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, status.Error(codes.AlreadyExists, "book is already in the list")
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &book.CommonListResponse{Message: "add book to list successfully", Id: item.ID}, nil
+}
+
+func (s *ReadingListService) RemoveFromList(ctx context.Context, body *book.ReadingListItem) (*book.CommonListResponse, error) {
+	if body.GetBookId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "book_id cannot be empty")
+	}
+
+	userData, err := s.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := s.ownList(body.GetListId(), userData.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	removed, err := s.repo.RemoveItem(list.ID, body.GetBookId())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if removed == 0 {
+		return nil, status.Error(codes.NotFound, "book is not in the list")
+	}
+
+	return &book.CommonListResponse{Message: "remove book from list successfully", Id: list.ID}, nil
+}
+
+func (s *ReadingListService) ReorderList(ctx context.Context, body *book.ReorderListRequest) (*book.CommonListResponse, error) {
+	userData, err := s.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := s.ownList(body.GetListId(), userData.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.repo.GetItems(list.ID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	remaining := map[string]bool{}
+	for _, v := range items {
+		remaining[v.BookID] = true
+	}
+	for _, id := range body.GetBookIds() {
+		if !remaining[id] {
+			return nil, status.Error(codes.InvalidArgument, "book_ids must list every book in the list exactly once")
+		}
+		delete(remaining, id)
+	}
+	if len(remaining) > 0 {
+		return nil, status.Error(codes.InvalidArgument, "book_ids must list every book in the list exactly once")
+	}
+
+	if err := s.repo.Reorder(list.ID, body.GetBookIds()); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &book.CommonListResponse{Message: "reorder list successfully", Id: list.ID}, nil
+}
+
+func (s *ReadingListService) ListNotifications(ctx context.Context, body *book.NotificationRequest) (*book.NotificationsResponse, error) {
+	userData, err := s.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.repo.GetNotifications(userData.GetId(), pagination.ListOptions{
+		PageSize:  int(body.GetPageSize()),
+		PageToken: body.GetPageToken(),
+	})
+	if err != nil {
+		return nil, listError(err)
+	}
+
+	notifications := []*book.Notification{}
+	for _, v := range data.Notifications {
+		notifications = append(notifications, &book.Notification{
+			Id:        v.ID,
+			UserId:    v.UserID,
+			BookId:    v.BookID,
+			Message:   v.Message,
+			CreatedAt: v.CreatedAt.String(),
+		})
+	}
+
+	return &book.NotificationsResponse{
+		Notifications: notifications,
+		NextPageToken: data.NextPageToken,
+	}, nil
+}
+
+func (s *ReadingListService) caller(ctx context.Context) (*user.User, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Internal, "missing outgoing metadata")
+	}
+	outbondCtx := metadata.NewOutgoingContext(ctx, md)
+
+	userData, err := s.userSvc.GetUser(outbondCtx, &emptypb.Empty{})
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid user")
+	}
+
+	return userData, nil
+}
+
+func (s *ReadingListService) ownList(id, userId string) (*model.ReadingList, error) {
+	if id == "" {
+		return nil, status.Error(codes.InvalidArgument, "list id cannot be empty")
+	}
+
+	list, err := s.repo.GetById(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Error(codes.NotFound, "list not found")
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if list.UserID != userId {
+		return nil, status.Error(codes.NotFound, "list not found")
+	}
+
+	return list, nil
+}
+
+func validateListName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", status.Error(codes.InvalidArgument, "name cannot be empty")
+	}
+	if len([]rune(name)) > maxListName {
+		return "", status.Error(codes.InvalidArgument, fmt.Sprintf("name cannot be longer than %d characters", maxListName))
+	}
+
+	return name, nil
+}
+
+// applyVisibility sets the list's visibility, issuing a share token when it
+// becomes link-shared. An empty visibility leaves the list as it is.
+func applyVisibility(list *model.ReadingList, visibility string) error {
+	if visibility == "" {
+		return nil
+	}
+
+	switch visibility {
+	case model.ListPrivate, model.ListPublic:
+		list.ShareToken = ""
+	case model.ListLink:
+		if list.Visibility != model.ListLink || list.ShareToken == "" {
+			token := make([]byte, 16)
+			if _, err := rand.Read(token); err != nil {
+				return status.Error(codes.Internal, err.Error())
+			}
+			list.ShareToken = hex.EncodeToString(token)
+		}
+	default:
+		return status.Error(codes.InvalidArgument, "visibility must be private, public or link")
+	}
+
+	list.Visibility = visibility
+	return nil
+}
+
+func toListResponse(data *model.ReadingList, owner bool) *book.ReadingList {
+	res := &book.ReadingList{
+		Id:         data.ID,
+		UserId:     data.UserID,
+		Name:       data.Name,
+		Kind:       data.Kind,
+		Visibility: data.Visibility,
+		Items:      []*book.ReadingListItem{},
+		CreatedAt:  data.CreatedAt.String(),
+		UpdatedAt:  data.UpdatedAt.String(),
+	}
+
+	if owner {
+		res.ShareToken = data.ShareToken
+	}
+
+	return res
+}
@@ -0,0 +1,317 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/shafaalafghany/book-service/model"
+	"github.com/shafaalafghany/book-service/repository"
+	"gitlab.com/shafaalafghany/synapsis-common/pagination"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/book"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/user"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"gorm.io/gorm"
+)
+
+const (
+	roleLibrarian = "librarian"
+
+	minRating     = 1
+	maxRating     = 5
+	maxReviewBody = 5000
+)
+
+type ReviewServiceInterface interface {
+	CreateReview(context.Context, *book.Review) (*book.CommonReviewResponse, error)
+	UpdateReview(context.Context, *book.Review) (*book.CommonReviewResponse, error)
+	DeleteReview(context.Context, *book.Review) (*book.CommonReviewResponse, error)
+	ListReviews(context.Context, *book.ReviewRequest) (*book.ReviewsResponse, error)
+	ModerateReview(context.Context, *book.ModerateReviewRequest) (*book.CommonReviewResponse, error)
+}
+
+type ReviewService struct {
+	repo     repository.ReviewRepositoryInterface
+	bookRepo repository.BookRepositoryInterface
+	log      *zap.Logger
+	userSvc  user.UserServiceClient
+}
+
+func NewReviewService(repo repository.ReviewRepositoryInterface, bookRepo repository.BookRepositoryInterface, log *zap.Logger, userSvc user.UserServiceClient) ReviewServiceInterface {
+	return &ReviewService{
+		repo:     repo,
+		bookRepo: bookRepo,
+		log:      log,
+		userSvc:  userSvc,
+	}
+}
+
+func (s *ReviewService) CreateReview(ctx context.Context, body *book.Review) (*book.CommonReviewResponse, error) {
+	if body.GetBookId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "book_id cannot be empty")
+	}
+
+	reviewBody, err := validateReview(body)
+	if err != nil {
+		return nil, err
+	}
+
+	userData, err := s.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.bookRepo.GetById(ctx, &model.Book{ID: body.GetBookId()}); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Error(codes.NotFound, "book not found")
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	returned, err := s.repo.HasReturnedBorrow(body.GetBookId(), userData.GetId())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if !returned {
+		return nil, status.Error(codes.FailedPrecondition, "only books you have borrowed and returned can be reviewed")
+	}
+
+	existing, err := s.repo.GetByBookAndUser(body.GetBookId(), userData.GetId())
+	if err == nil {
+		return nil, status.Error(codes.AlreadyExists, fmt.Sprintf("you already reviewed this book in review %s", existing.ID))
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	data := &model.Review{
+		BookID: body.GetBookId(),
+		UserID: userData.GetId(),
+		Rating: int(body.GetRating()),
+		Body:   reviewBody,
+		Status: model.ReviewVisible,
+	}
+
+	if err := s.repo.Create(data); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, status.Error(codes.AlreadyExists, "you already reviewed this book")
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if err := s.bookRepo.RefreshRating(ctx, data.BookID); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	response := fmt.Sprintf("create review successfully with id %s", data.ID)
+
+	return &book.CommonReviewResponse{Message: response}, nil
+}
+
+func (s *ReviewService) UpdateReview(ctx context.Context, body *book.Review) (*book.CommonReviewResponse, error) {
+	if body.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id cannot be empty")
+	}
+
+	reviewBody, err := validateReview(body)
+	if err != nil {
+		return nil, err
+	}
+
+	userData, err := s.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.ownReview(body.GetId(), userData.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(&model.Review{Rating: int(body.GetRating()), Body: reviewBody}, existing.ID); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if err := s.bookRepo.RefreshRating(ctx, existing.BookID); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &book.CommonReviewResponse{Message: "update review successfully"}, nil
+}
+
+func (s *ReviewService) DeleteReview(ctx context.Context, body *book.Review) (*book.CommonReviewResponse, error) {
+	if body.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id cannot be empty")
+	}
+
+	userData, err := s.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.ownReview(body.GetId(), userData.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Delete(existing.ID); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if err := s.bookRepo.RefreshRating(ctx, existing.BookID); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &book.CommonReviewResponse{Message: "delete review successfully"}, nil
+}
+
+func (s *ReviewService) ListReviews(ctx context.Context, body *book.ReviewRequest) (*book.ReviewsResponse, error) {
+	if body.GetBookId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "book_id cannot be empty")
+	}
+
+	userData, err := s.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if body.GetIncludeHidden() && !isModerator(userData.GetRole()) {
+		return nil, status.Error(codes.PermissionDenied, "librarian role required to list hidden reviews")
+	}
+
+	data, err := s.repo.Get(repository.ReviewQuery{
+		BookID:        body.GetBookId(),
+		IncludeHidden: body.GetIncludeHidden(),
+	}, pagination.ListOptions{
+		PageSize:     int(body.GetPageSize()),
+		PageToken:    body.GetPageToken(),
+		SortBy:       body.GetSortBy(),
+		SortOrder:    body.GetSortOrder(),
+		IncludeTotal: body.GetIncludeTotal(),
+	})
+	if err != nil {
+		return nil, listError(err)
+	}
+
+	reviews := []*book.Review{}
+	for _, v := range data.Reviews {
+		reviews = append(reviews, toReviewResponse(v))
+	}
+
+	return &book.ReviewsResponse{
+		Reviews:       reviews,
+		NextPageToken: data.NextPageToken,
+		TotalCount:    data.TotalCount,
+	}, nil
+}
+
+func (s *ReviewService) ModerateReview(ctx context.Context, body *book.ModerateReviewRequest) (*book.CommonReviewResponse, error) {
+	if body.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id cannot be empty")
+	}
+
+	switch body.GetStatus() {
+	case model.ReviewVisible, model.ReviewFlagged, model.ReviewHidden:
+	default:
+		return nil, status.Error(codes.InvalidArgument, "status must be visible, flagged or hidden")
+	}
+
+	userData, err := s.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isModerator(userData.GetRole()) {
+		return nil, status.Error(codes.PermissionDenied, "librarian role required")
+	}
+
+	existing, err := s.repo.GetById(body.GetId())
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Error(codes.NotFound, "review not found")
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	moderated := &model.Review{
+		Status:         body.GetStatus(),
+		ModerationNote: strings.TrimSpace(body.GetNote()),
+		ModeratedBy:    userData.GetId(),
+	}
+	if err := s.repo.Moderate(moderated, existing.ID); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if err := s.bookRepo.RefreshRating(ctx, existing.BookID); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &book.CommonReviewResponse{Message: fmt.Sprintf("review marked as %s", body.GetStatus())}, nil
+}
+
+func (s *ReviewService) caller(ctx context.Context) (*user.User, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Internal, "missing outgoing metadata")
+	}
+	outbondCtx := metadata.NewOutgoingContext(ctx, md)
+
+	userData, err := s.userSvc.GetUser(outbondCtx, &emptypb.Empty{})
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid user")
+	}
+
+	return userData, nil
+}
+
+func (s *ReviewService) ownReview(id, userId string) (*model.Review, error) {
+	existing, err := s.repo.GetById(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Error(codes.NotFound, "review not found")
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if existing.UserID != userId {
+		return nil, status.Error(codes.PermissionDenied, "you can only change your own review")
+	}
+
+	return existing, nil
+}
+
+func validateReview(body *book.Review) (string, error) {
+	if body.GetRating() < minRating || body.GetRating() > maxRating {
+		return "", status.Error(codes.InvalidArgument, fmt.Sprintf("rating must be between %d and %d", minRating, maxRating))
+	}
+
+	reviewBody := strings.TrimSpace(body.GetBody())
+	if utf8.RuneCountInString(reviewBody) > maxReviewBody {
+		return "", status.Error(codes.InvalidArgument, fmt.Sprintf("review cannot be longer than %d characters", maxReviewBody))
+	}
+
+	return reviewBody, nil
+}
+
+func isModerator(role string) bool {
+	return role == roleLibrarian || role == roleAdmin
+}
+
+func toReviewResponse(data *model.Review) *book.Review {
+	return &book.Review{
+		Id:             data.ID,
+		BookId:         data.BookID,
+		UserId:         data.UserID,
+		Rating:         int32(data.Rating),
+		Body:           data.Body,
+		Status:         data.Status,
+		ModerationNote: data.ModerationNote,
+		CreatedAt:      data.CreatedAt.String(),
+		UpdatedAt:      data.UpdatedAt.String(),
+	}
+}
@@ -0,0 +1,417 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shafaalafghany/book-service/model"
+	"github.com/shafaalafghany/book-service/repository"
+	"gitlab.com/shafaalafghany/synapsis-common/pagination"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/book"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/user"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"gorm.io/gorm"
+)
+
+var (
+	holdStatuses     = []string{model.HoldWaiting, model.HoldInTransit, model.HoldReady, model.HoldFulfilled, model.HoldCancelled}
+	transferStatuses = []string{model.TransferRequested, model.TransferInTransit, model.TransferReceived, model.TransferCancelled}
+)
+
+type TransferServiceInterface interface {
+	PlaceHold(context.Context, *book.Hold) (*book.CommonHoldResponse, error)
+	CancelHold(context.Context, *book.Hold) (*book.CommonHoldResponse, error)
+	ListHolds(context.Context, *book.HoldRequest) (*book.HoldsResponse, error)
+	RequestTransfer(context.Context, *book.Transfer) (*book.CommonTransferResponse, error)
+	DispatchTransfer(context.Context, *book.Transfer) (*book.CommonTransferResponse, error)
+	ReceiveTransfer(context.Context, *book.Transfer) (*book.CommonTransferResponse, error)
+	CancelTransfer(context.Context, *book.Transfer) (*book.CommonTransferResponse, error)
+	ListTransfers(context.Context, *book.TransferRequest) (*book.TransfersResponse, error)
+}
+
+type TransferService struct {
+	repo       repository.BookRepositoryInterface
+	branchRepo repository.BranchRepositoryInterface
+	log        *zap.Logger
+	userSvc    user.UserServiceClient
+}
+
+func NewTransferService(repo repository.BookRepositoryInterface, branchRepo repository.BranchRepositoryInterface, log *zap.Logger, userSvc user.UserServiceClient) TransferServiceInterface {
+	return &TransferService{
+		repo:       repo,
+		branchRepo: branchRepo,
+		log:        log,
+		userSvc:    userSvc,
+	}
+}
+
+// PlaceHold queues the signed-in reader for a physical book, to be picked up
+// at the given branch.
+func (s *TransferService) PlaceHold(ctx context.Context, body *book.Hold) (*book.CommonHoldResponse, error) {
+	if body.GetBookId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "book_id cannot be empty")
+	}
+	if body.GetPickupBranchId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "pickup_branch_id cannot be empty")
+	}
+
+	userData, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.physicalBook(ctx, body.GetBookId()); err != nil {
+		return nil, err
+	}
+	if err := s.checkBranch(body.GetPickupBranchId()); err != nil {
+		return nil, err
+	}
+
+	hold := &model.Hold{
+		BookID:         body.GetBookId(),
+		UserID:         userData.GetId(),
+		PickupBranchID: body.GetPickupBranchId(),
+	}
+	if err := s.repo.PlaceHold(ctx, hold); err != nil {
+		if errors.Is(err, repository.ErrHoldExists) {
+			return nil, status.Error(codes.AlreadyExists, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	res := &book.CommonHoldResponse{
+		Message: fmt.Sprintf("place hold successfully with id %s", hold.ID),
+		Id:      hold.ID,
+		Status:  hold.Status,
+	}
+	if placed, err := s.repo.GetHoldById(ctx, hold.ID); err == nil {
+		res.Status = placed.Status
+	}
+
+	return res, nil
+}
+
+// CancelHold can be called by the reader who placed the hold or by a
+// librarian.
+func (s *TransferService) CancelHold(ctx context.Context, body *book.Hold) (*book.CommonHoldResponse, error) {
+	if body.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id cannot be empty")
+	}
+
+	userData, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	hold, err := s.repo.GetHoldById(ctx, body.GetId())
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Error(codes.NotFound, "hold not found")
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if hold.UserID != userData.GetId() && !isModerator(userData.GetRole()) {
+		return nil, status.Error(codes.PermissionDenied, "only the reader who placed the hold can cancel it")
+	}
+
+	if err := s.repo.CancelHold(ctx, hold.ID); err != nil {
+		return nil, transferError(err)
+	}
+
+	return &book.CommonHoldResponse{Message: "cancel hold successfully", Id: hold.ID, Status: model.HoldCancelled}, nil
+}
+
+// ListHolds returns the caller's own holds. Librarians can list everyone's,
+// for example the holds waiting at their branch.
+func (s *TransferService) ListHolds(ctx context.Context, body *book.HoldRequest) (*book.HoldsResponse, error) {
+	if body.GetStatus() != "" && !slices.Contains(holdStatuses, body.GetStatus()) {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("status must be one of %s", strings.Join(holdStatuses, ", ")))
+	}
+
+	userData, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := repository.HoldFilter{
+		BookID:   body.GetBookId(),
+		UserID:   body.GetUserId(),
+		BranchID: body.GetBranchId(),
+		Status:   body.GetStatus(),
+	}
+	if !isModerator(userData.GetRole()) {
+		filter.UserID = userData.GetId()
+	}
+
+	page, err := s.repo.GetHolds(ctx, filter, pagination.ListOptions{
+		PageSize:  int(body.GetPageSize()),
+		PageToken: body.GetPageToken(),
+		SortOrder: body.GetSortOrder(),
+	})
+	if err != nil {
+		return nil, listError(err)
+	}
+
+	res := &book.HoldsResponse{NextPageToken: page.NextPageToken}
+	for _, v := range page.Holds {
+		res.Holds = append(res.Holds, toHoldResponse(v))
+	}
+
+	return res, nil
+}
+
+// RequestTransfer asks for a physical item to be sent from its current
+// branch to another one. The item stops being available right away.
+func (s *TransferService) RequestTransfer(ctx context.Context, body *book.Transfer) (*book.CommonTransferResponse, error) {
+	if body.GetBookId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "book_id cannot be empty")
+	}
+	if body.GetToBranchId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "to_branch_id cannot be empty")
+	}
+
+	userId, err := s.requireModerator(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.physicalBook(ctx, body.GetBookId()); err != nil {
+		return nil, err
+	}
+	if err := s.checkBranch(body.GetToBranchId()); err != nil {
+		return nil, err
+	}
+
+	transfer := &model.Transfer{
+		BookID:      body.GetBookId(),
+		ToBranchID:  body.GetToBranchId(),
+		RequestedBy: userId,
+	}
+	if err := s.repo.RequestTransfer(ctx, transfer); err != nil {
+		return nil, transferError(err)
+	}
+
+	return &book.CommonTransferResponse{
+		Message: fmt.Sprintf("request transfer successfully with id %s", transfer.ID),
+		Id:      transfer.ID,
+	}, nil
+}
+
+func (s *TransferService) DispatchTransfer(ctx context.Context, body *book.Transfer) (*book.CommonTransferResponse, error) {
+	userId, err := s.transfer(ctx, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.DispatchTransfer(ctx, body.GetId(), userId); err != nil {
+		return nil, transferError(err)
+	}
+
+	return &book.CommonTransferResponse{Message: "dispatch transfer successfully", Id: body.GetId()}, nil
+}
+
+func (s *TransferService) ReceiveTransfer(ctx context.Context, body *book.Transfer) (*book.CommonTransferResponse, error) {
+	userId, err := s.transfer(ctx, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.ReceiveTransfer(ctx, body.GetId(), userId); err != nil {
+		return nil, transferError(err)
+	}
+
+	return &book.CommonTransferResponse{Message: "receive transfer successfully", Id: body.GetId()}, nil
+}
+
+func (s *TransferService) CancelTransfer(ctx context.Context, body *book.Transfer) (*book.CommonTransferResponse, error) {
+	if _, err := s.transfer(ctx, body); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CancelTransfer(ctx, body.GetId()); err != nil {
+		return nil, transferError(err)
+	}
+
+	return &book.CommonTransferResponse{Message: "cancel transfer successfully", Id: body.GetId()}, nil
+}
+
+// ListTransfers returns a branch's transfer manifest. Outgoing transfers are
+// the items to pull and send, incoming ones the items to expect.
+func (s *TransferService) ListTransfers(ctx context.Context, body *book.TransferRequest) (*book.TransfersResponse, error) {
+	direction := strings.ToLower(strings.TrimSpace(body.GetDirection()))
+	if direction != "" && direction != "incoming" && direction != "outgoing" {
+		return nil, status.Error(codes.InvalidArgument, "direction must be incoming or outgoing")
+	}
+	if direction != "" && body.GetBranchId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "branch_id is required with direction")
+	}
+	if body.GetStatus() != "" && !slices.Contains(transferStatuses, body.GetStatus()) {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("status must be one of %s", strings.Join(transferStatuses, ", ")))
+	}
+
+	if _, err := s.requireModerator(ctx); err != nil {
+		return nil, err
+	}
+
+	page, err := s.repo.GetTransfers(ctx, repository.TransferFilter{
+		BranchID:  body.GetBranchId(),
+		Direction: direction,
+		Status:    body.GetStatus(),
+	}, pagination.ListOptions{
+		PageSize:  int(body.GetPageSize()),
+		PageToken: body.GetPageToken(),
+		SortOrder: body.GetSortOrder(),
+	})
+	if err != nil {
+		return nil, listError(err)
+	}
+
+	res := &book.TransfersResponse{NextPageToken: page.NextPageToken}
+	for _, v := range page.Transfers {
+		res.Transfers = append(res.Transfers, toTransferResponse(v))
+	}
+
+	return res, nil
+}
+
+// transfer checks that the caller may handle transfers and that the
+// transfer exists, returning the caller's id.
+func (s *TransferService) transfer(ctx context.Context, body *book.Transfer) (string, error) {
+	if body.GetId() == "" {
+		return "", status.Error(codes.InvalidArgument, "id cannot be empty")
+	}
+
+	userId, err := s.requireModerator(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := s.repo.GetTransferById(ctx, body.GetId()); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", status.Error(codes.NotFound, "transfer not found")
+		}
+		return "", status.Error(codes.Internal, err.Error())
+	}
+
+	return userId, nil
+}
+
+func (s *TransferService) physicalBook(ctx context.Context, id string) (*model.Book, error) {
+	data, err := s.repo.GetById(ctx, &model.Book{ID: id})
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "book not found")
+	}
+	if data.ItemType == model.ItemDigital {
+		return nil, status.Error(codes.FailedPrecondition, "digital books are not held or transferred between branches")
+	}
+
+	return data, nil
+}
+
+func (s *TransferService) checkBranch(id string) error {
+	if _, err := s.branchRepo.GetById(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return status.Error(codes.InvalidArgument, fmt.Sprintf("branch %s not found", id))
+		}
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	return nil
+}
+
+func (s *TransferService) authenticate(ctx context.Context) (*user.User, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Internal, "missing outgoing metadata")
+	}
+	outbondCtx := metadata.NewOutgoingContext(ctx, md)
+
+	userData, err := s.userSvc.GetUser(outbondCtx, &emptypb.Empty{})
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid user")
+	}
+
+	return userData, nil
+}
+
+func (s *TransferService) requireModerator(ctx context.Context) (string, error) {
+	userData, err := s.authenticate(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if !isModerator(userData.GetRole()) {
+		return "", status.Error(codes.PermissionDenied, "only librarians and admins can manage transfers")
+	}
+
+	return userData.GetId(), nil
+}
+
+func transferError(err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return status.Error(codes.NotFound, "book not found")
+	case errors.Is(err, repository.ErrSameBranch):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, repository.ErrBookUnavailable),
+		errors.Is(err, repository.ErrBookOnHold),
+		errors.Is(err, repository.ErrInvalidStatus),
+		errors.Is(err, repository.ErrNoLocation):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	return status.Error(codes.Internal, err.Error())
+}
+
+func toHoldResponse(data *model.Hold) *book.Hold {
+	res := &book.Hold{
+		Id:             data.ID,
+		BookId:         data.BookID,
+		UserId:         data.UserID,
+		PickupBranchId: data.PickupBranchID,
+		Status:         data.Status,
+		CreatedAt:      data.CreatedAt.String(),
+		UpdatedAt:      data.UpdatedAt.String(),
+	}
+	if data.ReadyAt != nil {
+		res.ReadyAt = data.ReadyAt.UTC().Format(time.RFC3339)
+	}
+
+	return res
+}
+
+func toTransferResponse(data *model.Transfer) *book.Transfer {
+	res := &book.Transfer{
+		Id:           data.ID,
+		BookId:       data.BookID,
+		BookName:     data.BookName,
+		BookIsbn:     data.BookISBN,
+		FromBranchId: data.FromBranchID,
+		ToBranchId:   data.ToBranchID,
+		Status:       data.Status,
+		Reason:       data.Reason,
+		HoldId:       data.HoldID,
+		RequestedBy:  data.RequestedBy,
+		DispatchedBy: data.DispatchedBy,
+		ReceivedBy:   data.ReceivedBy,
+		CreatedAt:    data.CreatedAt.String(),
+		UpdatedAt:    data.UpdatedAt.String(),
+	}
+	if data.DispatchedAt != nil {
+		res.DispatchedAt = data.DispatchedAt.UTC().Format(time.RFC3339)
+	}
+	if data.ReceivedAt != nil {
+		res.ReceivedAt = data.ReceivedAt.UTC().Format(time.RFC3339)
+	}
+
+	return res
+}
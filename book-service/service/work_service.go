@@ -0,0 +1,351 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/shafaalafghany/book-service/model"
+	"github.com/shafaalafghany/book-service/repository"
+	"gitlab.com/shafaalafghany/synapsis-common/pagination"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/author"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/book"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/category"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/user"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"gorm.io/gorm"
+)
+
+const maxWorkTitle = 255
+
+type WorkServiceInterface interface {
+	CreateWork(context.Context, *book.Work) (*book.CommonWorkResponse, error)
+	UpdateWork(context.Context, *book.Work) (*book.CommonWorkResponse, error)
+	GetWork(context.Context, *book.Work) (*book.Work, error)
+	ListEditions(context.Context, *book.EditionsRequest) (*book.BooksResponse, error)
+	CreateSeries(context.Context, *book.Series) (*book.CommonWorkResponse, error)
+	UpdateSeries(context.Context, *book.Series) (*book.CommonWorkResponse, error)
+	GetSeries(context.Context, *book.Series) (*book.Series, error)
+	ListSeriesBooks(context.Context, *book.SeriesBooksRequest) (*book.BooksResponse, error)
+}
+
+type WorkService struct {
+	repo        repository.WorkRepositoryInterface
+	bookRepo    repository.BookRepositoryInterface
+	log         *zap.Logger
+	userSvc     user.UserServiceClient
+	authorSvc   author.AuthorServiceClient
+	categorySvc category.CategoryServiceClient
+}
+
+func NewWorkService(repo repository.WorkRepositoryInterface, bookRepo repository.BookRepositoryInterface, log *zap.Logger, userSvc user.UserServiceClient, authorSvc author.AuthorServiceClient, categorySvc category.CategoryServiceClient) WorkServiceInterface {
+	return &WorkService{
+		repo:        repo,
+		bookRepo:    bookRepo,
+		log:         log,
+		userSvc:     userSvc,
+		authorSvc:   authorSvc,
+		categorySvc: categorySvc,
+	}
+}
+
+func (s *WorkService) CreateWork(ctx context.Context, body *book.Work) (*book.CommonWorkResponse, error) {
+	title, description, err := validateGrouping("title", body.GetTitle(), body.GetDescription())
+	if err != nil {
+		return nil, err
+	}
+
+	outbondCtx, err := s.outgoing(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userData, err := s.userSvc.GetUser(outbondCtx, &emptypb.Empty{})
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid user")
+	}
+
+	data := &model.Work{
+		Title:       title,
+		Description: description,
+		CreatedBy:   userData.GetId(),
+	}
+	if err := s.repo.CreateWork(data); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &book.CommonWorkResponse{
+		Message: fmt.Sprintf("create new work successfully with id %s", data.ID),
+		Id:      data.ID,
+	}, nil
+}
+
+func (s *WorkService) UpdateWork(ctx context.Context, body *book.Work) (*book.CommonWorkResponse, error) {
+	if body.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id cannot be empty")
+	}
+
+	title, description, err := validateGrouping("title", body.GetTitle(), body.GetDescription())
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.authenticate(ctx); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.work(body.GetId()); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateWork(&model.Work{Title: title, Description: description}, body.GetId()); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &book.CommonWorkResponse{Message: "update work successfully", Id: body.GetId()}, nil
+}
+
+func (s *WorkService) GetWork(ctx context.Context, body *book.Work) (*book.Work, error) {
+	if body.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id cannot be empty")
+	}
+
+	if _, err := s.authenticate(ctx); err != nil {
+		return nil, err
+	}
+
+	data, err := s.work(body.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	return &book.Work{
+		Id:           data.ID,
+		Title:        data.Title,
+		Description:  data.Description,
+		CreatedBy:    data.CreatedBy,
+		CreatedAt:    data.CreatedAt.String(),
+		UpdatedAt:    data.UpdatedAt.String(),
+		EditionCount: int32(data.EditionCount),
+	}, nil
+}
+
+func (s *WorkService) ListEditions(ctx context.Context, body *book.EditionsRequest) (*book.BooksResponse, error) {
+	if body.GetWorkId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "work_id cannot be empty")
+	}
+
+	outbondCtx, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.work(body.GetWorkId()); err != nil {
+		return nil, err
+	}
+
+	data, err := s.bookRepo.GetEditions(ctx, body.GetWorkId(), pagination.ListOptions{
+		PageSize:  int(body.GetPageSize()),
+		PageToken: body.GetPageToken(),
+		SortBy:    body.GetSortBy(),
+		SortOrder: body.GetSortOrder(),
+	})
+	if err != nil {
+		return nil, listError(err)
+	}
+
+	return s.booksResponse(outbondCtx, data, body.GetView())
+}
+
+func (s *WorkService) CreateSeries(ctx context.Context, body *book.Series) (*book.CommonWorkResponse, error) {
+	name, description, err := validateGrouping("name", body.GetName(), body.GetDescription())
+	if err != nil {
+		return nil, err
+	}
+
+	outbondCtx, err := s.outgoing(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userData, err := s.userSvc.GetUser(outbondCtx, &emptypb.Empty{})
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid user")
+	}
+
+	data := &model.Series{
+		Name:        name,
+		Description: description,
+		CreatedBy:   userData.GetId(),
+	}
+	if err := s.repo.CreateSeries(data); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &book.CommonWorkResponse{
+		Message: fmt.Sprintf("create new series successfully with id %s", data.ID),
+		Id:      data.ID,
+	}, nil
+}
+
+func (s *WorkService) UpdateSeries(ctx context.Context, body *book.Series) (*book.CommonWorkResponse, error) {
+	if body.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id cannot be empty")
+	}
+
+	name, description, err := validateGrouping("name", body.GetName(), body.GetDescription())
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.authenticate(ctx); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.series(body.GetId()); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateSeries(&model.Series{Name: name, Description: description}, body.GetId()); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &book.CommonWorkResponse{Message: "update series successfully", Id: body.GetId()}, nil
+}
+
+func (s *WorkService) GetSeries(ctx context.Context, body *book.Series) (*book.Series, error) {
+	if body.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id cannot be empty")
+	}
+
+	if _, err := s.authenticate(ctx); err != nil {
+		return nil, err
+	}
+
+	data, err := s.series(body.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	return &book.Series{
+		Id:          data.ID,
+		Name:        data.Name,
+		Description: data.Description,
+		CreatedBy:   data.CreatedBy,
+		CreatedAt:   data.CreatedAt.String(),
+		UpdatedAt:   data.UpdatedAt.String(),
+		BookCount:   int32(data.BookCount),
+	}, nil
+}
+
+func (s *WorkService) ListSeriesBooks(ctx context.Context, body *book.SeriesBooksRequest) (*book.BooksResponse, error) {
+	if body.GetSeriesId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "series_id cannot be empty")
+	}
+
+	outbondCtx, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.series(body.GetSeriesId()); err != nil {
+		return nil, err
+	}
+
+	data, err := s.bookRepo.GetSeriesBooks(ctx, body.GetSeriesId(), pagination.ListOptions{
+		PageSize:  int(body.GetPageSize()),
+		PageToken: body.GetPageToken(),
+	})
+	if err != nil {
+		return nil, listError(err)
+	}
+
+	return s.booksResponse(outbondCtx, data, body.GetView())
+}
+
+func (s *WorkService) outgoing(ctx context.Context) (context.Context, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Internal, "missing outgoing metadata")
+	}
+
+	return metadata.NewOutgoingContext(ctx, md), nil
+}
+
+func (s *WorkService) authenticate(ctx context.Context) (context.Context, error) {
+	outbondCtx, err := s.outgoing(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.userSvc.GetUser(outbondCtx, &emptypb.Empty{}); err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid user")
+	}
+
+	return outbondCtx, nil
+}
+
+func (s *WorkService) work(id string) (*model.Work, error) {
+	data, err := s.repo.GetWorkById(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Error(codes.NotFound, "work not found")
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return data, nil
+}
+
+func (s *WorkService) series(id string) (*model.Series, error) {
+	data, err := s.repo.GetSeriesById(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Error(codes.NotFound, "series not found")
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return data, nil
+}
+
+func (s *WorkService) booksResponse(ctx context.Context, data *repository.BookPage, view book.BookView) (*book.BooksResponse, error) {
+	books := []*book.Book{}
+	for _, v := range data.Books {
+		books = append(books, toBookResponse(v))
+	}
+
+	if view == book.BookView_FULL {
+		if err := newBookLoader(s.authorSvc, s.categorySvc).load(ctx, books); err != nil {
+			return nil, err
+		}
+	}
+
+	return &book.BooksResponse{
+		Books:         books,
+		NextPageToken: data.NextPageToken,
+	}, nil
+}
+
+func validateGrouping(field, name, description string) (string, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", "", status.Error(codes.InvalidArgument, fmt.Sprintf("%s cannot be empty", field))
+	}
+	if utf8.RuneCountInString(name) > maxWorkTitle {
+		return "", "", status.Error(codes.InvalidArgument, fmt.Sprintf("%s cannot be longer than %d characters", field, maxWorkTitle))
+	}
+
+	description = strings.TrimSpace(description)
+	if utf8.RuneCountInString(description) > maxDescription {
+		return "", "", status.Error(codes.InvalidArgument, fmt.Sprintf("description cannot be longer than %d characters", maxDescription))
+	}
+
+	return name, description, nil
+}
@@ -15,7 +15,7 @@ require (
 	golang.org/x/net v0.29.0 // indirect
 	golang.org/x/sync v0.8.0 // indirect
 	golang.org/x/sys v0.25.0 // indirect
-	google.golang.org/genproto/googleapis/rpc v0.0.0-20240903143218-8af14fe29dc1 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240903143218-8af14fe29dc1
 	google.golang.org/protobuf v1.35.2 // indirect
 )
 
@@ -25,8 +25,13 @@ require (
 	github.com/jinzhu/inflection v1.0.0 // indirect
 	github.com/jinzhu/now v1.1.5 // indirect
 	github.com/joho/godotenv v1.5.1
+	gitlab.com/shafaalafghany/synapsis-common v0.0.0-00010101000000-000000000000
 	go.uber.org/zap v1.27.0
 	golang.org/x/text v0.18.0 // indirect
 	google.golang.org/grpc v1.68.1
 	gorm.io/driver/postgres v1.5.11
 )
+
+replace gitlab.com/shafaalafghany/synapsis-proto => ../proto
+
+replace gitlab.com/shafaalafghany/synapsis-common => ../common
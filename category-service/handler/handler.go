@@ -48,6 +48,33 @@ func (ch *CategoryHandler) Get(ctx context.Context, body *category.Category) (*c
 	return ch.cs.GetCategory(ctx, body)
 }
 
+func (ch *CategoryHandler) GetCategoryBySlug(ctx context.Context, body *category.Category) (*category.Category, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return ch.cs.GetCategoryBySlug(ctx, body)
+}
+
+func (ch *CategoryHandler) BatchGet(ctx context.Context, body *category.BatchGetCategoriesRequest) (*category.BatchGetCategoriesResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return ch.cs.BatchGetCategories(ctx, body)
+}
+
+func (ch *CategoryHandler) GetAncestors(ctx context.Context, body *category.Category) (*category.CategoriesResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return ch.cs.GetCategoryAncestors(ctx, body)
+}
+
 func (ch *CategoryHandler) GetList(ctx context.Context, body *category.CategoryRequest) (*category.CategoriesResponse, error) {
 	_, err := getUserIDFromContext(ctx)
 	if err != nil {
@@ -57,6 +84,15 @@ func (ch *CategoryHandler) GetList(ctx context.Context, body *category.CategoryR
 	return ch.cs.GetCategories(ctx, body)
 }
 
+func (ch *CategoryHandler) Resolve(ctx context.Context, body *category.ResolveCategoriesRequest) (*category.ResolveCategoriesResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return ch.cs.ResolveCategories(ctx, body)
+}
+
 func (ch *CategoryHandler) Update(ctx context.Context, body *category.Category) (*category.CommonCategoryResponse, error) {
 	_, err := getUserIDFromContext(ctx)
 	if err != nil {
@@ -74,6 +110,24 @@ func (ch *CategoryHandler) Delete(ctx context.Context, body *category.Category)
 	return ch.cs.DeleteCategory(ctx, body)
 }
 
+func (ch *CategoryHandler) ListDeleted(ctx context.Context, body *category.CategoryRequest) (*category.CategoriesResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return ch.cs.ListDeletedCategories(ctx, body)
+}
+
+func (ch *CategoryHandler) Restore(ctx context.Context, body *category.Category) (*category.CommonCategoryResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return ch.cs.RestoreCategory(ctx, body)
+}
+
 func getUserIDFromContext(ctx context.Context) (string, error) {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
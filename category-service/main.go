@@ -1,10 +1,12 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net"
 	"os"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/shafaalafghany/category-service/handler"
@@ -12,6 +14,8 @@ import (
 	"github.com/shafaalafghany/category-service/model"
 	"github.com/shafaalafghany/category-service/repository"
 	"github.com/shafaalafghany/category-service/service"
+	"gitlab.com/shafaalafghany/synapsis-common/purge"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/book"
 	"gitlab.com/shafaalafghany/synapsis-proto/go/category"
 	"gitlab.com/shafaalafghany/synapsis-proto/go/user"
 	"go.uber.org/zap"
@@ -32,6 +36,10 @@ type Config struct {
 	JwtSecret   string
 	AppPort     string
 	UserService string
+	BookService string
+
+	PurgeRetention string
+	PurgeInterval  string
 }
 
 func main() {
@@ -46,6 +54,10 @@ func main() {
 		DBPassword:  os.Getenv("DB_PASS"),
 		DBName:      os.Getenv("DB_NAME"),
 		UserService: os.Getenv("USER_SERVICE"),
+		BookService: os.Getenv("BOOK_SERVICE"),
+
+		PurgeRetention: os.Getenv("PURGE_RETENTION"),
+		PurgeInterval:  os.Getenv("PURGE_INTERVAL"),
 	}
 
 	logConfig := zap.NewDevelopmentConfig()
@@ -56,13 +68,19 @@ func main() {
 	}
 
 	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s", config.DBHost, config.DBUser, config.DBPassword, config.DBName, config.DBPort)
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{TranslateError: true})
 	if err != nil {
 		panic(err)
 	}
 
 	db.Exec("CREATE EXTENSION IF NOT EXISTS \"uuid-ossp\"")
 	db.AutoMigrate(&model.Category{})
+	if err := db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_name_lower ON categories (LOWER(name)) WHERE deleted_at IS NULL").Error; err != nil {
+		logger.Error("failed to create unique category name index, rename or delete duplicate categories", zap.Error(err))
+	}
+	if err := db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_slug ON categories (slug) WHERE deleted_at IS NULL AND slug <> ''").Error; err != nil {
+		logger.Error("failed to create unique category slug index", zap.Error(err))
+	}
 
 	userConn, err := grpc.NewClient(config.UserService, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	if err != nil {
@@ -74,8 +92,40 @@ func main() {
 
 	categoryRepo := repository.NewCategoryRepository(db, logger)
 	categoryService := service.NewCategoryService(categoryRepo, logger, userClient)
+
+	if count, err := categoryService.BackfillSlugs(); err != nil {
+		logger.Error("failed to backfill category slugs", zap.Error(err))
+	} else if count > 0 {
+		logger.Info("backfilled category slugs", zap.Int64("count", count))
+	}
 	categoryHandler := handler.NewCategoryHandler(categoryService, logger)
 
+	if config.PurgeRetention != "" {
+		retention, err := time.ParseDuration(config.PurgeRetention)
+		if err != nil {
+			log.Fatalf("invalid PURGE_RETENTION %v", err)
+		}
+
+		interval := 24 * time.Hour
+		if config.PurgeInterval != "" {
+			if interval, err = time.ParseDuration(config.PurgeInterval); err != nil {
+				log.Fatalf("invalid PURGE_INTERVAL %v", err)
+			}
+		}
+
+		if config.BookService == "" {
+			log.Fatalf("PURGE_RETENTION requires BOOK_SERVICE to check which categories books still use")
+		}
+		bookConn, err := grpc.NewClient(config.BookService, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			log.Fatalf("failed to connect book service %v", err)
+		}
+		defer bookConn.Close()
+
+		referenced := service.ReferencedByBooks(book.NewBookServiceClient(bookConn), config.JwtSecret)
+		go purge.NewJob(purge.SkipReferenced(categoryRepo, referenced), "categories", logger, retention, interval).Run(context.Background())
+	}
+
 	server := grpc.NewServer(
 		grpc.UnaryInterceptor(middleware.JWTAuthInterceptor(config.JwtSecret)),
 	)
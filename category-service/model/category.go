@@ -10,6 +10,8 @@ import (
 type Category struct {
 	ID        string     `json:"id" gorm:"type:uuid;default:uuid_generate_v4();primary_key"`
 	Name      string     `json:"name" gorm:"not null;index"`
+	Slug      string     `json:"slug" gorm:"not null;default:''"`
+	ParentID  string     `json:"parent_id" gorm:"not null;default:'';index"`
 	CreatedBy string     `json:"created_by" gorm:"not null"`
 	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
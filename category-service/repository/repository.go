@@ -1,9 +1,11 @@
 package repository
 
 import (
+	"strings"
 	"time"
 
 	"github.com/shafaalafghany/category-service/model"
+	"gitlab.com/shafaalafghany/synapsis-common/pagination"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
@@ -11,16 +13,50 @@ import (
 type CategoryRepositoryInterface interface {
 	Create(*model.Category) error
 	GetById(string) (*model.Category, error)
-	Get(string) ([]*model.Category, error)
+	GetByIds([]string) ([]*model.Category, error)
+	GetByName(string) (*model.Category, error)
+	GetByNames([]string) ([]*model.Category, error)
+	GetBySlug(string) (*model.Category, error)
+	GetAncestors(string) ([]*model.Category, error)
+	SlugExists(string, string) (bool, error)
+	GetWithoutSlug(int) ([]*model.Category, error)
+	SetSlug(string, string) error
+	Get(pagination.ListOptions) (*CategoryPage, error)
 	Update(*model.Category, string) error
 	Delete(string) error
+	GetDeleted(pagination.ListOptions) (*CategoryPage, error)
+	GetDeletedById(string) (*model.Category, error)
+	Restore(*model.Category, string) error
+	Purgeable(time.Time, string, int) ([]string, error)
+	PurgeIds(time.Time, []string) (int64, error)
 }
 
+const maxCategoryDepth = 32
+
 type CategoryRepository struct {
 	db  *gorm.DB
 	log *zap.Logger
 }
 
+type CategoryPage struct {
+	Categories    []*model.Category
+	NextPageToken string
+	TotalCount    int64
+}
+
+var categorySortColumns = map[string]pagination.SortKind{
+	"name":       pagination.SortString,
+	"created_at": pagination.SortTime,
+	"updated_at": pagination.SortTime,
+}
+
+var deletedCategorySortColumns = map[string]pagination.SortKind{
+	"name":       pagination.SortString,
+	"created_at": pagination.SortTime,
+	"updated_at": pagination.SortTime,
+	"deleted_at": pagination.SortTime,
+}
+
 func NewCategoryRepository(db *gorm.DB, log *zap.Logger) CategoryRepositoryInterface {
 	return &CategoryRepository{
 		db:  db,
@@ -44,25 +80,119 @@ func (cr *CategoryRepository) GetById(id string) (*model.Category, error) {
 	return &category, nil
 }
 
-func (cr *CategoryRepository) Get(search string) ([]*model.Category, error) {
+func (cr *CategoryRepository) GetByIds(ids []string) ([]*model.Category, error) {
 	var categories []*model.Category
-	base := cr.db.Model(&model.Category{}).Where("deleted_at IS NULL")
+	if err := cr.db.Where("id IN ? AND deleted_at IS NULL", ids).Find(&categories).Error; err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+func (cr *CategoryRepository) GetByName(name string) (*model.Category, error) {
+	var category model.Category
+	if err := cr.db.Where("LOWER(name) = LOWER(?) AND deleted_at IS NULL", name).First(&category).Error; err != nil {
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+func (cr *CategoryRepository) GetAncestors(id string) ([]*model.Category, error) {
+	var categories []*model.Category
+	if err := cr.db.Raw(`
+		WITH RECURSIVE ancestors AS (
+			SELECT parent_id, 1 AS depth FROM categories WHERE id = ?
+			UNION ALL
+			SELECT c.parent_id, a.depth + 1 FROM categories c
+			JOIN ancestors a ON c.id::text = a.parent_id
+			WHERE a.depth < ?
+		)
+		SELECT categories.* FROM categories
+		JOIN ancestors ON categories.id::text = ancestors.parent_id
+		WHERE categories.deleted_at IS NULL
+		ORDER BY ancestors.depth`, id, maxCategoryDepth).Scan(&categories).Error; err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+func (cr *CategoryRepository) GetByNames(names []string) ([]*model.Category, error) {
+	lowered := make([]string, 0, len(names))
+	for _, name := range names {
+		lowered = append(lowered, strings.ToLower(name))
+	}
+
+	var categories []*model.Category
+	if err := cr.db.Where("LOWER(name) IN ? AND deleted_at IS NULL", lowered).Find(&categories).Error; err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+func (cr *CategoryRepository) GetBySlug(slug string) (*model.Category, error) {
+	var category model.Category
+	if err := cr.db.Where("slug = ? AND deleted_at IS NULL", slug).First(&category).Error; err != nil {
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+func (cr *CategoryRepository) SlugExists(slug, excludeId string) (bool, error) {
+	var count int64
+	base := cr.db.Model(&model.Category{}).Where("slug = ? AND deleted_at IS NULL", slug)
+
+	if excludeId != "" {
+		base.Where("id <> ?", excludeId)
+	}
 
-	if search != "" {
-		base.Where("name ILIKE ?", "%"+search+"%")
+	if err := base.Count(&count).Error; err != nil {
+		return false, err
 	}
 
-	if err := base.Find(&categories).Error; err != nil {
+	return count > 0, nil
+}
+
+func (cr *CategoryRepository) GetWithoutSlug(limit int) ([]*model.Category, error) {
+	var categories []*model.Category
+	if err := cr.db.Where("slug = '' AND deleted_at IS NULL").Order("created_at ASC, id ASC").Limit(limit).Find(&categories).Error; err != nil {
 		return nil, err
 	}
 
 	return categories, nil
 }
 
+func (cr *CategoryRepository) SetSlug(id, slug string) error {
+	return cr.db.Model(&model.Category{}).Where("id = ?", id).Update("slug", slug).Error
+}
+
+func (cr *CategoryRepository) Get(opts pagination.ListOptions) (*CategoryPage, error) {
+	if err := opts.Normalize(categorySortColumns, "created_at", "asc"); err != nil {
+		return nil, err
+	}
+
+	base := cr.db.Model(&model.Category{}).Where("deleted_at IS NULL")
+
+	if opts.Search != "" {
+		base.Where("name ILIKE ?", "%"+opts.Search+"%")
+	}
+
+	return cr.page(base, opts, categorySortColumns)
+}
+
 func (cr *CategoryRepository) Update(data *model.Category, id string) error {
+	updatedData := map[string]interface{}{
+		"name":      data.Name,
+		"slug":      data.Slug,
+		"parent_id": data.ParentID,
+	}
+
 	if err := cr.db.Model(&model.Category{}).
 		Where("id = ? AND deleted_at IS NULL", id).
-		Update("name", data.Name).Error; err != nil {
+		Updates(updatedData).Error; err != nil {
 		return err
 	}
 	return nil
@@ -74,3 +204,108 @@ func (cr *CategoryRepository) Delete(id string) error {
 	}
 	return nil
 }
+
+func (cr *CategoryRepository) GetDeleted(opts pagination.ListOptions) (*CategoryPage, error) {
+	if err := opts.Normalize(deletedCategorySortColumns, "deleted_at", "desc"); err != nil {
+		return nil, err
+	}
+
+	base := cr.db.Model(&model.Category{}).Where("deleted_at IS NOT NULL")
+
+	if opts.Search != "" {
+		base.Where("name ILIKE ?", "%"+opts.Search+"%")
+	}
+
+	return cr.page(base, opts, deletedCategorySortColumns)
+}
+
+func (cr *CategoryRepository) GetDeletedById(id string) (*model.Category, error) {
+	var category model.Category
+	if err := cr.db.Where("id = ? AND deleted_at IS NOT NULL", id).First(&category).Error; err != nil {
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+func (cr *CategoryRepository) Restore(data *model.Category, id string) error {
+	restoredData := map[string]interface{}{
+		"slug":       data.Slug,
+		"deleted_at": nil,
+	}
+
+	if err := cr.db.Model(&model.Category{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Updates(restoredData).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// Purgeable lists categories that have been in the trash since before the
+// given time, for the purge job to check against book-service.
+// Categories that still have subcategories are kept.
+func (cr *CategoryRepository) Purgeable(before time.Time, after string, limit int) ([]string, error) {
+	var ids []string
+	if err := cr.db.Model(&model.Category{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ? AND id::text > ? AND NOT EXISTS (SELECT 1 FROM categories children WHERE children.parent_id = categories.id::text)", before, after).
+		Order("id::text ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (cr *CategoryRepository) PurgeIds(before time.Time, ids []string) (int64, error) {
+	result := cr.db.Where("id IN ? AND deleted_at IS NOT NULL AND deleted_at < ? AND NOT EXISTS (SELECT 1 FROM categories children WHERE children.parent_id = categories.id::text)", ids, before).Delete(&model.Category{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+func (cr *CategoryRepository) page(base *gorm.DB, opts pagination.ListOptions, columns map[string]pagination.SortKind) (*CategoryPage, error) {
+	page := &CategoryPage{}
+
+	if opts.IncludeTotal {
+		if err := base.Session(&gorm.Session{}).Count(&page.TotalCount).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	query, err := pagination.Paginate(base.Session(&gorm.Session{}), opts, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := query.Find(&page.Categories).Error; err != nil {
+		return nil, err
+	}
+
+	if len(page.Categories) > opts.PageSize {
+		page.Categories = page.Categories[:opts.PageSize]
+		last := page.Categories[len(page.Categories)-1]
+		page.NextPageToken = pagination.EncodePageToken(opts, categorySortValue(last, opts.SortBy), last.ID)
+	}
+
+	return page, nil
+}
+
+func categorySortValue(data *model.Category, sortBy string) interface{} {
+	switch sortBy {
+	case "name":
+		return data.Name
+	case "updated_at":
+		return data.UpdatedAt
+	case "deleted_at":
+		if data.DeletedAt != nil {
+			return *data.DeletedAt
+		}
+		return nil
+	default:
+		return data.CreatedAt
+	}
+}
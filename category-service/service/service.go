@@ -2,26 +2,46 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/shafaalafghany/category-service/model"
 	"github.com/shafaalafghany/category-service/repository"
+	"gitlab.com/shafaalafghany/synapsis-common/auth"
+	"gitlab.com/shafaalafghany/synapsis-common/pagination"
 	"gitlab.com/shafaalafghany/synapsis-proto/go/category"
 	"gitlab.com/shafaalafghany/synapsis-proto/go/user"
 	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"gorm.io/gorm"
+)
+
+const (
+	roleAdmin    = "admin"
+	maxBatchSize = 100
 )
 
 type CategoryServiceInterface interface {
 	CreateCategory(context.Context, *category.Category) (*category.CommonCategoryResponse, error)
 	GetCategory(context.Context, *category.Category) (*category.Category, error)
+	GetCategoryBySlug(context.Context, *category.Category) (*category.Category, error)
+	GetCategoryAncestors(context.Context, *category.Category) (*category.CategoriesResponse, error)
 	GetCategories(context.Context, *category.CategoryRequest) (*category.CategoriesResponse, error)
+	BatchGetCategories(context.Context, *category.BatchGetCategoriesRequest) (*category.BatchGetCategoriesResponse, error)
+	ResolveCategories(context.Context, *category.ResolveCategoriesRequest) (*category.ResolveCategoriesResponse, error)
 	UpdateCategory(context.Context, *category.Category) (*category.CommonCategoryResponse, error)
 	DeleteCategory(context.Context, *category.Category) (*category.CommonCategoryResponse, error)
+	ListDeletedCategories(context.Context, *category.CategoryRequest) (*category.CategoriesResponse, error)
+	RestoreCategory(context.Context, *category.Category) (*category.CommonCategoryResponse, error)
+	BackfillSlugs() (int64, error)
 }
 
 type CategoryService struct {
@@ -39,6 +59,11 @@ func NewCategoryService(repo repository.CategoryRepositoryInterface, log *zap.Lo
 }
 
 func (cs *CategoryService) CreateCategory(ctx context.Context, body *category.Category) (*category.CommonCategoryResponse, error) {
+	name := strings.TrimSpace(body.GetName())
+	if name == "" {
+		return nil, status.Error(codes.InvalidArgument, "name cannot be empty")
+	}
+
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
 		return nil, status.Error(codes.Internal, "missing outgoing metadata")
@@ -50,20 +75,38 @@ func (cs *CategoryService) CreateCategory(ctx context.Context, body *category.Ca
 		return nil, status.Error(codes.Unauthenticated, "invalid user")
 	}
 
+	if err := cs.checkNameAvailable(name, ""); err != nil {
+		return nil, err
+	}
+
+	if err := cs.checkParent(body.GetParentId(), ""); err != nil {
+		return nil, err
+	}
+
+	slug, err := cs.uniqueSlug(name, "")
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
 	id := uuid.NewString()
 	data := &model.Category{
 		ID:        id,
-		Name:      body.GetName(),
+		Name:      name,
+		Slug:      slug,
+		ParentID:  body.GetParentId(),
 		CreatedBy: user.Id,
 	}
 
 	if err := cs.repo.Create(data); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, cs.duplicateError(name)
+		}
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	response := fmt.Sprintf("create new category %s successfully with id %v", body.GetName(), id)
+	response := fmt.Sprintf("create new category %s successfully with id %v", name, id)
 
-	return &category.CommonCategoryResponse{Message: response}, nil
+	return &category.CommonCategoryResponse{Message: response, Id: id}, nil
 }
 
 func (cs *CategoryService) GetCategory(ctx context.Context, body *category.Category) (*category.Category, error) {
@@ -88,6 +131,8 @@ func (cs *CategoryService) GetCategory(ctx context.Context, body *category.Categ
 	}
 
 	body.Name = data.Name
+	body.Slug = data.Slug
+	body.ParentId = proto.String(data.ParentID)
 	body.CreatedBy = data.CreatedBy
 	body.CreatedAt = data.CreatedAt.String()
 	body.UpdatedAt = data.UpdatedAt.String()
@@ -95,7 +140,11 @@ func (cs *CategoryService) GetCategory(ctx context.Context, body *category.Categ
 	return body, nil
 }
 
-func (cs *CategoryService) GetCategories(ctx context.Context, body *category.CategoryRequest) (*category.CategoriesResponse, error) {
+func (cs *CategoryService) GetCategoryBySlug(ctx context.Context, body *category.Category) (*category.Category, error) {
+	if body.GetSlug() == "" {
+		return nil, status.Error(codes.InvalidArgument, "slug cannot be empty")
+	}
+
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
 		return nil, status.Error(codes.Internal, "missing outgoing metadata")
@@ -107,18 +156,241 @@ func (cs *CategoryService) GetCategories(ctx context.Context, body *category.Cat
 		return nil, status.Error(codes.Unauthenticated, "invalid user")
 	}
 
-	data, err := cs.repo.Get(body.GetSearch())
+	data, err := cs.repo.GetBySlug(body.GetSlug())
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Error(codes.NotFound, "category not found")
+		}
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
+	return &category.Category{
+		Id:        data.ID,
+		Name:      data.Name,
+		Slug:      data.Slug,
+		ParentId:  proto.String(data.ParentID),
+		CreatedBy: data.CreatedBy,
+		CreatedAt: data.CreatedAt.String(),
+		UpdatedAt: data.UpdatedAt.String(),
+	}, nil
+}
+
+// ResolveCategories matches names case-insensitively and optionally creates
+// the missing ones as top-level categories.
+func (cs *CategoryService) ResolveCategories(ctx context.Context, body *category.ResolveCategoriesRequest) (*category.ResolveCategoriesResponse, error) {
+	names, err := batchNames(body.GetNames())
+	if err != nil {
+		return nil, err
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Internal, "missing outgoing metadata")
+	}
+	outbondCtx := metadata.NewOutgoingContext(ctx, md)
+
+	user, err := cs.userService.GetUser(outbondCtx, &emptypb.Empty{})
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid user")
+	}
+
+	if len(names) == 0 {
+		return &category.ResolveCategoriesResponse{Categories: []*category.Category{}, MissingNames: []string{}}, nil
+	}
+
+	data, err := cs.repo.GetByNames(names)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	found := map[string]*model.Category{}
+	for _, v := range data {
+		found[strings.ToLower(v.Name)] = v
+	}
+
+	categories := []*category.Category{}
+	missing := []string{}
+	for _, name := range names {
+		v, ok := found[strings.ToLower(name)]
+		if !ok {
+			if !body.GetCreateMissing() {
+				missing = append(missing, name)
+				continue
+			}
+
+			if v, err = cs.createResolved(name, user.Id); err != nil {
+				return nil, err
+			}
+		}
+
+		categories = append(categories, &category.Category{
+			Id:        v.ID,
+			Name:      v.Name,
+			Slug:      v.Slug,
+			ParentId:  proto.String(v.ParentID),
+			CreatedBy: v.CreatedBy,
+			CreatedAt: v.CreatedAt.String(),
+			UpdatedAt: v.UpdatedAt.String(),
+		})
+	}
+
+	return &category.ResolveCategoriesResponse{
+		Categories:   categories,
+		MissingNames: missing,
+	}, nil
+}
+
+func (cs *CategoryService) createResolved(name, userId string) (*model.Category, error) {
+	slug, err := cs.uniqueSlug(name, "")
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	data := &model.Category{
+		ID:        uuid.NewString(),
+		Name:      name,
+		Slug:      slug,
+		CreatedBy: userId,
+	}
+	if err := cs.repo.Create(data); err != nil {
+		if !errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+
+		existing, err := cs.repo.GetByName(name)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		return existing, nil
+	}
+
+	return data, nil
+}
+
+func (cs *CategoryService) BatchGetCategories(ctx context.Context, body *category.BatchGetCategoriesRequest) (*category.BatchGetCategoriesResponse, error) {
+	ids, invalid, err := batchIds(body.GetIds())
+	if err != nil {
+		return nil, err
+	}
+
+	// book-service refreshes the category names it keeps on books with a
+	// service token, which has no user behind it.
+	if !auth.IsService(ctx, os.Getenv("SECRET_KEY")) {
+		md, ok := metadata.FromIncomingContext(ctx)
+		if !ok {
+			return nil, status.Error(codes.Internal, "missing outgoing metadata")
+		}
+		outbondCtx := metadata.NewOutgoingContext(ctx, md)
+
+		_, err = cs.userService.GetUser(outbondCtx, &emptypb.Empty{})
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "invalid user")
+		}
+	}
+
+	if len(ids) == 0 {
+		return &category.BatchGetCategoriesResponse{Categories: []*category.Category{}, MissingIds: invalid}, nil
+	}
+
+	data, err := cs.repo.GetByIds(ids)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	found := map[string]*model.Category{}
+	for _, v := range data {
+		found[v.ID] = v
+	}
+
+	categories := []*category.Category{}
+	missing := invalid
+	for _, id := range ids {
+		v, ok := found[id]
+		if !ok {
+			missing = append(missing, id)
+			continue
+		}
+
+		categories = append(categories, &category.Category{
+			Id:        v.ID,
+			Name:      v.Name,
+			Slug:      v.Slug,
+			ParentId:  proto.String(v.ParentID),
+			CreatedBy: v.CreatedBy,
+			CreatedAt: v.CreatedAt.String(),
+			UpdatedAt: v.UpdatedAt.String(),
+		})
+	}
+
+	return &category.BatchGetCategoriesResponse{
+		Categories: categories,
+		MissingIds: missing,
+	}, nil
+}
+
+func (cs *CategoryService) GetCategoryAncestors(ctx context.Context, body *category.Category) (*category.CategoriesResponse, error) {
+	if body.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id cannot be empty")
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Internal, "missing outgoing metadata")
+	}
+	outbondCtx := metadata.NewOutgoingContext(ctx, md)
+
+	_, err := cs.userService.GetUser(outbondCtx, &emptypb.Empty{})
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid user")
+	}
+
+	data, err := cs.repo.GetAncestors(body.GetId())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	categories := []*category.Category{}
+	for _, v := range data {
+		categories = append(categories, &category.Category{
+			Id:        v.ID,
+			Name:      v.Name,
+			Slug:      v.Slug,
+			ParentId:  proto.String(v.ParentID),
+			CreatedBy: v.CreatedBy,
+			CreatedAt: v.CreatedAt.String(),
+			UpdatedAt: v.UpdatedAt.String(),
+		})
+	}
+
+	return &category.CategoriesResponse{Categories: categories}, nil
+}
+
+func (cs *CategoryService) GetCategories(ctx context.Context, body *category.CategoryRequest) (*category.CategoriesResponse, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Internal, "missing outgoing metadata")
+	}
+	outbondCtx := metadata.NewOutgoingContext(ctx, md)
+
+	_, err := cs.userService.GetUser(outbondCtx, &emptypb.Empty{})
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid user")
+	}
+
+	data, err := cs.repo.Get(listOptions(body))
+	if err != nil {
+		return nil, listError(err)
+	}
+
 	categories := []*category.Category{}
 
-	if len(data) > 0 {
-		for _, v := range data {
+	if len(data.Categories) > 0 {
+		for _, v := range data.Categories {
 			temp := &category.Category{
 				Id:        v.ID,
 				Name:      v.Name,
+				Slug:      v.Slug,
+				ParentId:  proto.String(v.ParentID),
 				CreatedBy: v.CreatedBy,
 				CreatedAt: v.CreatedAt.String(),
 				UpdatedAt: v.UpdatedAt.String(),
@@ -128,7 +400,11 @@ func (cs *CategoryService) GetCategories(ctx context.Context, body *category.Cat
 		}
 	}
 
-	return &category.CategoriesResponse{Categories: categories}, nil
+	return &category.CategoriesResponse{
+		Categories:    categories,
+		NextPageToken: data.NextPageToken,
+		TotalCount:    data.TotalCount,
+	}, nil
 }
 
 func (cs *CategoryService) UpdateCategory(ctx context.Context, body *category.Category) (*category.CommonCategoryResponse, error) {
@@ -147,13 +423,43 @@ func (cs *CategoryService) UpdateCategory(ctx context.Context, body *category.Ca
 		return nil, status.Error(codes.Unauthenticated, "invalid user")
 	}
 
-	_, err = cs.repo.GetById(body.Id)
+	existing, err := cs.repo.GetById(body.Id)
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	updateData := &model.Category{Name: body.GetName()}
+	name := strings.TrimSpace(body.GetName())
+	if name == "" {
+		return nil, status.Error(codes.InvalidArgument, "name cannot be empty")
+	}
+
+	if err := cs.checkNameAvailable(name, existing.ID); err != nil {
+		return nil, err
+	}
+
+	// parent_id is optional so that an update leaving it out keeps the
+	// current parent, while an empty value moves the category to the root.
+	parentId := existing.ParentID
+	if body.ParentId != nil {
+		parentId = body.GetParentId()
+		if err := cs.checkParent(parentId, existing.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	slug := existing.Slug
+	if name != existing.Name || slug == "" {
+		slug, err = cs.uniqueSlug(name, existing.ID)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	updateData := &model.Category{Name: name, Slug: slug, ParentID: parentId}
 	if err := cs.repo.Update(updateData, body.GetId()); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, cs.duplicateError(name)
+		}
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
@@ -187,3 +493,228 @@ func (cs *CategoryService) DeleteCategory(ctx context.Context, body *category.Ca
 
 	return &category.CommonCategoryResponse{Message: "delete category successfully"}, nil
 }
+
+func (cs *CategoryService) ListDeletedCategories(ctx context.Context, body *category.CategoryRequest) (*category.CategoriesResponse, error) {
+	if err := cs.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	data, err := cs.repo.GetDeleted(listOptions(body))
+	if err != nil {
+		return nil, listError(err)
+	}
+
+	categories := []*category.Category{}
+	for _, v := range data.Categories {
+		categories = append(categories, &category.Category{
+			Id:        v.ID,
+			Name:      v.Name,
+			Slug:      v.Slug,
+			ParentId:  proto.String(v.ParentID),
+			CreatedBy: v.CreatedBy,
+			CreatedAt: v.CreatedAt.String(),
+			UpdatedAt: v.UpdatedAt.String(),
+			DeletedAt: v.DeletedAt.String(),
+		})
+	}
+
+	return &category.CategoriesResponse{
+		Categories:    categories,
+		NextPageToken: data.NextPageToken,
+		TotalCount:    data.TotalCount,
+	}, nil
+}
+
+func (cs *CategoryService) RestoreCategory(ctx context.Context, body *category.Category) (*category.CommonCategoryResponse, error) {
+	if body.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id cannot be empty")
+	}
+
+	if err := cs.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	deleted, err := cs.repo.GetDeletedById(body.GetId())
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Error(codes.NotFound, "deleted category not found")
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if err := cs.checkNameAvailable(deleted.Name, deleted.ID); err != nil {
+		return nil, err
+	}
+
+	slug, err := cs.uniqueSlug(deleted.Name, deleted.ID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if err := cs.repo.Restore(&model.Category{Slug: slug}, deleted.ID); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, cs.duplicateError(deleted.Name)
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &category.CommonCategoryResponse{Message: "restore category successfully"}, nil
+}
+
+func (cs *CategoryService) requireAdmin(ctx context.Context) error {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return status.Error(codes.Internal, "missing outgoing metadata")
+	}
+	outbondCtx := metadata.NewOutgoingContext(ctx, md)
+
+	user, err := cs.userService.GetUser(outbondCtx, &emptypb.Empty{})
+	if err != nil {
+		return status.Error(codes.Unauthenticated, "invalid user")
+	}
+
+	if user.GetRole() != roleAdmin {
+		return status.Error(codes.PermissionDenied, "admin role required")
+	}
+
+	return nil
+}
+
+func (cs *CategoryService) checkNameAvailable(name, excludeId string) error {
+	existing, err := cs.repo.GetByName(name)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	if existing.ID == excludeId {
+		return nil
+	}
+
+	return alreadyExistsError(existing)
+}
+
+func (cs *CategoryService) checkParent(parentId, id string) error {
+	if parentId == "" {
+		return nil
+	}
+
+	if parentId == id {
+		return status.Error(codes.InvalidArgument, "category cannot be its own parent")
+	}
+
+	if _, err := cs.repo.GetById(parentId); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return status.Error(codes.InvalidArgument, "parent category not found")
+		}
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	if id == "" {
+		return nil
+	}
+
+	ancestors, err := cs.repo.GetAncestors(parentId)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	for _, v := range ancestors {
+		if v.ID == id {
+			return status.Error(codes.InvalidArgument, "parent category would create a cycle")
+		}
+	}
+
+	return nil
+}
+
+// duplicateError resolves the category that won a concurrent insert so the
+// caller still learns which id holds the name.
+func (cs *CategoryService) duplicateError(name string) error {
+	existing, err := cs.repo.GetByName(name)
+	if err != nil {
+		return status.Error(codes.AlreadyExists, "category already exists")
+	}
+
+	return alreadyExistsError(existing)
+}
+
+func alreadyExistsError(existing *model.Category) error {
+	st := status.New(codes.AlreadyExists, fmt.Sprintf("category %s already exists with id %s", existing.Name, existing.ID))
+	detailed, err := st.WithDetails(&errdetails.ResourceInfo{
+		ResourceType: "category",
+		ResourceName: existing.ID,
+		Description:  existing.Name,
+	})
+	if err != nil {
+		return st.Err()
+	}
+
+	return detailed.Err()
+}
+
+func batchNames(names []string) ([]string, error) {
+	seen := map[string]bool{}
+	result := []string{}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[strings.ToLower(name)] {
+			continue
+		}
+		seen[strings.ToLower(name)] = true
+		result = append(result, name)
+	}
+
+	if len(result) > maxBatchSize {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("at most %d names can be resolved at once", maxBatchSize))
+	}
+
+	return result, nil
+}
+
+// batchIds dedupes the requested ids and splits off the ones that are not
+// UUIDs, which cannot match a row and would fail the query on the uuid column.
+func batchIds(ids []string) ([]string, []string, error) {
+	seen := map[string]bool{}
+	result := []string{}
+	invalid := []string{}
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		if _, err := uuid.Parse(id); err != nil {
+			invalid = append(invalid, id)
+			continue
+		}
+		result = append(result, id)
+	}
+
+	if len(result)+len(invalid) > maxBatchSize {
+		return nil, nil, status.Error(codes.InvalidArgument, fmt.Sprintf("at most %d ids can be requested at once", maxBatchSize))
+	}
+
+	return result, invalid, nil
+}
+
+func listOptions(body *category.CategoryRequest) pagination.ListOptions {
+	return pagination.ListOptions{
+		Search:       body.GetSearch(),
+		PageSize:     int(body.GetPageSize()),
+		PageToken:    body.GetPageToken(),
+		SortBy:       body.GetSortBy(),
+		SortOrder:    body.GetSortOrder(),
+		IncludeTotal: body.GetIncludeTotal(),
+	}
+}
+
+func listError(err error) error {
+	if errors.Is(err, pagination.ErrInvalidPageToken) || errors.Is(err, pagination.ErrInvalidSort) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return status.Error(codes.Internal, err.Error())
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+const slugBackfillBatch = 500
+
+func slugify(name string) string {
+	var b strings.Builder
+	dash := false
+
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+
+		if !dash && b.Len() > 0 {
+			b.WriteRune('-')
+			dash = true
+		}
+	}
+
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+func (cs *CategoryService) uniqueSlug(name, excludeId string) (string, error) {
+	base := slugify(name)
+	if base == "" {
+		base = "category"
+	}
+
+	slug := base
+	for i := 2; ; i++ {
+		exists, err := cs.repo.SlugExists(slug, excludeId)
+		if err != nil {
+			return "", err
+		}
+
+		if !exists {
+			return slug, nil
+		}
+
+		slug = fmt.Sprintf("%s-%d", base, i)
+	}
+}
+
+// BackfillSlugs gives a slug to every live category that has none, such as
+// categories created before slugs were introduced. Older categories are
+// handled first so they keep the unsuffixed slug.
+func (cs *CategoryService) BackfillSlugs() (int64, error) {
+	var count int64
+	for {
+		categories, err := cs.repo.GetWithoutSlug(slugBackfillBatch)
+		if err != nil {
+			return count, err
+		}
+		if len(categories) == 0 {
+			return count, nil
+		}
+
+		for _, v := range categories {
+			slug, err := cs.uniqueSlug(v.Name, v.ID)
+			if err != nil {
+				return count, err
+			}
+			if err := cs.repo.SetSlug(v.ID, slug); err != nil {
+				return count, err
+			}
+			count++
+		}
+	}
+}
@@ -0,0 +1,21 @@
+module gitlab.com/shafaalafghany/synapsis-common
+
+go 1.23.3
+
+require (
+	github.com/dgrijalva/jwt-go v3.2.0+incompatible
+	go.uber.org/zap v1.27.0
+	google.golang.org/grpc v1.68.0
+	gorm.io/gorm v1.25.12
+)
+
+require (
+	github.com/jinzhu/inflection v1.0.0 // indirect
+	github.com/jinzhu/now v1.1.5 // indirect
+	go.uber.org/multierr v1.10.0 // indirect
+	golang.org/x/net v0.29.0 // indirect
+	golang.org/x/sys v0.25.0 // indirect
+	golang.org/x/text v0.18.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240903143218-8af14fe29dc1 // indirect
+	google.golang.org/protobuf v1.35.2 // indirect
+)
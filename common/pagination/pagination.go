@@ -0,0 +1,166 @@
+// Package pagination implements the cursor pagination and sorting shared by
+// the list RPCs of every service.
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+var (
+	ErrInvalidPageToken = errors.New("invalid page token")
+	ErrInvalidSort      = errors.New("invalid sort field or order")
+)
+
+// ListOptions are the paging, sorting and search parameters of a list
+// request.
+type ListOptions struct {
+	Search       string
+	PageSize     int
+	PageToken    string
+	SortBy       string
+	SortOrder    string
+	IncludeTotal bool
+}
+
+// SortKind tells how a sort column's value is carried in a page token.
+type SortKind int
+
+const (
+	SortString SortKind = iota
+	SortTime
+	SortInt
+)
+
+type pageCursor struct {
+	SortBy    string          `json:"s"`
+	SortOrder string          `json:"o"`
+	Value     json.RawMessage `json:"v"`
+	ID        string          `json:"i"`
+}
+
+// Normalize applies the default page size, sort column and order, and checks
+// the sort against the columns a list allows.
+func (o *ListOptions) Normalize(columns map[string]SortKind, defaultSort, defaultOrder string) error {
+	if o.PageSize <= 0 {
+		o.PageSize = DefaultPageSize
+	}
+	if o.PageSize > MaxPageSize {
+		o.PageSize = MaxPageSize
+	}
+
+	if o.SortBy == "" {
+		o.SortBy = defaultSort
+	}
+	if _, ok := columns[o.SortBy]; !ok {
+		return ErrInvalidSort
+	}
+
+	o.SortOrder = strings.ToLower(o.SortOrder)
+	if o.SortOrder == "" {
+		o.SortOrder = defaultOrder
+	}
+	if o.SortOrder != "asc" && o.SortOrder != "desc" {
+		return ErrInvalidSort
+	}
+
+	return nil
+}
+
+// Paginate orders by the sort column with id as a tie breaker and, when a
+// page token is given, seeks past the last row of the previous page. One row
+// more than the page size is fetched so callers can tell whether a next page
+// exists.
+func Paginate(base *gorm.DB, opts ListOptions, columns map[string]SortKind) (*gorm.DB, error) {
+	op, dir := ">", "ASC"
+	if opts.SortOrder == "desc" {
+		op, dir = "<", "DESC"
+	}
+
+	if opts.PageToken != "" {
+		value, id, err := decodePageToken(opts, columns[opts.SortBy])
+		if err != nil {
+			return nil, err
+		}
+		base = base.Where(fmt.Sprintf("(%s, id) %s (?, ?)", opts.SortBy, op), value, id)
+	}
+
+	return base.Order(fmt.Sprintf("%s %s, id %s", opts.SortBy, dir, dir)).Limit(opts.PageSize + 1), nil
+}
+
+// EncodePageToken builds the token for the page after the row with the given
+// sort value and id.
+func EncodePageToken(opts ListOptions, value interface{}, id string) string {
+	if t, ok := value.(time.Time); ok {
+		value = t.UTC().Format(time.RFC3339Nano)
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+
+	token, err := json.Marshal(pageCursor{
+		SortBy:    opts.SortBy,
+		SortOrder: opts.SortOrder,
+		Value:     raw,
+		ID:        id,
+	})
+	if err != nil {
+		return ""
+	}
+
+	return base64.RawURLEncoding.EncodeToString(token)
+}
+
+func decodePageToken(opts ListOptions, kind SortKind) (interface{}, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(opts.PageToken)
+	if err != nil {
+		return nil, "", ErrInvalidPageToken
+	}
+
+	var cursor pageCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil {
+		return nil, "", ErrInvalidPageToken
+	}
+
+	if cursor.SortBy != opts.SortBy || cursor.SortOrder != opts.SortOrder || cursor.ID == "" {
+		return nil, "", ErrInvalidPageToken
+	}
+
+	switch kind {
+	case SortTime:
+		var value string
+		if err := json.Unmarshal(cursor.Value, &value); err != nil {
+			return nil, "", ErrInvalidPageToken
+		}
+		t, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return nil, "", ErrInvalidPageToken
+		}
+		return t, cursor.ID, nil
+	case SortInt:
+		var value int64
+		if err := json.Unmarshal(cursor.Value, &value); err != nil {
+			return nil, "", ErrInvalidPageToken
+		}
+		return value, cursor.ID, nil
+	default:
+		var value string
+		if err := json.Unmarshal(cursor.Value, &value); err != nil {
+			return nil, "", ErrInvalidPageToken
+		}
+		return value, cursor.ID, nil
+	}
+}
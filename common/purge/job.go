@@ -0,0 +1,63 @@
+// Package purge runs the retention job that hard-deletes soft-deleted
+// records.
+package purge
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Purger hard-deletes the records soft-deleted before the given time and
+// reports how many were removed.
+type Purger interface {
+	Purge(before time.Time) (int64, error)
+}
+
+type Job struct {
+	purger    Purger
+	name      string
+	log       *zap.Logger
+	retention time.Duration
+	interval  time.Duration
+}
+
+// NewJob returns a job that purges records older than retention every
+// interval. name is the plural record name used in log messages.
+func NewJob(purger Purger, name string, log *zap.Logger, retention, interval time.Duration) *Job {
+	return &Job{
+		purger:    purger,
+		name:      name,
+		log:       log,
+		retention: retention,
+		interval:  interval,
+	}
+}
+
+func (j *Job) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.purge()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *Job) purge() {
+	count, err := j.purger.Purge(time.Now().Add(-j.retention))
+	if err != nil {
+		j.log.Error("failed to purge deleted "+j.name, zap.Error(err))
+		return
+	}
+
+	if count > 0 {
+		j.log.Info("purged deleted "+j.name, zap.Int64("count", count))
+	}
+}
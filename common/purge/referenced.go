@@ -0,0 +1,72 @@
+package purge
+
+import (
+	"slices"
+	"time"
+)
+
+const referenceBatch = 100
+
+// Store lists and hard-deletes soft-deleted records by id.
+type Store interface {
+	// Purgeable returns up to limit ids, in ascending order and greater than
+	// after, of records soft-deleted before the given time.
+	Purgeable(before time.Time, after string, limit int) ([]string, error)
+	// PurgeIds hard-deletes the given records if they are still deleted.
+	PurgeIds(before time.Time, ids []string) (int64, error)
+}
+
+// ReferencedFunc returns the ids among the given ones that other records,
+// possibly in another service, still refer to.
+type ReferencedFunc func(ids []string) ([]string, error)
+
+type referencedPurger struct {
+	store      Store
+	referenced ReferencedFunc
+}
+
+// SkipReferenced returns a Purger that leaves a record in the trash for as
+// long as referenced reports it in use, so purging never orphans the records
+// that point at it.
+func SkipReferenced(store Store, referenced ReferencedFunc) Purger {
+	return &referencedPurger{store: store, referenced: referenced}
+}
+
+func (p *referencedPurger) Purge(before time.Time) (int64, error) {
+	var total int64
+	after := ""
+	for {
+		ids, err := p.store.Purgeable(before, after, referenceBatch)
+		if err != nil {
+			return total, err
+		}
+		if len(ids) == 0 {
+			return total, nil
+		}
+		after = ids[len(ids)-1]
+
+		inUse, err := p.referenced(ids)
+		if err != nil {
+			return total, err
+		}
+
+		free := make([]string, 0, len(ids))
+		for _, id := range ids {
+			if !slices.Contains(inUse, id) {
+				free = append(free, id)
+			}
+		}
+
+		if len(free) > 0 {
+			count, err := p.store.PurgeIds(before, free)
+			if err != nil {
+				return total, err
+			}
+			total += count
+		}
+
+		if len(ids) < referenceBatch {
+			return total, nil
+		}
+	}
+}
@@ -0,0 +1,8 @@
+#!/bin/bash
+set -e
+
+psql -v ON_ERROR_STOP=1 --username "$POSTGRES_USER" --dbname "$POSTGRES_DB" <<-EOSQL
+    CREATE DATABASE publisher_master;
+    CREATE USER youruser WITH ENCRYPTED PASSWORD 'yourpassword';
+    GRANT ALL PRIVILEGES ON DATABASE publisher_master TO youruser;
+EOSQL
@@ -0,0 +1,405 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v4.23.4
+// source: author.proto
+
+package author
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	AuthorService_Create_FullMethodName      = "/author.AuthorService/Create"
+	AuthorService_Get_FullMethodName         = "/author.AuthorService/Get"
+	AuthorService_GetList_FullMethodName     = "/author.AuthorService/GetList"
+	AuthorService_Update_FullMethodName      = "/author.AuthorService/Update"
+	AuthorService_Delete_FullMethodName      = "/author.AuthorService/Delete"
+	AuthorService_ListDeleted_FullMethodName = "/author.AuthorService/ListDeleted"
+	AuthorService_Restore_FullMethodName     = "/author.AuthorService/Restore"
+	AuthorService_BatchGet_FullMethodName    = "/author.AuthorService/BatchGet"
+	AuthorService_Resolve_FullMethodName     = "/author.AuthorService/Resolve"
+)
+
+// AuthorServiceClient is the client API for AuthorService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AuthorServiceClient interface {
+	Create(ctx context.Context, in *Author, opts ...grpc.CallOption) (*CommonAuthorResponse, error)
+	Get(ctx context.Context, in *Author, opts ...grpc.CallOption) (*Author, error)
+	GetList(ctx context.Context, in *AuthorRequest, opts ...grpc.CallOption) (*AuthorsResponse, error)
+	Update(ctx context.Context, in *Author, opts ...grpc.CallOption) (*CommonAuthorResponse, error)
+	Delete(ctx context.Context, in *Author, opts ...grpc.CallOption) (*CommonAuthorResponse, error)
+	ListDeleted(ctx context.Context, in *AuthorRequest, opts ...grpc.CallOption) (*AuthorsResponse, error)
+	Restore(ctx context.Context, in *Author, opts ...grpc.CallOption) (*CommonAuthorResponse, error)
+	BatchGet(ctx context.Context, in *BatchGetAuthorsRequest, opts ...grpc.CallOption) (*BatchGetAuthorsResponse, error)
+	Resolve(ctx context.Context, in *ResolveAuthorsRequest, opts ...grpc.CallOption) (*ResolveAuthorsResponse, error)
+}
+
+type authorServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAuthorServiceClient(cc grpc.ClientConnInterface) AuthorServiceClient {
+	return &authorServiceClient{cc}
+}
+
+func (c *authorServiceClient) Create(ctx context.Context, in *Author, opts ...grpc.CallOption) (*CommonAuthorResponse, error) {
+	out := new(CommonAuthorResponse)
+	err := c.cc.Invoke(ctx, AuthorService_Create_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authorServiceClient) Get(ctx context.Context, in *Author, opts ...grpc.CallOption) (*Author, error) {
+	out := new(Author)
+	err := c.cc.Invoke(ctx, AuthorService_Get_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authorServiceClient) GetList(ctx context.Context, in *AuthorRequest, opts ...grpc.CallOption) (*AuthorsResponse, error) {
+	out := new(AuthorsResponse)
+	err := c.cc.Invoke(ctx, AuthorService_GetList_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authorServiceClient) Update(ctx context.Context, in *Author, opts ...grpc.CallOption) (*CommonAuthorResponse, error) {
+	out := new(CommonAuthorResponse)
+	err := c.cc.Invoke(ctx, AuthorService_Update_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authorServiceClient) Delete(ctx context.Context, in *Author, opts ...grpc.CallOption) (*CommonAuthorResponse, error) {
+	out := new(CommonAuthorResponse)
+	err := c.cc.Invoke(ctx, AuthorService_Delete_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authorServiceClient) ListDeleted(ctx context.Context, in *AuthorRequest, opts ...grpc.CallOption) (*AuthorsResponse, error) {
+	out := new(AuthorsResponse)
+	err := c.cc.Invoke(ctx, AuthorService_ListDeleted_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authorServiceClient) Restore(ctx context.Context, in *Author, opts ...grpc.CallOption) (*CommonAuthorResponse, error) {
+	out := new(CommonAuthorResponse)
+	err := c.cc.Invoke(ctx, AuthorService_Restore_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authorServiceClient) BatchGet(ctx context.Context, in *BatchGetAuthorsRequest, opts ...grpc.CallOption) (*BatchGetAuthorsResponse, error) {
+	out := new(BatchGetAuthorsResponse)
+	err := c.cc.Invoke(ctx, AuthorService_BatchGet_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authorServiceClient) Resolve(ctx context.Context, in *ResolveAuthorsRequest, opts ...grpc.CallOption) (*ResolveAuthorsResponse, error) {
+	out := new(ResolveAuthorsResponse)
+	err := c.cc.Invoke(ctx, AuthorService_Resolve_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AuthorServiceServer is the server API for AuthorService service.
+// All implementations must embed UnimplementedAuthorServiceServer
+// for forward compatibility
+type AuthorServiceServer interface {
+	Create(context.Context, *Author) (*CommonAuthorResponse, error)
+	Get(context.Context, *Author) (*Author, error)
+	GetList(context.Context, *AuthorRequest) (*AuthorsResponse, error)
+	Update(context.Context, *Author) (*CommonAuthorResponse, error)
+	Delete(context.Context, *Author) (*CommonAuthorResponse, error)
+	ListDeleted(context.Context, *AuthorRequest) (*AuthorsResponse, error)
+	Restore(context.Context, *Author) (*CommonAuthorResponse, error)
+	BatchGet(context.Context, *BatchGetAuthorsRequest) (*BatchGetAuthorsResponse, error)
+	Resolve(context.Context, *ResolveAuthorsRequest) (*ResolveAuthorsResponse, error)
+	mustEmbedUnimplementedAuthorServiceServer()
+}
+
+// UnimplementedAuthorServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedAuthorServiceServer struct {
+}
+
+func (UnimplementedAuthorServiceServer) Create(context.Context, *Author) (*CommonAuthorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Create not implemented")
+}
+func (UnimplementedAuthorServiceServer) Get(context.Context, *Author) (*Author, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
+}
+func (UnimplementedAuthorServiceServer) GetList(context.Context, *AuthorRequest) (*AuthorsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetList not implemented")
+}
+func (UnimplementedAuthorServiceServer) Update(context.Context, *Author) (*CommonAuthorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Update not implemented")
+}
+func (UnimplementedAuthorServiceServer) Delete(context.Context, *Author) (*CommonAuthorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
+func (UnimplementedAuthorServiceServer) ListDeleted(context.Context, *AuthorRequest) (*AuthorsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDeleted not implemented")
+}
+func (UnimplementedAuthorServiceServer) Restore(context.Context, *Author) (*CommonAuthorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Restore not implemented")
+}
+func (UnimplementedAuthorServiceServer) BatchGet(context.Context, *BatchGetAuthorsRequest) (*BatchGetAuthorsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchGet not implemented")
+}
+func (UnimplementedAuthorServiceServer) Resolve(context.Context, *ResolveAuthorsRequest) (*ResolveAuthorsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Resolve not implemented")
+}
+func (UnimplementedAuthorServiceServer) mustEmbedUnimplementedAuthorServiceServer() {}
+
+// UnsafeAuthorServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AuthorServiceServer will
+// result in compilation errors.
+type UnsafeAuthorServiceServer interface {
+	mustEmbedUnimplementedAuthorServiceServer()
+}
+
+func RegisterAuthorServiceServer(s grpc.ServiceRegistrar, srv AuthorServiceServer) {
+	s.RegisterService(&AuthorService_ServiceDesc, srv)
+}
+
+func _AuthorService_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Author)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthorServiceServer).Create(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthorService_Create_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthorServiceServer).Create(ctx, req.(*Author))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthorService_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Author)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthorServiceServer).Get(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthorService_Get_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthorServiceServer).Get(ctx, req.(*Author))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthorService_GetList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuthorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthorServiceServer).GetList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthorService_GetList_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthorServiceServer).GetList(ctx, req.(*AuthorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthorService_Update_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Author)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthorServiceServer).Update(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthorService_Update_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthorServiceServer).Update(ctx, req.(*Author))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthorService_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Author)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthorServiceServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthorService_Delete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthorServiceServer).Delete(ctx, req.(*Author))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthorService_ListDeleted_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuthorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthorServiceServer).ListDeleted(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthorService_ListDeleted_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthorServiceServer).ListDeleted(ctx, req.(*AuthorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthorService_Restore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Author)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthorServiceServer).Restore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthorService_Restore_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthorServiceServer).Restore(ctx, req.(*Author))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthorService_BatchGet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchGetAuthorsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthorServiceServer).BatchGet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthorService_BatchGet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthorServiceServer).BatchGet(ctx, req.(*BatchGetAuthorsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthorService_Resolve_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveAuthorsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthorServiceServer).Resolve(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthorService_Resolve_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthorServiceServer).Resolve(ctx, req.(*ResolveAuthorsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AuthorService_ServiceDesc is the grpc.ServiceDesc for AuthorService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AuthorService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "author.AuthorService",
+	HandlerType: (*AuthorServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Create",
+			Handler:    _AuthorService_Create_Handler,
+		},
+		{
+			MethodName: "Get",
+			Handler:    _AuthorService_Get_Handler,
+		},
+		{
+			MethodName: "GetList",
+			Handler:    _AuthorService_GetList_Handler,
+		},
+		{
+			MethodName: "Update",
+			Handler:    _AuthorService_Update_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _AuthorService_Delete_Handler,
+		},
+		{
+			MethodName: "ListDeleted",
+			Handler:    _AuthorService_ListDeleted_Handler,
+		},
+		{
+			MethodName: "Restore",
+			Handler:    _AuthorService_Restore_Handler,
+		},
+		{
+			MethodName: "BatchGet",
+			Handler:    _AuthorService_BatchGet_Handler,
+		},
+		{
+			MethodName: "Resolve",
+			Handler:    _AuthorService_Resolve_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "author.proto",
+}
@@ -0,0 +1,2563 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v4.23.4
+// source: book.proto
+
+package book
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	BookService_Create_FullMethodName            = "/book.BookService/Create"
+	BookService_Get_FullMethodName               = "/book.BookService/Get"
+	BookService_GetBookByISBN_FullMethodName     = "/book.BookService/GetBookByISBN"
+	BookService_Getlist_FullMethodName           = "/book.BookService/Getlist"
+	BookService_Update_FullMethodName            = "/book.BookService/Update"
+	BookService_Delete_FullMethodName            = "/book.BookService/Delete"
+	BookService_UploadCover_FullMethodName       = "/book.BookService/UploadCover"
+	BookService_DownloadCover_FullMethodName     = "/book.BookService/DownloadCover"
+	BookService_ListDeleted_FullMethodName       = "/book.BookService/ListDeleted"
+	BookService_Restore_FullMethodName           = "/book.BookService/Restore"
+	BookService_BatchGet_FullMethodName          = "/book.BookService/BatchGet"
+	BookService_ReferencedIds_FullMethodName     = "/book.BookService/ReferencedIds"
+	BookService_ImportBooks_FullMethodName       = "/book.BookService/ImportBooks"
+	BookService_ExportBooks_FullMethodName       = "/book.BookService/ExportBooks"
+	BookService_SearchBooks_FullMethodName       = "/book.BookService/SearchBooks"
+	BookService_GetRecommendation_FullMethodName = "/book.BookService/GetRecommendation"
+	BookService_GetTrending_FullMethodName       = "/book.BookService/GetTrending"
+	BookService_GetSimilarBooks_FullMethodName   = "/book.BookService/GetSimilarBooks"
+	BookService_BorrowBook_FullMethodName        = "/book.BookService/BorrowBook"
+	BookService_ReturnBook_FullMethodName        = "/book.BookService/ReturnBook"
+	BookService_GetDigitalAccess_FullMethodName  = "/book.BookService/GetDigitalAccess"
+	BookService_AddLicense_FullMethodName        = "/book.BookService/AddLicense"
+	BookService_ListLicenses_FullMethodName      = "/book.BookService/ListLicenses"
+	BookService_RevokeLicense_FullMethodName     = "/book.BookService/RevokeLicense"
+	BookService_CreateReview_FullMethodName      = "/book.BookService/CreateReview"
+	BookService_UpdateReview_FullMethodName      = "/book.BookService/UpdateReview"
+	BookService_DeleteReview_FullMethodName      = "/book.BookService/DeleteReview"
+	BookService_ListReviews_FullMethodName       = "/book.BookService/ListReviews"
+	BookService_ModerateReview_FullMethodName    = "/book.BookService/ModerateReview"
+	BookService_CreateList_FullMethodName        = "/book.BookService/CreateList"
+	BookService_UpdateList_FullMethodName        = "/book.BookService/UpdateList"
+	BookService_DeleteList_FullMethodName        = "/book.BookService/DeleteList"
+	BookService_GetList_FullMethodName           = "/book.BookService/GetList"
+	BookService_ListMyLists_FullMethodName       = "/book.BookService/ListMyLists"
+	BookService_AddToList_FullMethodName         = "/book.BookService/AddToList"
+	BookService_RemoveFromList_FullMethodName    = "/book.BookService/RemoveFromList"
+	BookService_ReorderList_FullMethodName       = "/book.BookService/ReorderList"
+	BookService_ListNotifications_FullMethodName = "/book.BookService/ListNotifications"
+	BookService_CreateWork_FullMethodName        = "/book.BookService/CreateWork"
+	BookService_UpdateWork_FullMethodName        = "/book.BookService/UpdateWork"
+	BookService_GetWork_FullMethodName           = "/book.BookService/GetWork"
+	BookService_ListEditions_FullMethodName      = "/book.BookService/ListEditions"
+	BookService_CreateSeries_FullMethodName      = "/book.BookService/CreateSeries"
+	BookService_UpdateSeries_FullMethodName      = "/book.BookService/UpdateSeries"
+	BookService_GetSeries_FullMethodName         = "/book.BookService/GetSeries"
+	BookService_ListSeriesBooks_FullMethodName   = "/book.BookService/ListSeriesBooks"
+	BookService_CreateBranch_FullMethodName      = "/book.BookService/CreateBranch"
+	BookService_UpdateBranch_FullMethodName      = "/book.BookService/UpdateBranch"
+	BookService_GetBranch_FullMethodName         = "/book.BookService/GetBranch"
+	BookService_ListBranches_FullMethodName      = "/book.BookService/ListBranches"
+	BookService_DeleteBranch_FullMethodName      = "/book.BookService/DeleteBranch"
+	BookService_PlaceHold_FullMethodName         = "/book.BookService/PlaceHold"
+	BookService_CancelHold_FullMethodName        = "/book.BookService/CancelHold"
+	BookService_ListHolds_FullMethodName         = "/book.BookService/ListHolds"
+	BookService_RequestTransfer_FullMethodName   = "/book.BookService/RequestTransfer"
+	BookService_DispatchTransfer_FullMethodName  = "/book.BookService/DispatchTransfer"
+	BookService_ReceiveTransfer_FullMethodName   = "/book.BookService/ReceiveTransfer"
+	BookService_CancelTransfer_FullMethodName    = "/book.BookService/CancelTransfer"
+	BookService_ListTransfers_FullMethodName     = "/book.BookService/ListTransfers"
+	BookService_LookupISBN_FullMethodName        = "/book.BookService/LookupISBN"
+	BookService_CreateDraft_FullMethodName       = "/book.BookService/CreateDraft"
+	BookService_ListDrafts_FullMethodName        = "/book.BookService/ListDrafts"
+	BookService_PublishDraft_FullMethodName      = "/book.BookService/PublishDraft"
+	BookService_DeleteDraft_FullMethodName       = "/book.BookService/DeleteDraft"
+)
+
+// BookServiceClient is the client API for BookService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type BookServiceClient interface {
+	Create(ctx context.Context, in *Book, opts ...grpc.CallOption) (*CommonBookResponse, error)
+	Get(ctx context.Context, in *Book, opts ...grpc.CallOption) (*Book, error)
+	GetBookByISBN(ctx context.Context, in *Book, opts ...grpc.CallOption) (*Book, error)
+	Getlist(ctx context.Context, in *BookRequest, opts ...grpc.CallOption) (*BooksResponse, error)
+	Update(ctx context.Context, in *Book, opts ...grpc.CallOption) (*CommonBookResponse, error)
+	Delete(ctx context.Context, in *Book, opts ...grpc.CallOption) (*CommonBookResponse, error)
+	UploadCover(ctx context.Context, opts ...grpc.CallOption) (BookService_UploadCoverClient, error)
+	DownloadCover(ctx context.Context, in *CoverRequest, opts ...grpc.CallOption) (BookService_DownloadCoverClient, error)
+	ListDeleted(ctx context.Context, in *BookRequest, opts ...grpc.CallOption) (*BooksResponse, error)
+	Restore(ctx context.Context, in *Book, opts ...grpc.CallOption) (*CommonBookResponse, error)
+	BatchGet(ctx context.Context, in *BatchGetBooksRequest, opts ...grpc.CallOption) (*BatchGetBooksResponse, error)
+	ReferencedIds(ctx context.Context, in *ReferencedIdsRequest, opts ...grpc.CallOption) (*ReferencedIdsResponse, error)
+	ImportBooks(ctx context.Context, opts ...grpc.CallOption) (BookService_ImportBooksClient, error)
+	ExportBooks(ctx context.Context, in *ExportBooksRequest, opts ...grpc.CallOption) (BookService_ExportBooksClient, error)
+	SearchBooks(ctx context.Context, in *SearchBooksRequest, opts ...grpc.CallOption) (*SearchBooksResponse, error)
+	GetRecommendation(ctx context.Context, in *BookRequest, opts ...grpc.CallOption) (*BooksResponse, error)
+	GetTrending(ctx context.Context, in *TrendingRequest, opts ...grpc.CallOption) (*TrendingResponse, error)
+	GetSimilarBooks(ctx context.Context, in *SimilarBooksRequest, opts ...grpc.CallOption) (*SimilarBooksResponse, error)
+	BorrowBook(ctx context.Context, in *BorrowRecord, opts ...grpc.CallOption) (*CommonBorrowRecordResponse, error)
+	ReturnBook(ctx context.Context, in *BorrowRecord, opts ...grpc.CallOption) (*CommonBorrowRecordResponse, error)
+	GetDigitalAccess(ctx context.Context, in *DigitalAccessRequest, opts ...grpc.CallOption) (*DigitalAccess, error)
+	AddLicense(ctx context.Context, in *DigitalLicense, opts ...grpc.CallOption) (*CommonBookResponse, error)
+	ListLicenses(ctx context.Context, in *Book, opts ...grpc.CallOption) (*LicensesResponse, error)
+	RevokeLicense(ctx context.Context, in *DigitalLicense, opts ...grpc.CallOption) (*CommonBookResponse, error)
+	CreateReview(ctx context.Context, in *Review, opts ...grpc.CallOption) (*CommonReviewResponse, error)
+	UpdateReview(ctx context.Context, in *Review, opts ...grpc.CallOption) (*CommonReviewResponse, error)
+	DeleteReview(ctx context.Context, in *Review, opts ...grpc.CallOption) (*CommonReviewResponse, error)
+	ListReviews(ctx context.Context, in *ReviewRequest, opts ...grpc.CallOption) (*ReviewsResponse, error)
+	ModerateReview(ctx context.Context, in *ModerateReviewRequest, opts ...grpc.CallOption) (*CommonReviewResponse, error)
+	CreateList(ctx context.Context, in *ReadingList, opts ...grpc.CallOption) (*CommonListResponse, error)
+	UpdateList(ctx context.Context, in *ReadingList, opts ...grpc.CallOption) (*CommonListResponse, error)
+	DeleteList(ctx context.Context, in *ReadingList, opts ...grpc.CallOption) (*CommonListResponse, error)
+	GetList(ctx context.Context, in *ReadingList, opts ...grpc.CallOption) (*ReadingList, error)
+	ListMyLists(ctx context.Context, in *ReadingListRequest, opts ...grpc.CallOption) (*ReadingListsResponse, error)
+	AddToList(ctx context.Context, in *ReadingListItem, opts ...grpc.CallOption) (*CommonListResponse, error)
+	RemoveFromList(ctx context.Context, in *ReadingListItem, opts ...grpc.CallOption) (*CommonListResponse, error)
+	ReorderList(ctx context.Context, in *ReorderListRequest, opts ...grpc.CallOption) (*CommonListResponse, error)
+	ListNotifications(ctx context.Context, in *NotificationRequest, opts ...grpc.CallOption) (*NotificationsResponse, error)
+	CreateWork(ctx context.Context, in *Work, opts ...grpc.CallOption) (*CommonWorkResponse, error)
+	UpdateWork(ctx context.Context, in *Work, opts ...grpc.CallOption) (*CommonWorkResponse, error)
+	GetWork(ctx context.Context, in *Work, opts ...grpc.CallOption) (*Work, error)
+	ListEditions(ctx context.Context, in *EditionsRequest, opts ...grpc.CallOption) (*BooksResponse, error)
+	CreateSeries(ctx context.Context, in *Series, opts ...grpc.CallOption) (*CommonWorkResponse, error)
+	UpdateSeries(ctx context.Context, in *Series, opts ...grpc.CallOption) (*CommonWorkResponse, error)
+	GetSeries(ctx context.Context, in *Series, opts ...grpc.CallOption) (*Series, error)
+	ListSeriesBooks(ctx context.Context, in *SeriesBooksRequest, opts ...grpc.CallOption) (*BooksResponse, error)
+	CreateBranch(ctx context.Context, in *Branch, opts ...grpc.CallOption) (*CommonBranchResponse, error)
+	UpdateBranch(ctx context.Context, in *Branch, opts ...grpc.CallOption) (*CommonBranchResponse, error)
+	GetBranch(ctx context.Context, in *Branch, opts ...grpc.CallOption) (*Branch, error)
+	ListBranches(ctx context.Context, in *BranchRequest, opts ...grpc.CallOption) (*BranchesResponse, error)
+	DeleteBranch(ctx context.Context, in *Branch, opts ...grpc.CallOption) (*CommonBranchResponse, error)
+	PlaceHold(ctx context.Context, in *Hold, opts ...grpc.CallOption) (*CommonHoldResponse, error)
+	CancelHold(ctx context.Context, in *Hold, opts ...grpc.CallOption) (*CommonHoldResponse, error)
+	ListHolds(ctx context.Context, in *HoldRequest, opts ...grpc.CallOption) (*HoldsResponse, error)
+	RequestTransfer(ctx context.Context, in *Transfer, opts ...grpc.CallOption) (*CommonTransferResponse, error)
+	DispatchTransfer(ctx context.Context, in *Transfer, opts ...grpc.CallOption) (*CommonTransferResponse, error)
+	ReceiveTransfer(ctx context.Context, in *Transfer, opts ...grpc.CallOption) (*CommonTransferResponse, error)
+	CancelTransfer(ctx context.Context, in *Transfer, opts ...grpc.CallOption) (*CommonTransferResponse, error)
+	ListTransfers(ctx context.Context, in *TransferRequest, opts ...grpc.CallOption) (*TransfersResponse, error)
+	LookupISBN(ctx context.Context, in *LookupISBNRequest, opts ...grpc.CallOption) (*ISBNLookupResponse, error)
+	CreateDraft(ctx context.Context, in *CreateDraftRequest, opts ...grpc.CallOption) (*BookDraft, error)
+	ListDrafts(ctx context.Context, in *BookRequest, opts ...grpc.CallOption) (*BookDraftsResponse, error)
+	PublishDraft(ctx context.Context, in *PublishDraftRequest, opts ...grpc.CallOption) (*CommonBookResponse, error)
+	DeleteDraft(ctx context.Context, in *BookDraft, opts ...grpc.CallOption) (*CommonBookResponse, error)
+}
+
+type bookServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBookServiceClient(cc grpc.ClientConnInterface) BookServiceClient {
+	return &bookServiceClient{cc}
+}
+
+func (c *bookServiceClient) Create(ctx context.Context, in *Book, opts ...grpc.CallOption) (*CommonBookResponse, error) {
+	out := new(CommonBookResponse)
+	err := c.cc.Invoke(ctx, BookService_Create_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) Get(ctx context.Context, in *Book, opts ...grpc.CallOption) (*Book, error) {
+	out := new(Book)
+	err := c.cc.Invoke(ctx, BookService_Get_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) GetBookByISBN(ctx context.Context, in *Book, opts ...grpc.CallOption) (*Book, error) {
+	out := new(Book)
+	err := c.cc.Invoke(ctx, BookService_GetBookByISBN_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) Getlist(ctx context.Context, in *BookRequest, opts ...grpc.CallOption) (*BooksResponse, error) {
+	out := new(BooksResponse)
+	err := c.cc.Invoke(ctx, BookService_Getlist_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) Update(ctx context.Context, in *Book, opts ...grpc.CallOption) (*CommonBookResponse, error) {
+	out := new(CommonBookResponse)
+	err := c.cc.Invoke(ctx, BookService_Update_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) Delete(ctx context.Context, in *Book, opts ...grpc.CallOption) (*CommonBookResponse, error) {
+	out := new(CommonBookResponse)
+	err := c.cc.Invoke(ctx, BookService_Delete_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) UploadCover(ctx context.Context, opts ...grpc.CallOption) (BookService_UploadCoverClient, error) {
+	stream, err := c.cc.NewStream(ctx, &BookService_ServiceDesc.Streams[0], BookService_UploadCover_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &bookServiceUploadCoverClient{stream}
+	return x, nil
+}
+
+type BookService_UploadCoverClient interface {
+	Send(*CoverChunk) error
+	CloseAndRecv() (*CoverResponse, error)
+	grpc.ClientStream
+}
+
+type bookServiceUploadCoverClient struct {
+	grpc.ClientStream
+}
+
+func (x *bookServiceUploadCoverClient) Send(m *CoverChunk) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *bookServiceUploadCoverClient) CloseAndRecv() (*CoverResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(CoverResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *bookServiceClient) DownloadCover(ctx context.Context, in *CoverRequest, opts ...grpc.CallOption) (BookService_DownloadCoverClient, error) {
+	stream, err := c.cc.NewStream(ctx, &BookService_ServiceDesc.Streams[1], BookService_DownloadCover_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &bookServiceDownloadCoverClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BookService_DownloadCoverClient interface {
+	Recv() (*CoverChunk, error)
+	grpc.ClientStream
+}
+
+type bookServiceDownloadCoverClient struct {
+	grpc.ClientStream
+}
+
+func (x *bookServiceDownloadCoverClient) Recv() (*CoverChunk, error) {
+	m := new(CoverChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *bookServiceClient) ListDeleted(ctx context.Context, in *BookRequest, opts ...grpc.CallOption) (*BooksResponse, error) {
+	out := new(BooksResponse)
+	err := c.cc.Invoke(ctx, BookService_ListDeleted_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) Restore(ctx context.Context, in *Book, opts ...grpc.CallOption) (*CommonBookResponse, error) {
+	out := new(CommonBookResponse)
+	err := c.cc.Invoke(ctx, BookService_Restore_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) BatchGet(ctx context.Context, in *BatchGetBooksRequest, opts ...grpc.CallOption) (*BatchGetBooksResponse, error) {
+	out := new(BatchGetBooksResponse)
+	err := c.cc.Invoke(ctx, BookService_BatchGet_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) ReferencedIds(ctx context.Context, in *ReferencedIdsRequest, opts ...grpc.CallOption) (*ReferencedIdsResponse, error) {
+	out := new(ReferencedIdsResponse)
+	err := c.cc.Invoke(ctx, BookService_ReferencedIds_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) ImportBooks(ctx context.Context, opts ...grpc.CallOption) (BookService_ImportBooksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &BookService_ServiceDesc.Streams[2], BookService_ImportBooks_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &bookServiceImportBooksClient{stream}
+	return x, nil
+}
+
+type BookService_ImportBooksClient interface {
+	Send(*ImportBooksRequest) error
+	CloseAndRecv() (*ImportBooksResponse, error)
+	grpc.ClientStream
+}
+
+type bookServiceImportBooksClient struct {
+	grpc.ClientStream
+}
+
+func (x *bookServiceImportBooksClient) Send(m *ImportBooksRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *bookServiceImportBooksClient) CloseAndRecv() (*ImportBooksResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(ImportBooksResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *bookServiceClient) ExportBooks(ctx context.Context, in *ExportBooksRequest, opts ...grpc.CallOption) (BookService_ExportBooksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &BookService_ServiceDesc.Streams[3], BookService_ExportBooks_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &bookServiceExportBooksClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BookService_ExportBooksClient interface {
+	Recv() (*ExportChunk, error)
+	grpc.ClientStream
+}
+
+type bookServiceExportBooksClient struct {
+	grpc.ClientStream
+}
+
+func (x *bookServiceExportBooksClient) Recv() (*ExportChunk, error) {
+	m := new(ExportChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *bookServiceClient) SearchBooks(ctx context.Context, in *SearchBooksRequest, opts ...grpc.CallOption) (*SearchBooksResponse, error) {
+	out := new(SearchBooksResponse)
+	err := c.cc.Invoke(ctx, BookService_SearchBooks_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) GetRecommendation(ctx context.Context, in *BookRequest, opts ...grpc.CallOption) (*BooksResponse, error) {
+	out := new(BooksResponse)
+	err := c.cc.Invoke(ctx, BookService_GetRecommendation_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) GetTrending(ctx context.Context, in *TrendingRequest, opts ...grpc.CallOption) (*TrendingResponse, error) {
+	out := new(TrendingResponse)
+	err := c.cc.Invoke(ctx, BookService_GetTrending_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) GetSimilarBooks(ctx context.Context, in *SimilarBooksRequest, opts ...grpc.CallOption) (*SimilarBooksResponse, error) {
+	out := new(SimilarBooksResponse)
+	err := c.cc.Invoke(ctx, BookService_GetSimilarBooks_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) BorrowBook(ctx context.Context, in *BorrowRecord, opts ...grpc.CallOption) (*CommonBorrowRecordResponse, error) {
+	out := new(CommonBorrowRecordResponse)
+	err := c.cc.Invoke(ctx, BookService_BorrowBook_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) ReturnBook(ctx context.Context, in *BorrowRecord, opts ...grpc.CallOption) (*CommonBorrowRecordResponse, error) {
+	out := new(CommonBorrowRecordResponse)
+	err := c.cc.Invoke(ctx, BookService_ReturnBook_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) GetDigitalAccess(ctx context.Context, in *DigitalAccessRequest, opts ...grpc.CallOption) (*DigitalAccess, error) {
+	out := new(DigitalAccess)
+	err := c.cc.Invoke(ctx, BookService_GetDigitalAccess_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) AddLicense(ctx context.Context, in *DigitalLicense, opts ...grpc.CallOption) (*CommonBookResponse, error) {
+	out := new(CommonBookResponse)
+	err := c.cc.Invoke(ctx, BookService_AddLicense_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) ListLicenses(ctx context.Context, in *Book, opts ...grpc.CallOption) (*LicensesResponse, error) {
+	out := new(LicensesResponse)
+	err := c.cc.Invoke(ctx, BookService_ListLicenses_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) RevokeLicense(ctx context.Context, in *DigitalLicense, opts ...grpc.CallOption) (*CommonBookResponse, error) {
+	out := new(CommonBookResponse)
+	err := c.cc.Invoke(ctx, BookService_RevokeLicense_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) CreateReview(ctx context.Context, in *Review, opts ...grpc.CallOption) (*CommonReviewResponse, error) {
+	out := new(CommonReviewResponse)
+	err := c.cc.Invoke(ctx, BookService_CreateReview_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) UpdateReview(ctx context.Context, in *Review, opts ...grpc.CallOption) (*CommonReviewResponse, error) {
+	out := new(CommonReviewResponse)
+	err := c.cc.Invoke(ctx, BookService_UpdateReview_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) DeleteReview(ctx context.Context, in *Review, opts ...grpc.CallOption) (*CommonReviewResponse, error) {
+	out := new(CommonReviewResponse)
+	err := c.cc.Invoke(ctx, BookService_DeleteReview_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) ListReviews(ctx context.Context, in *ReviewRequest, opts ...grpc.CallOption) (*ReviewsResponse, error) {
+	out := new(ReviewsResponse)
+	err := c.cc.Invoke(ctx, BookService_ListReviews_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) ModerateReview(ctx context.Context, in *ModerateReviewRequest, opts ...grpc.CallOption) (*CommonReviewResponse, error) {
+	out := new(CommonReviewResponse)
+	err := c.cc.Invoke(ctx, BookService_ModerateReview_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) CreateList(ctx context.Context, in *ReadingList, opts ...grpc.CallOption) (*CommonListResponse, error) {
+	out := new(CommonListResponse)
+	err := c.cc.Invoke(ctx, BookService_CreateList_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) UpdateList(ctx context.Context, in *ReadingList, opts ...grpc.CallOption) (*CommonListResponse, error) {
+	out := new(CommonListResponse)
+	err := c.cc.Invoke(ctx, BookService_UpdateList_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) DeleteList(ctx context.Context, in *ReadingList, opts ...grpc.CallOption) (*CommonListResponse, error) {
+	out := new(CommonListResponse)
+	err := c.cc.Invoke(ctx, BookService_DeleteList_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) GetList(ctx context.Context, in *ReadingList, opts ...grpc.CallOption) (*ReadingList, error) {
+	out := new(ReadingList)
+	err := c.cc.Invoke(ctx, BookService_GetList_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) ListMyLists(ctx context.Context, in *ReadingListRequest, opts ...grpc.CallOption) (*ReadingListsResponse, error) {
+	out := new(ReadingListsResponse)
+	err := c.cc.Invoke(ctx, BookService_ListMyLists_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) AddToList(ctx context.Context, in *ReadingListItem, opts ...grpc.CallOption) (*CommonListResponse, error) {
+	out := new(CommonListResponse)
+	err := c.cc.Invoke(ctx, BookService_AddToList_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) RemoveFromList(ctx context.Context, in *ReadingListItem, opts ...grpc.CallOption) (*CommonListResponse, error) {
+	out := new(CommonListResponse)
+	err := c.cc.Invoke(ctx, BookService_RemoveFromList_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) ReorderList(ctx context.Context, in *ReorderListRequest, opts ...grpc.CallOption) (*CommonListResponse, error) {
+	out := new(CommonListResponse)
+	err := c.cc.Invoke(ctx, BookService_ReorderList_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) ListNotifications(ctx context.Context, in *NotificationRequest, opts ...grpc.CallOption) (*NotificationsResponse, error) {
+	out := new(NotificationsResponse)
+	err := c.cc.Invoke(ctx, BookService_ListNotifications_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) CreateWork(ctx context.Context, in *Work, opts ...grpc.CallOption) (*CommonWorkResponse, error) {
+	out := new(CommonWorkResponse)
+	err := c.cc.Invoke(ctx, BookService_CreateWork_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) UpdateWork(ctx context.Context, in *Work, opts ...grpc.CallOption) (*CommonWorkResponse, error) {
+	out := new(CommonWorkResponse)
+	err := c.cc.Invoke(ctx, BookService_UpdateWork_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) GetWork(ctx context.Context, in *Work, opts ...grpc.CallOption) (*Work, error) {
+	out := new(Work)
+	err := c.cc.Invoke(ctx, BookService_GetWork_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) ListEditions(ctx context.Context, in *EditionsRequest, opts ...grpc.CallOption) (*BooksResponse, error) {
+	out := new(BooksResponse)
+	err := c.cc.Invoke(ctx, BookService_ListEditions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) CreateSeries(ctx context.Context, in *Series, opts ...grpc.CallOption) (*CommonWorkResponse, error) {
+	out := new(CommonWorkResponse)
+	err := c.cc.Invoke(ctx, BookService_CreateSeries_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) UpdateSeries(ctx context.Context, in *Series, opts ...grpc.CallOption) (*CommonWorkResponse, error) {
+	out := new(CommonWorkResponse)
+	err := c.cc.Invoke(ctx, BookService_UpdateSeries_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) GetSeries(ctx context.Context, in *Series, opts ...grpc.CallOption) (*Series, error) {
+	out := new(Series)
+	err := c.cc.Invoke(ctx, BookService_GetSeries_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) ListSeriesBooks(ctx context.Context, in *SeriesBooksRequest, opts ...grpc.CallOption) (*BooksResponse, error) {
+	out := new(BooksResponse)
+	err := c.cc.Invoke(ctx, BookService_ListSeriesBooks_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) CreateBranch(ctx context.Context, in *Branch, opts ...grpc.CallOption) (*CommonBranchResponse, error) {
+	out := new(CommonBranchResponse)
+	err := c.cc.Invoke(ctx, BookService_CreateBranch_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) UpdateBranch(ctx context.Context, in *Branch, opts ...grpc.CallOption) (*CommonBranchResponse, error) {
+	out := new(CommonBranchResponse)
+	err := c.cc.Invoke(ctx, BookService_UpdateBranch_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) GetBranch(ctx context.Context, in *Branch, opts ...grpc.CallOption) (*Branch, error) {
+	out := new(Branch)
+	err := c.cc.Invoke(ctx, BookService_GetBranch_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) ListBranches(ctx context.Context, in *BranchRequest, opts ...grpc.CallOption) (*BranchesResponse, error) {
+	out := new(BranchesResponse)
+	err := c.cc.Invoke(ctx, BookService_ListBranches_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) DeleteBranch(ctx context.Context, in *Branch, opts ...grpc.CallOption) (*CommonBranchResponse, error) {
+	out := new(CommonBranchResponse)
+	err := c.cc.Invoke(ctx, BookService_DeleteBranch_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) PlaceHold(ctx context.Context, in *Hold, opts ...grpc.CallOption) (*CommonHoldResponse, error) {
+	out := new(CommonHoldResponse)
+	err := c.cc.Invoke(ctx, BookService_PlaceHold_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) CancelHold(ctx context.Context, in *Hold, opts ...grpc.CallOption) (*CommonHoldResponse, error) {
+	out := new(CommonHoldResponse)
+	err := c.cc.Invoke(ctx, BookService_CancelHold_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) ListHolds(ctx context.Context, in *HoldRequest, opts ...grpc.CallOption) (*HoldsResponse, error) {
+	out := new(HoldsResponse)
+	err := c.cc.Invoke(ctx, BookService_ListHolds_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) RequestTransfer(ctx context.Context, in *Transfer, opts ...grpc.CallOption) (*CommonTransferResponse, error) {
+	out := new(CommonTransferResponse)
+	err := c.cc.Invoke(ctx, BookService_RequestTransfer_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) DispatchTransfer(ctx context.Context, in *Transfer, opts ...grpc.CallOption) (*CommonTransferResponse, error) {
+	out := new(CommonTransferResponse)
+	err := c.cc.Invoke(ctx, BookService_DispatchTransfer_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) ReceiveTransfer(ctx context.Context, in *Transfer, opts ...grpc.CallOption) (*CommonTransferResponse, error) {
+	out := new(CommonTransferResponse)
+	err := c.cc.Invoke(ctx, BookService_ReceiveTransfer_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) CancelTransfer(ctx context.Context, in *Transfer, opts ...grpc.CallOption) (*CommonTransferResponse, error) {
+	out := new(CommonTransferResponse)
+	err := c.cc.Invoke(ctx, BookService_CancelTransfer_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) ListTransfers(ctx context.Context, in *TransferRequest, opts ...grpc.CallOption) (*TransfersResponse, error) {
+	out := new(TransfersResponse)
+	err := c.cc.Invoke(ctx, BookService_ListTransfers_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) LookupISBN(ctx context.Context, in *LookupISBNRequest, opts ...grpc.CallOption) (*ISBNLookupResponse, error) {
+	out := new(ISBNLookupResponse)
+	err := c.cc.Invoke(ctx, BookService_LookupISBN_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) CreateDraft(ctx context.Context, in *CreateDraftRequest, opts ...grpc.CallOption) (*BookDraft, error) {
+	out := new(BookDraft)
+	err := c.cc.Invoke(ctx, BookService_CreateDraft_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) ListDrafts(ctx context.Context, in *BookRequest, opts ...grpc.CallOption) (*BookDraftsResponse, error) {
+	out := new(BookDraftsResponse)
+	err := c.cc.Invoke(ctx, BookService_ListDrafts_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) PublishDraft(ctx context.Context, in *PublishDraftRequest, opts ...grpc.CallOption) (*CommonBookResponse, error) {
+	out := new(CommonBookResponse)
+	err := c.cc.Invoke(ctx, BookService_PublishDraft_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookServiceClient) DeleteDraft(ctx context.Context, in *BookDraft, opts ...grpc.CallOption) (*CommonBookResponse, error) {
+	out := new(CommonBookResponse)
+	err := c.cc.Invoke(ctx, BookService_DeleteDraft_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BookServiceServer is the server API for BookService service.
+// All implementations must embed UnimplementedBookServiceServer
+// for forward compatibility
+type BookServiceServer interface {
+	Create(context.Context, *Book) (*CommonBookResponse, error)
+	Get(context.Context, *Book) (*Book, error)
+	GetBookByISBN(context.Context, *Book) (*Book, error)
+	Getlist(context.Context, *BookRequest) (*BooksResponse, error)
+	Update(context.Context, *Book) (*CommonBookResponse, error)
+	Delete(context.Context, *Book) (*CommonBookResponse, error)
+	UploadCover(BookService_UploadCoverServer) error
+	DownloadCover(*CoverRequest, BookService_DownloadCoverServer) error
+	ListDeleted(context.Context, *BookRequest) (*BooksResponse, error)
+	Restore(context.Context, *Book) (*CommonBookResponse, error)
+	BatchGet(context.Context, *BatchGetBooksRequest) (*BatchGetBooksResponse, error)
+	ReferencedIds(context.Context, *ReferencedIdsRequest) (*ReferencedIdsResponse, error)
+	ImportBooks(BookService_ImportBooksServer) error
+	ExportBooks(*ExportBooksRequest, BookService_ExportBooksServer) error
+	SearchBooks(context.Context, *SearchBooksRequest) (*SearchBooksResponse, error)
+	GetRecommendation(context.Context, *BookRequest) (*BooksResponse, error)
+	GetTrending(context.Context, *TrendingRequest) (*TrendingResponse, error)
+	GetSimilarBooks(context.Context, *SimilarBooksRequest) (*SimilarBooksResponse, error)
+	BorrowBook(context.Context, *BorrowRecord) (*CommonBorrowRecordResponse, error)
+	ReturnBook(context.Context, *BorrowRecord) (*CommonBorrowRecordResponse, error)
+	GetDigitalAccess(context.Context, *DigitalAccessRequest) (*DigitalAccess, error)
+	AddLicense(context.Context, *DigitalLicense) (*CommonBookResponse, error)
+	ListLicenses(context.Context, *Book) (*LicensesResponse, error)
+	RevokeLicense(context.Context, *DigitalLicense) (*CommonBookResponse, error)
+	CreateReview(context.Context, *Review) (*CommonReviewResponse, error)
+	UpdateReview(context.Context, *Review) (*CommonReviewResponse, error)
+	DeleteReview(context.Context, *Review) (*CommonReviewResponse, error)
+	ListReviews(context.Context, *ReviewRequest) (*ReviewsResponse, error)
+	ModerateReview(context.Context, *ModerateReviewRequest) (*CommonReviewResponse, error)
+	CreateList(context.Context, *ReadingList) (*CommonListResponse, error)
+	UpdateList(context.Context, *ReadingList) (*CommonListResponse, error)
+	DeleteList(context.Context, *ReadingList) (*CommonListResponse, error)
+	GetList(context.Context, *ReadingList) (*ReadingList, error)
+	ListMyLists(context.Context, *ReadingListRequest) (*ReadingListsResponse, error)
+	AddToList(context.Context, *ReadingListItem) (*CommonListResponse, error)
+	RemoveFromList(context.Context, *ReadingListItem) (*CommonListResponse, error)
+	ReorderList(context.Context, *ReorderListRequest) (*CommonListResponse, error)
+	ListNotifications(context.Context, *NotificationRequest) (*NotificationsResponse, error)
+	CreateWork(context.Context, *Work) (*CommonWorkResponse, error)
+	UpdateWork(context.Context, *Work) (*CommonWorkResponse, error)
+	GetWork(context.Context, *Work) (*Work, error)
+	ListEditions(context.Context, *EditionsRequest) (*BooksResponse, error)
+	CreateSeries(context.Context, *Series) (*CommonWorkResponse, error)
+	UpdateSeries(context.Context, *Series) (*CommonWorkResponse, error)
+	GetSeries(context.Context, *Series) (*Series, error)
+	ListSeriesBooks(context.Context, *SeriesBooksRequest) (*BooksResponse, error)
+	CreateBranch(context.Context, *Branch) (*CommonBranchResponse, error)
+	UpdateBranch(context.Context, *Branch) (*CommonBranchResponse, error)
+	GetBranch(context.Context, *Branch) (*Branch, error)
+	ListBranches(context.Context, *BranchRequest) (*BranchesResponse, error)
+	DeleteBranch(context.Context, *Branch) (*CommonBranchResponse, error)
+	PlaceHold(context.Context, *Hold) (*CommonHoldResponse, error)
+	CancelHold(context.Context, *Hold) (*CommonHoldResponse, error)
+	ListHolds(context.Context, *HoldRequest) (*HoldsResponse, error)
+	RequestTransfer(context.Context, *Transfer) (*CommonTransferResponse, error)
+	DispatchTransfer(context.Context, *Transfer) (*CommonTransferResponse, error)
+	ReceiveTransfer(context.Context, *Transfer) (*CommonTransferResponse, error)
+	CancelTransfer(context.Context, *Transfer) (*CommonTransferResponse, error)
+	ListTransfers(context.Context, *TransferRequest) (*TransfersResponse, error)
+	LookupISBN(context.Context, *LookupISBNRequest) (*ISBNLookupResponse, error)
+	CreateDraft(context.Context, *CreateDraftRequest) (*BookDraft, error)
+	ListDrafts(context.Context, *BookRequest) (*BookDraftsResponse, error)
+	PublishDraft(context.Context, *PublishDraftRequest) (*CommonBookResponse, error)
+	DeleteDraft(context.Context, *BookDraft) (*CommonBookResponse, error)
+	mustEmbedUnimplementedBookServiceServer()
+}
+
+// UnimplementedBookServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedBookServiceServer struct {
+}
+
+func (UnimplementedBookServiceServer) Create(context.Context, *Book) (*CommonBookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Create not implemented")
+}
+func (UnimplementedBookServiceServer) Get(context.Context, *Book) (*Book, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
+}
+func (UnimplementedBookServiceServer) GetBookByISBN(context.Context, *Book) (*Book, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBookByISBN not implemented")
+}
+func (UnimplementedBookServiceServer) Getlist(context.Context, *BookRequest) (*BooksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Getlist not implemented")
+}
+func (UnimplementedBookServiceServer) Update(context.Context, *Book) (*CommonBookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Update not implemented")
+}
+func (UnimplementedBookServiceServer) Delete(context.Context, *Book) (*CommonBookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
+func (UnimplementedBookServiceServer) UploadCover(BookService_UploadCoverServer) error {
+	return status.Errorf(codes.Unimplemented, "method UploadCover not implemented")
+}
+func (UnimplementedBookServiceServer) DownloadCover(*CoverRequest, BookService_DownloadCoverServer) error {
+	return status.Errorf(codes.Unimplemented, "method DownloadCover not implemented")
+}
+func (UnimplementedBookServiceServer) ListDeleted(context.Context, *BookRequest) (*BooksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDeleted not implemented")
+}
+func (UnimplementedBookServiceServer) Restore(context.Context, *Book) (*CommonBookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Restore not implemented")
+}
+func (UnimplementedBookServiceServer) BatchGet(context.Context, *BatchGetBooksRequest) (*BatchGetBooksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchGet not implemented")
+}
+func (UnimplementedBookServiceServer) ReferencedIds(context.Context, *ReferencedIdsRequest) (*ReferencedIdsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReferencedIds not implemented")
+}
+func (UnimplementedBookServiceServer) ImportBooks(BookService_ImportBooksServer) error {
+	return status.Errorf(codes.Unimplemented, "method ImportBooks not implemented")
+}
+func (UnimplementedBookServiceServer) ExportBooks(*ExportBooksRequest, BookService_ExportBooksServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExportBooks not implemented")
+}
+func (UnimplementedBookServiceServer) SearchBooks(context.Context, *SearchBooksRequest) (*SearchBooksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchBooks not implemented")
+}
+func (UnimplementedBookServiceServer) GetRecommendation(context.Context, *BookRequest) (*BooksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRecommendation not implemented")
+}
+func (UnimplementedBookServiceServer) GetTrending(context.Context, *TrendingRequest) (*TrendingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTrending not implemented")
+}
+func (UnimplementedBookServiceServer) GetSimilarBooks(context.Context, *SimilarBooksRequest) (*SimilarBooksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSimilarBooks not implemented")
+}
+func (UnimplementedBookServiceServer) BorrowBook(context.Context, *BorrowRecord) (*CommonBorrowRecordResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BorrowBook not implemented")
+}
+func (UnimplementedBookServiceServer) ReturnBook(context.Context, *BorrowRecord) (*CommonBorrowRecordResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReturnBook not implemented")
+}
+func (UnimplementedBookServiceServer) GetDigitalAccess(context.Context, *DigitalAccessRequest) (*DigitalAccess, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDigitalAccess not implemented")
+}
+func (UnimplementedBookServiceServer) AddLicense(context.Context, *DigitalLicense) (*CommonBookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddLicense not implemented")
+}
+func (UnimplementedBookServiceServer) ListLicenses(context.Context, *Book) (*LicensesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLicenses not implemented")
+}
+func (UnimplementedBookServiceServer) RevokeLicense(context.Context, *DigitalLicense) (*CommonBookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeLicense not implemented")
+}
+func (UnimplementedBookServiceServer) CreateReview(context.Context, *Review) (*CommonReviewResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateReview not implemented")
+}
+func (UnimplementedBookServiceServer) UpdateReview(context.Context, *Review) (*CommonReviewResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateReview not implemented")
+}
+func (UnimplementedBookServiceServer) DeleteReview(context.Context, *Review) (*CommonReviewResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteReview not implemented")
+}
+func (UnimplementedBookServiceServer) ListReviews(context.Context, *ReviewRequest) (*ReviewsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListReviews not implemented")
+}
+func (UnimplementedBookServiceServer) ModerateReview(context.Context, *ModerateReviewRequest) (*CommonReviewResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ModerateReview not implemented")
+}
+func (UnimplementedBookServiceServer) CreateList(context.Context, *ReadingList) (*CommonListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateList not implemented")
+}
+func (UnimplementedBookServiceServer) UpdateList(context.Context, *ReadingList) (*CommonListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateList not implemented")
+}
+func (UnimplementedBookServiceServer) DeleteList(context.Context, *ReadingList) (*CommonListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteList not implemented")
+}
+func (UnimplementedBookServiceServer) GetList(context.Context, *ReadingList) (*ReadingList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetList not implemented")
+}
+func (UnimplementedBookServiceServer) ListMyLists(context.Context, *ReadingListRequest) (*ReadingListsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMyLists not implemented")
+}
+func (UnimplementedBookServiceServer) AddToList(context.Context, *ReadingListItem) (*CommonListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddToList not implemented")
+}
+func (UnimplementedBookServiceServer) RemoveFromList(context.Context, *ReadingListItem) (*CommonListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveFromList not implemented")
+}
+func (UnimplementedBookServiceServer) ReorderList(context.Context, *ReorderListRequest) (*CommonListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReorderList not implemented")
+}
+func (UnimplementedBookServiceServer) ListNotifications(context.Context, *NotificationRequest) (*NotificationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListNotifications not implemented")
+}
+func (UnimplementedBookServiceServer) CreateWork(context.Context, *Work) (*CommonWorkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateWork not implemented")
+}
+func (UnimplementedBookServiceServer) UpdateWork(context.Context, *Work) (*CommonWorkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateWork not implemented")
+}
+func (UnimplementedBookServiceServer) GetWork(context.Context, *Work) (*Work, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWork not implemented")
+}
+func (UnimplementedBookServiceServer) ListEditions(context.Context, *EditionsRequest) (*BooksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListEditions not implemented")
+}
+func (UnimplementedBookServiceServer) CreateSeries(context.Context, *Series) (*CommonWorkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSeries not implemented")
+}
+func (UnimplementedBookServiceServer) UpdateSeries(context.Context, *Series) (*CommonWorkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateSeries not implemented")
+}
+func (UnimplementedBookServiceServer) GetSeries(context.Context, *Series) (*Series, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSeries not implemented")
+}
+func (UnimplementedBookServiceServer) ListSeriesBooks(context.Context, *SeriesBooksRequest) (*BooksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSeriesBooks not implemented")
+}
+func (UnimplementedBookServiceServer) CreateBranch(context.Context, *Branch) (*CommonBranchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateBranch not implemented")
+}
+func (UnimplementedBookServiceServer) UpdateBranch(context.Context, *Branch) (*CommonBranchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateBranch not implemented")
+}
+func (UnimplementedBookServiceServer) GetBranch(context.Context, *Branch) (*Branch, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBranch not implemented")
+}
+func (UnimplementedBookServiceServer) ListBranches(context.Context, *BranchRequest) (*BranchesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBranches not implemented")
+}
+func (UnimplementedBookServiceServer) DeleteBranch(context.Context, *Branch) (*CommonBranchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteBranch not implemented")
+}
+func (UnimplementedBookServiceServer) PlaceHold(context.Context, *Hold) (*CommonHoldResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PlaceHold not implemented")
+}
+func (UnimplementedBookServiceServer) CancelHold(context.Context, *Hold) (*CommonHoldResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelHold not implemented")
+}
+func (UnimplementedBookServiceServer) ListHolds(context.Context, *HoldRequest) (*HoldsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListHolds not implemented")
+}
+func (UnimplementedBookServiceServer) RequestTransfer(context.Context, *Transfer) (*CommonTransferResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestTransfer not implemented")
+}
+func (UnimplementedBookServiceServer) DispatchTransfer(context.Context, *Transfer) (*CommonTransferResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DispatchTransfer not implemented")
+}
+func (UnimplementedBookServiceServer) ReceiveTransfer(context.Context, *Transfer) (*CommonTransferResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReceiveTransfer not implemented")
+}
+func (UnimplementedBookServiceServer) CancelTransfer(context.Context, *Transfer) (*CommonTransferResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelTransfer not implemented")
+}
+func (UnimplementedBookServiceServer) ListTransfers(context.Context, *TransferRequest) (*TransfersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTransfers not implemented")
+}
+func (UnimplementedBookServiceServer) LookupISBN(context.Context, *LookupISBNRequest) (*ISBNLookupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LookupISBN not implemented")
+}
+func (UnimplementedBookServiceServer) CreateDraft(context.Context, *CreateDraftRequest) (*BookDraft, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateDraft not implemented")
+}
+func (UnimplementedBookServiceServer) ListDrafts(context.Context, *BookRequest) (*BookDraftsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDrafts not implemented")
+}
+func (UnimplementedBookServiceServer) PublishDraft(context.Context, *PublishDraftRequest) (*CommonBookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PublishDraft not implemented")
+}
+func (UnimplementedBookServiceServer) DeleteDraft(context.Context, *BookDraft) (*CommonBookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteDraft not implemented")
+}
+func (UnimplementedBookServiceServer) mustEmbedUnimplementedBookServiceServer() {}
+
+// UnsafeBookServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BookServiceServer will
+// result in compilation errors.
+type UnsafeBookServiceServer interface {
+	mustEmbedUnimplementedBookServiceServer()
+}
+
+func RegisterBookServiceServer(s grpc.ServiceRegistrar, srv BookServiceServer) {
+	s.RegisterService(&BookService_ServiceDesc, srv)
+}
+
+func _BookService_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Book)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).Create(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_Create_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).Create(ctx, req.(*Book))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Book)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).Get(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_Get_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).Get(ctx, req.(*Book))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_GetBookByISBN_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Book)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).GetBookByISBN(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_GetBookByISBN_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).GetBookByISBN(ctx, req.(*Book))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_Getlist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).Getlist(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_Getlist_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).Getlist(ctx, req.(*BookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_Update_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Book)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).Update(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_Update_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).Update(ctx, req.(*Book))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Book)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_Delete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).Delete(ctx, req.(*Book))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_UploadCover_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(BookServiceServer).UploadCover(&bookServiceUploadCoverServer{stream})
+}
+
+type BookService_UploadCoverServer interface {
+	SendAndClose(*CoverResponse) error
+	Recv() (*CoverChunk, error)
+	grpc.ServerStream
+}
+
+type bookServiceUploadCoverServer struct {
+	grpc.ServerStream
+}
+
+func (x *bookServiceUploadCoverServer) SendAndClose(m *CoverResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *bookServiceUploadCoverServer) Recv() (*CoverChunk, error) {
+	m := new(CoverChunk)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _BookService_DownloadCover_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(CoverRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BookServiceServer).DownloadCover(m, &bookServiceDownloadCoverServer{stream})
+}
+
+type BookService_DownloadCoverServer interface {
+	Send(*CoverChunk) error
+	grpc.ServerStream
+}
+
+type bookServiceDownloadCoverServer struct {
+	grpc.ServerStream
+}
+
+func (x *bookServiceDownloadCoverServer) Send(m *CoverChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BookService_ListDeleted_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).ListDeleted(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_ListDeleted_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).ListDeleted(ctx, req.(*BookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_Restore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Book)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).Restore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_Restore_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).Restore(ctx, req.(*Book))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_BatchGet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchGetBooksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).BatchGet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_BatchGet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).BatchGet(ctx, req.(*BatchGetBooksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_ReferencedIds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReferencedIdsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).ReferencedIds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_ReferencedIds_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).ReferencedIds(ctx, req.(*ReferencedIdsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_ImportBooks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(BookServiceServer).ImportBooks(&bookServiceImportBooksServer{stream})
+}
+
+type BookService_ImportBooksServer interface {
+	SendAndClose(*ImportBooksResponse) error
+	Recv() (*ImportBooksRequest, error)
+	grpc.ServerStream
+}
+
+type bookServiceImportBooksServer struct {
+	grpc.ServerStream
+}
+
+func (x *bookServiceImportBooksServer) SendAndClose(m *ImportBooksResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *bookServiceImportBooksServer) Recv() (*ImportBooksRequest, error) {
+	m := new(ImportBooksRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _BookService_ExportBooks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportBooksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BookServiceServer).ExportBooks(m, &bookServiceExportBooksServer{stream})
+}
+
+type BookService_ExportBooksServer interface {
+	Send(*ExportChunk) error
+	grpc.ServerStream
+}
+
+type bookServiceExportBooksServer struct {
+	grpc.ServerStream
+}
+
+func (x *bookServiceExportBooksServer) Send(m *ExportChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BookService_SearchBooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchBooksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).SearchBooks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_SearchBooks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).SearchBooks(ctx, req.(*SearchBooksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_GetRecommendation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).GetRecommendation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_GetRecommendation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).GetRecommendation(ctx, req.(*BookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_GetTrending_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TrendingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).GetTrending(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_GetTrending_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).GetTrending(ctx, req.(*TrendingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_GetSimilarBooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SimilarBooksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).GetSimilarBooks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_GetSimilarBooks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).GetSimilarBooks(ctx, req.(*SimilarBooksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_BorrowBook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BorrowRecord)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).BorrowBook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_BorrowBook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).BorrowBook(ctx, req.(*BorrowRecord))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_ReturnBook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BorrowRecord)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).ReturnBook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_ReturnBook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).ReturnBook(ctx, req.(*BorrowRecord))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_GetDigitalAccess_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DigitalAccessRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).GetDigitalAccess(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_GetDigitalAccess_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).GetDigitalAccess(ctx, req.(*DigitalAccessRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_AddLicense_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DigitalLicense)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).AddLicense(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_AddLicense_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).AddLicense(ctx, req.(*DigitalLicense))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_ListLicenses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Book)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).ListLicenses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_ListLicenses_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).ListLicenses(ctx, req.(*Book))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_RevokeLicense_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DigitalLicense)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).RevokeLicense(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_RevokeLicense_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).RevokeLicense(ctx, req.(*DigitalLicense))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_CreateReview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Review)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).CreateReview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_CreateReview_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).CreateReview(ctx, req.(*Review))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_UpdateReview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Review)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).UpdateReview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_UpdateReview_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).UpdateReview(ctx, req.(*Review))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_DeleteReview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Review)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).DeleteReview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_DeleteReview_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).DeleteReview(ctx, req.(*Review))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_ListReviews_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReviewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).ListReviews(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_ListReviews_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).ListReviews(ctx, req.(*ReviewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_ModerateReview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ModerateReviewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).ModerateReview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_ModerateReview_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).ModerateReview(ctx, req.(*ModerateReviewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_CreateList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadingList)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).CreateList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_CreateList_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).CreateList(ctx, req.(*ReadingList))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_UpdateList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadingList)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).UpdateList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_UpdateList_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).UpdateList(ctx, req.(*ReadingList))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_DeleteList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadingList)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).DeleteList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_DeleteList_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).DeleteList(ctx, req.(*ReadingList))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_GetList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadingList)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).GetList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_GetList_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).GetList(ctx, req.(*ReadingList))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_ListMyLists_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadingListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).ListMyLists(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_ListMyLists_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).ListMyLists(ctx, req.(*ReadingListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_AddToList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadingListItem)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).AddToList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_AddToList_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).AddToList(ctx, req.(*ReadingListItem))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_RemoveFromList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadingListItem)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).RemoveFromList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_RemoveFromList_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).RemoveFromList(ctx, req.(*ReadingListItem))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_ReorderList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReorderListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).ReorderList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_ReorderList_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).ReorderList(ctx, req.(*ReorderListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_ListNotifications_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NotificationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).ListNotifications(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_ListNotifications_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).ListNotifications(ctx, req.(*NotificationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_CreateWork_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Work)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).CreateWork(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_CreateWork_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).CreateWork(ctx, req.(*Work))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_UpdateWork_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Work)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).UpdateWork(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_UpdateWork_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).UpdateWork(ctx, req.(*Work))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_GetWork_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Work)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).GetWork(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_GetWork_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).GetWork(ctx, req.(*Work))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_ListEditions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EditionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).ListEditions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_ListEditions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).ListEditions(ctx, req.(*EditionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_CreateSeries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Series)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).CreateSeries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_CreateSeries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).CreateSeries(ctx, req.(*Series))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_UpdateSeries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Series)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).UpdateSeries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_UpdateSeries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).UpdateSeries(ctx, req.(*Series))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_GetSeries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Series)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).GetSeries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_GetSeries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).GetSeries(ctx, req.(*Series))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_ListSeriesBooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SeriesBooksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).ListSeriesBooks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_ListSeriesBooks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).ListSeriesBooks(ctx, req.(*SeriesBooksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_CreateBranch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Branch)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).CreateBranch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_CreateBranch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).CreateBranch(ctx, req.(*Branch))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_UpdateBranch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Branch)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).UpdateBranch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_UpdateBranch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).UpdateBranch(ctx, req.(*Branch))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_GetBranch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Branch)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).GetBranch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_GetBranch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).GetBranch(ctx, req.(*Branch))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_ListBranches_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BranchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).ListBranches(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_ListBranches_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).ListBranches(ctx, req.(*BranchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_DeleteBranch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Branch)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).DeleteBranch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_DeleteBranch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).DeleteBranch(ctx, req.(*Branch))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_PlaceHold_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Hold)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).PlaceHold(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_PlaceHold_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).PlaceHold(ctx, req.(*Hold))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_CancelHold_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Hold)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).CancelHold(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_CancelHold_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).CancelHold(ctx, req.(*Hold))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_ListHolds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HoldRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).ListHolds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_ListHolds_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).ListHolds(ctx, req.(*HoldRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_RequestTransfer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Transfer)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).RequestTransfer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_RequestTransfer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).RequestTransfer(ctx, req.(*Transfer))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_DispatchTransfer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Transfer)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).DispatchTransfer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_DispatchTransfer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).DispatchTransfer(ctx, req.(*Transfer))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_ReceiveTransfer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Transfer)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).ReceiveTransfer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_ReceiveTransfer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).ReceiveTransfer(ctx, req.(*Transfer))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_CancelTransfer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Transfer)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).CancelTransfer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_CancelTransfer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).CancelTransfer(ctx, req.(*Transfer))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_ListTransfers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).ListTransfers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_ListTransfers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).ListTransfers(ctx, req.(*TransferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_LookupISBN_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LookupISBNRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).LookupISBN(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_LookupISBN_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).LookupISBN(ctx, req.(*LookupISBNRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_CreateDraft_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateDraftRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).CreateDraft(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_CreateDraft_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).CreateDraft(ctx, req.(*CreateDraftRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_ListDrafts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).ListDrafts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_ListDrafts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).ListDrafts(ctx, req.(*BookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_PublishDraft_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PublishDraftRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).PublishDraft(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_PublishDraft_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).PublishDraft(ctx, req.(*PublishDraftRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookService_DeleteDraft_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BookDraft)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookServiceServer).DeleteDraft(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookService_DeleteDraft_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookServiceServer).DeleteDraft(ctx, req.(*BookDraft))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// BookService_ServiceDesc is the grpc.ServiceDesc for BookService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var BookService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "book.BookService",
+	HandlerType: (*BookServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Create",
+			Handler:    _BookService_Create_Handler,
+		},
+		{
+			MethodName: "Get",
+			Handler:    _BookService_Get_Handler,
+		},
+		{
+			MethodName: "GetBookByISBN",
+			Handler:    _BookService_GetBookByISBN_Handler,
+		},
+		{
+			MethodName: "Getlist",
+			Handler:    _BookService_Getlist_Handler,
+		},
+		{
+			MethodName: "Update",
+			Handler:    _BookService_Update_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _BookService_Delete_Handler,
+		},
+		{
+			MethodName: "ListDeleted",
+			Handler:    _BookService_ListDeleted_Handler,
+		},
+		{
+			MethodName: "Restore",
+			Handler:    _BookService_Restore_Handler,
+		},
+		{
+			MethodName: "BatchGet",
+			Handler:    _BookService_BatchGet_Handler,
+		},
+		{
+			MethodName: "ReferencedIds",
+			Handler:    _BookService_ReferencedIds_Handler,
+		},
+		{
+			MethodName: "SearchBooks",
+			Handler:    _BookService_SearchBooks_Handler,
+		},
+		{
+			MethodName: "GetRecommendation",
+			Handler:    _BookService_GetRecommendation_Handler,
+		},
+		{
+			MethodName: "GetTrending",
+			Handler:    _BookService_GetTrending_Handler,
+		},
+		{
+			MethodName: "GetSimilarBooks",
+			Handler:    _BookService_GetSimilarBooks_Handler,
+		},
+		{
+			MethodName: "BorrowBook",
+			Handler:    _BookService_BorrowBook_Handler,
+		},
+		{
+			MethodName: "ReturnBook",
+			Handler:    _BookService_ReturnBook_Handler,
+		},
+		{
+			MethodName: "GetDigitalAccess",
+			Handler:    _BookService_GetDigitalAccess_Handler,
+		},
+		{
+			MethodName: "AddLicense",
+			Handler:    _BookService_AddLicense_Handler,
+		},
+		{
+			MethodName: "ListLicenses",
+			Handler:    _BookService_ListLicenses_Handler,
+		},
+		{
+			MethodName: "RevokeLicense",
+			Handler:    _BookService_RevokeLicense_Handler,
+		},
+		{
+			MethodName: "CreateReview",
+			Handler:    _BookService_CreateReview_Handler,
+		},
+		{
+			MethodName: "UpdateReview",
+			Handler:    _BookService_UpdateReview_Handler,
+		},
+		{
+			MethodName: "DeleteReview",
+			Handler:    _BookService_DeleteReview_Handler,
+		},
+		{
+			MethodName: "ListReviews",
+			Handler:    _BookService_ListReviews_Handler,
+		},
+		{
+			MethodName: "ModerateReview",
+			Handler:    _BookService_ModerateReview_Handler,
+		},
+		{
+			MethodName: "CreateList",
+			Handler:    _BookService_CreateList_Handler,
+		},
+		{
+			MethodName: "UpdateList",
+			Handler:    _BookService_UpdateList_Handler,
+		},
+		{
+			MethodName: "DeleteList",
+			Handler:    _BookService_DeleteList_Handler,
+		},
+		{
+			MethodName: "GetList",
+			Handler:    _BookService_GetList_Handler,
+		},
+		{
+			MethodName: "ListMyLists",
+			Handler:    _BookService_ListMyLists_Handler,
+		},
+		{
+			MethodName: "AddToList",
+			Handler:    _BookService_AddToList_Handler,
+		},
+		{
+			MethodName: "RemoveFromList",
+			Handler:    _BookService_RemoveFromList_Handler,
+		},
+		{
+			MethodName: "ReorderList",
+			Handler:    _BookService_ReorderList_Handler,
+		},
+		{
+			MethodName: "ListNotifications",
+			Handler:    _BookService_ListNotifications_Handler,
+		},
+		{
+			MethodName: "CreateWork",
+			Handler:    _BookService_CreateWork_Handler,
+		},
+		{
+			MethodName: "UpdateWork",
+			Handler:    _BookService_UpdateWork_Handler,
+		},
+		{
+			MethodName: "GetWork",
+			Handler:    _BookService_GetWork_Handler,
+		},
+		{
+			MethodName: "ListEditions",
+			Handler:    _BookService_ListEditions_Handler,
+		},
+		{
+			MethodName: "CreateSeries",
+			Handler:    _BookService_CreateSeries_Handler,
+		},
+		{
+			MethodName: "UpdateSeries",
+			Handler:    _BookService_UpdateSeries_Handler,
+		},
+		{
+			MethodName: "GetSeries",
+			Handler:    _BookService_GetSeries_Handler,
+		},
+		{
+			MethodName: "ListSeriesBooks",
+			Handler:    _BookService_ListSeriesBooks_Handler,
+		},
+		{
+			MethodName: "CreateBranch",
+			Handler:    _BookService_CreateBranch_Handler,
+		},
+		{
+			MethodName: "UpdateBranch",
+			Handler:    _BookService_UpdateBranch_Handler,
+		},
+		{
+			MethodName: "GetBranch",
+			Handler:    _BookService_GetBranch_Handler,
+		},
+		{
+			MethodName: "ListBranches",
+			Handler:    _BookService_ListBranches_Handler,
+		},
+		{
+			MethodName: "DeleteBranch",
+			Handler:    _BookService_DeleteBranch_Handler,
+		},
+		{
+			MethodName: "PlaceHold",
+			Handler:    _BookService_PlaceHold_Handler,
+		},
+		{
+			MethodName: "CancelHold",
+			Handler:    _BookService_CancelHold_Handler,
+		},
+		{
+			MethodName: "ListHolds",
+			Handler:    _BookService_ListHolds_Handler,
+		},
+		{
+			MethodName: "RequestTransfer",
+			Handler:    _BookService_RequestTransfer_Handler,
+		},
+		{
+			MethodName: "DispatchTransfer",
+			Handler:    _BookService_DispatchTransfer_Handler,
+		},
+		{
+			MethodName: "ReceiveTransfer",
+			Handler:    _BookService_ReceiveTransfer_Handler,
+		},
+		{
+			MethodName: "CancelTransfer",
+			Handler:    _BookService_CancelTransfer_Handler,
+		},
+		{
+			MethodName: "ListTransfers",
+			Handler:    _BookService_ListTransfers_Handler,
+		},
+		{
+			MethodName: "LookupISBN",
+			Handler:    _BookService_LookupISBN_Handler,
+		},
+		{
+			MethodName: "CreateDraft",
+			Handler:    _BookService_CreateDraft_Handler,
+		},
+		{
+			MethodName: "ListDrafts",
+			Handler:    _BookService_ListDrafts_Handler,
+		},
+		{
+			MethodName: "PublishDraft",
+			Handler:    _BookService_PublishDraft_Handler,
+		},
+		{
+			MethodName: "DeleteDraft",
+			Handler:    _BookService_DeleteDraft_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "UploadCover",
+			Handler:       _BookService_UploadCover_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "DownloadCover",
+			Handler:       _BookService_DownloadCover_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ImportBooks",
+			Handler:       _BookService_ImportBooks_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "ExportBooks",
+			Handler:       _BookService_ExportBooks_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "book.proto",
+}
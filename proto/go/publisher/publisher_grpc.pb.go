@@ -0,0 +1,368 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v4.23.4
+// source: publisher.proto
+
+package publisher
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	PublisherService_Create_FullMethodName      = "/publisher.PublisherService/Create"
+	PublisherService_Get_FullMethodName         = "/publisher.PublisherService/Get"
+	PublisherService_GetList_FullMethodName     = "/publisher.PublisherService/GetList"
+	PublisherService_Update_FullMethodName      = "/publisher.PublisherService/Update"
+	PublisherService_Delete_FullMethodName      = "/publisher.PublisherService/Delete"
+	PublisherService_ListDeleted_FullMethodName = "/publisher.PublisherService/ListDeleted"
+	PublisherService_Restore_FullMethodName     = "/publisher.PublisherService/Restore"
+	PublisherService_BatchGet_FullMethodName    = "/publisher.PublisherService/BatchGet"
+)
+
+// PublisherServiceClient is the client API for PublisherService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PublisherServiceClient interface {
+	Create(ctx context.Context, in *Publisher, opts ...grpc.CallOption) (*CommonPublisherResponse, error)
+	Get(ctx context.Context, in *Publisher, opts ...grpc.CallOption) (*Publisher, error)
+	GetList(ctx context.Context, in *PublisherRequest, opts ...grpc.CallOption) (*PublishersResponse, error)
+	Update(ctx context.Context, in *Publisher, opts ...grpc.CallOption) (*CommonPublisherResponse, error)
+	Delete(ctx context.Context, in *Publisher, opts ...grpc.CallOption) (*CommonPublisherResponse, error)
+	ListDeleted(ctx context.Context, in *PublisherRequest, opts ...grpc.CallOption) (*PublishersResponse, error)
+	Restore(ctx context.Context, in *Publisher, opts ...grpc.CallOption) (*CommonPublisherResponse, error)
+	BatchGet(ctx context.Context, in *BatchGetPublishersRequest, opts ...grpc.CallOption) (*BatchGetPublishersResponse, error)
+}
+
+type publisherServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPublisherServiceClient(cc grpc.ClientConnInterface) PublisherServiceClient {
+	return &publisherServiceClient{cc}
+}
+
+func (c *publisherServiceClient) Create(ctx context.Context, in *Publisher, opts ...grpc.CallOption) (*CommonPublisherResponse, error) {
+	out := new(CommonPublisherResponse)
+	err := c.cc.Invoke(ctx, PublisherService_Create_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *publisherServiceClient) Get(ctx context.Context, in *Publisher, opts ...grpc.CallOption) (*Publisher, error) {
+	out := new(Publisher)
+	err := c.cc.Invoke(ctx, PublisherService_Get_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *publisherServiceClient) GetList(ctx context.Context, in *PublisherRequest, opts ...grpc.CallOption) (*PublishersResponse, error) {
+	out := new(PublishersResponse)
+	err := c.cc.Invoke(ctx, PublisherService_GetList_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *publisherServiceClient) Update(ctx context.Context, in *Publisher, opts ...grpc.CallOption) (*CommonPublisherResponse, error) {
+	out := new(CommonPublisherResponse)
+	err := c.cc.Invoke(ctx, PublisherService_Update_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *publisherServiceClient) Delete(ctx context.Context, in *Publisher, opts ...grpc.CallOption) (*CommonPublisherResponse, error) {
+	out := new(CommonPublisherResponse)
+	err := c.cc.Invoke(ctx, PublisherService_Delete_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *publisherServiceClient) ListDeleted(ctx context.Context, in *PublisherRequest, opts ...grpc.CallOption) (*PublishersResponse, error) {
+	out := new(PublishersResponse)
+	err := c.cc.Invoke(ctx, PublisherService_ListDeleted_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *publisherServiceClient) Restore(ctx context.Context, in *Publisher, opts ...grpc.CallOption) (*CommonPublisherResponse, error) {
+	out := new(CommonPublisherResponse)
+	err := c.cc.Invoke(ctx, PublisherService_Restore_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *publisherServiceClient) BatchGet(ctx context.Context, in *BatchGetPublishersRequest, opts ...grpc.CallOption) (*BatchGetPublishersResponse, error) {
+	out := new(BatchGetPublishersResponse)
+	err := c.cc.Invoke(ctx, PublisherService_BatchGet_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PublisherServiceServer is the server API for PublisherService service.
+// All implementations must embed UnimplementedPublisherServiceServer
+// for forward compatibility
+type PublisherServiceServer interface {
+	Create(context.Context, *Publisher) (*CommonPublisherResponse, error)
+	Get(context.Context, *Publisher) (*Publisher, error)
+	GetList(context.Context, *PublisherRequest) (*PublishersResponse, error)
+	Update(context.Context, *Publisher) (*CommonPublisherResponse, error)
+	Delete(context.Context, *Publisher) (*CommonPublisherResponse, error)
+	ListDeleted(context.Context, *PublisherRequest) (*PublishersResponse, error)
+	Restore(context.Context, *Publisher) (*CommonPublisherResponse, error)
+	BatchGet(context.Context, *BatchGetPublishersRequest) (*BatchGetPublishersResponse, error)
+	mustEmbedUnimplementedPublisherServiceServer()
+}
+
+// UnimplementedPublisherServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedPublisherServiceServer struct {
+}
+
+func (UnimplementedPublisherServiceServer) Create(context.Context, *Publisher) (*CommonPublisherResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Create not implemented")
+}
+func (UnimplementedPublisherServiceServer) Get(context.Context, *Publisher) (*Publisher, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
+}
+func (UnimplementedPublisherServiceServer) GetList(context.Context, *PublisherRequest) (*PublishersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetList not implemented")
+}
+func (UnimplementedPublisherServiceServer) Update(context.Context, *Publisher) (*CommonPublisherResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Update not implemented")
+}
+func (UnimplementedPublisherServiceServer) Delete(context.Context, *Publisher) (*CommonPublisherResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
+func (UnimplementedPublisherServiceServer) ListDeleted(context.Context, *PublisherRequest) (*PublishersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDeleted not implemented")
+}
+func (UnimplementedPublisherServiceServer) Restore(context.Context, *Publisher) (*CommonPublisherResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Restore not implemented")
+}
+func (UnimplementedPublisherServiceServer) BatchGet(context.Context, *BatchGetPublishersRequest) (*BatchGetPublishersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchGet not implemented")
+}
+func (UnimplementedPublisherServiceServer) mustEmbedUnimplementedPublisherServiceServer() {}
+
+// UnsafePublisherServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PublisherServiceServer will
+// result in compilation errors.
+type UnsafePublisherServiceServer interface {
+	mustEmbedUnimplementedPublisherServiceServer()
+}
+
+func RegisterPublisherServiceServer(s grpc.ServiceRegistrar, srv PublisherServiceServer) {
+	s.RegisterService(&PublisherService_ServiceDesc, srv)
+}
+
+func _PublisherService_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Publisher)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PublisherServiceServer).Create(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PublisherService_Create_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PublisherServiceServer).Create(ctx, req.(*Publisher))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PublisherService_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Publisher)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PublisherServiceServer).Get(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PublisherService_Get_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PublisherServiceServer).Get(ctx, req.(*Publisher))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PublisherService_GetList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PublisherRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PublisherServiceServer).GetList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PublisherService_GetList_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PublisherServiceServer).GetList(ctx, req.(*PublisherRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PublisherService_Update_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Publisher)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PublisherServiceServer).Update(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PublisherService_Update_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PublisherServiceServer).Update(ctx, req.(*Publisher))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PublisherService_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Publisher)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PublisherServiceServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PublisherService_Delete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PublisherServiceServer).Delete(ctx, req.(*Publisher))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PublisherService_ListDeleted_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PublisherRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PublisherServiceServer).ListDeleted(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PublisherService_ListDeleted_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PublisherServiceServer).ListDeleted(ctx, req.(*PublisherRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PublisherService_Restore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Publisher)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PublisherServiceServer).Restore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PublisherService_Restore_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PublisherServiceServer).Restore(ctx, req.(*Publisher))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PublisherService_BatchGet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchGetPublishersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PublisherServiceServer).BatchGet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PublisherService_BatchGet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PublisherServiceServer).BatchGet(ctx, req.(*BatchGetPublishersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PublisherService_ServiceDesc is the grpc.ServiceDesc for PublisherService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PublisherService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "publisher.PublisherService",
+	HandlerType: (*PublisherServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Create",
+			Handler:    _PublisherService_Create_Handler,
+		},
+		{
+			MethodName: "Get",
+			Handler:    _PublisherService_Get_Handler,
+		},
+		{
+			MethodName: "GetList",
+			Handler:    _PublisherService_GetList_Handler,
+		},
+		{
+			MethodName: "Update",
+			Handler:    _PublisherService_Update_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _PublisherService_Delete_Handler,
+		},
+		{
+			MethodName: "ListDeleted",
+			Handler:    _PublisherService_ListDeleted_Handler,
+		},
+		{
+			MethodName: "Restore",
+			Handler:    _PublisherService_Restore_Handler,
+		},
+		{
+			MethodName: "BatchGet",
+			Handler:    _PublisherService_BatchGet_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "publisher.proto",
+}
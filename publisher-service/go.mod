@@ -0,0 +1,38 @@
+module github.com/shafaalafghany/publisher-service
+
+go 1.23.3
+
+require (
+	github.com/google/uuid v1.6.0
+	github.com/joho/godotenv v1.5.1
+	gitlab.com/shafaalafghany/synapsis-proto v0.0.0-20241205181320-ad4a6cf44a81
+	go.uber.org/zap v1.27.0
+	google.golang.org/grpc v1.68.0
+	gorm.io/driver/postgres v1.5.11
+	gorm.io/gorm v1.25.12
+)
+
+require golang.org/x/crypto v0.27.0 // indirect
+
+require (
+	github.com/dgrijalva/jwt-go v3.2.0+incompatible
+	github.com/golang/protobuf v1.5.4 // indirect
+	github.com/jackc/pgpassfile v1.0.0 // indirect
+	github.com/jackc/pgservicefile v0.0.0-20221227161230-091c0ba34f0a // indirect
+	github.com/jackc/pgx/v5 v5.5.5 // indirect
+	github.com/jackc/puddle/v2 v2.2.1 // indirect
+	github.com/jinzhu/inflection v1.0.0 // indirect
+	github.com/jinzhu/now v1.1.5 // indirect
+	gitlab.com/shafaalafghany/synapsis-common v0.0.0-00010101000000-000000000000
+	go.uber.org/multierr v1.10.0 // indirect
+	golang.org/x/net v0.29.0 // indirect
+	golang.org/x/sync v0.9.0 // indirect
+	golang.org/x/sys v0.27.0 // indirect
+	golang.org/x/text v0.20.0 // indirect
+	google.golang.org/genproto v0.0.0-20200526211855-cb27e3aa2013 // indirect
+	google.golang.org/protobuf v1.35.2
+)
+
+replace gitlab.com/shafaalafghany/synapsis-proto => ../proto
+
+replace gitlab.com/shafaalafghany/synapsis-common => ../common
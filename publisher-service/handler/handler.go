@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/shafaalafghany/publisher-service/service"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/publisher"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type PublisherHandler struct {
+	publisher.UnimplementedPublisherServiceServer
+	ps  service.PublisherServiceInterface
+	log *zap.Logger
+}
+
+func NewPublisherHandler(ps service.PublisherServiceInterface, log *zap.Logger) *PublisherHandler {
+	return &PublisherHandler{
+		ps:  ps,
+		log: log,
+	}
+}
+
+func (h *PublisherHandler) Create(ctx context.Context, body *publisher.Publisher) (*publisher.CommonPublisherResponse, error) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	body.CreatedBy = userId
+
+	return h.ps.CreatePublisher(ctx, body)
+}
+
+func (h *PublisherHandler) Get(ctx context.Context, body *publisher.Publisher) (*publisher.Publisher, error) {
+	return h.ps.GetPublisher(ctx, body)
+}
+
+func (h *PublisherHandler) BatchGet(ctx context.Context, body *publisher.BatchGetPublishersRequest) (*publisher.BatchGetPublishersResponse, error) {
+	return h.ps.BatchGetPublishers(ctx, body)
+}
+
+func (h *PublisherHandler) Update(ctx context.Context, body *publisher.Publisher) (*publisher.CommonPublisherResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.ps.UpdatePublisher(ctx, body)
+}
+
+func (h *PublisherHandler) GetList(ctx context.Context, body *publisher.PublisherRequest) (*publisher.PublishersResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.ps.GetPublishers(ctx, body)
+}
+
+func (h *PublisherHandler) Delete(ctx context.Context, body *publisher.Publisher) (*publisher.CommonPublisherResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.ps.DeletePublisher(ctx, body)
+}
+
+func (h *PublisherHandler) ListDeleted(ctx context.Context, body *publisher.PublisherRequest) (*publisher.PublishersResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.ps.ListDeletedPublishers(ctx, body)
+}
+
+func (h *PublisherHandler) Restore(ctx context.Context, body *publisher.Publisher) (*publisher.CommonPublisherResponse, error) {
+	_, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.ps.RestorePublisher(ctx, body)
+}
+
+func getUserIDFromContext(ctx context.Context) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", errors.New("missing metadata")
+	}
+
+	authHeader, ok := md["publisherization"]
+	if !ok || len(authHeader) == 0 {
+		return "", errors.New("missing publisherization header")
+	}
+
+	tokenStr := strings.TrimPrefix(authHeader[0], "Bearer ")
+	token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return []byte(os.Getenv("SECRET_KEY")), nil
+	})
+
+	if err != nil || !token.Valid {
+		return "", errors.New("invalid token")
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return "", errors.New("invalid token claims")
+	}
+
+	userID, ok := claims["id"].(string)
+	if !ok {
+		return "", errors.New("invalid user ID in token claims")
+	}
+
+	return userID, nil
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/shafaalafghany/publisher-service/handler"
+	"github.com/shafaalafghany/publisher-service/middleware"
+	"github.com/shafaalafghany/publisher-service/model"
+	"github.com/shafaalafghany/publisher-service/repository"
+	"github.com/shafaalafghany/publisher-service/service"
+	"gitlab.com/shafaalafghany/synapsis-common/purge"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/book"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/publisher"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/user"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/reflection"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type Config struct {
+	DBHost      string
+	DBUser      string
+	DBPassword  string
+	DBPort      string
+	DBName      string
+	JwtSecret   string
+	AppPort     string
+	UserService string
+	BookService string
+
+	PurgeRetention string
+	PurgeInterval  string
+}
+
+func main() {
+	_ = godotenv.Load()
+
+	config := Config{
+		JwtSecret:   os.Getenv("SECRET_KEY"),
+		AppPort:     os.Getenv("APP_PORT"),
+		DBHost:      os.Getenv("DB_HOST"),
+		DBPort:      os.Getenv("DB_PORT"),
+		DBUser:      os.Getenv("DB_USER"),
+		DBPassword:  os.Getenv("DB_PASS"),
+		DBName:      os.Getenv("DB_NAME"),
+		UserService: os.Getenv("USER_SERVICE"),
+		BookService: os.Getenv("BOOK_SERVICE"),
+
+		PurgeRetention: os.Getenv("PURGE_RETENTION"),
+		PurgeInterval:  os.Getenv("PURGE_INTERVAL"),
+	}
+
+	logConfig := zap.NewDevelopmentConfig()
+	logConfig.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	logger, err := logConfig.Build()
+	if err != nil {
+		panic(err)
+	}
+
+	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s", config.DBHost, config.DBUser, config.DBPassword, config.DBName, config.DBPort)
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		panic(err)
+	}
+
+	db.Exec("CREATE EXTENSION IF NOT EXISTS \"uuid-ossp\"")
+	db.AutoMigrate(&model.Publisher{})
+
+	userConn, err := grpc.NewClient(config.UserService, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatalf("failed to connect user service %v", err)
+	}
+	defer userConn.Close()
+
+	userClient := user.NewUserServiceClient(userConn)
+
+	publisherRepo := repository.NewPublisherRepository(db, logger)
+	publisherService := service.NewPublisherService(publisherRepo, logger, userClient)
+	publisherHandler := handler.NewPublisherHandler(publisherService, logger)
+
+	if config.PurgeRetention != "" {
+		retention, err := time.ParseDuration(config.PurgeRetention)
+		if err != nil {
+			log.Fatalf("invalid PURGE_RETENTION %v", err)
+		}
+
+		interval := 24 * time.Hour
+		if config.PurgeInterval != "" {
+			if interval, err = time.ParseDuration(config.PurgeInterval); err != nil {
+				log.Fatalf("invalid PURGE_INTERVAL %v", err)
+			}
+		}
+
+		if config.BookService == "" {
+			log.Fatalf("PURGE_RETENTION requires BOOK_SERVICE to check which publishers books still use")
+		}
+		bookConn, err := grpc.NewClient(config.BookService, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			log.Fatalf("failed to connect book service %v", err)
+		}
+		defer bookConn.Close()
+
+		referenced := service.ReferencedByBooks(book.NewBookServiceClient(bookConn), config.JwtSecret)
+		go purge.NewJob(purge.SkipReferenced(publisherRepo, referenced), "publishers", logger, retention, interval).Run(context.Background())
+	}
+
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(middleware.JWTAuthInterceptor(config.JwtSecret)),
+	)
+	publisher.RegisterPublisherServiceServer(server, publisherHandler)
+	reflection.Register(server)
+
+	listen, err := net.Listen("tcp", ":"+config.AppPort)
+	if err != nil {
+		log.Fatalf("failed to listen: %v", err)
+	}
+
+	if err := server.Serve(listen); err != nil {
+		log.Fatalf("failed to serve: %v", err)
+	}
+	fmt.Println("listened at ", config.AppPort)
+}
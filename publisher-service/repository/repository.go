@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/shafaalafghany/publisher-service/model"
+	"gitlab.com/shafaalafghany/synapsis-common/pagination"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type PublisherRepositoryInterface interface {
+	Create(*model.Publisher) error
+	GetById(string) (*model.Publisher, error)
+	GetByIds([]string) ([]*model.Publisher, error)
+	Get(pagination.ListOptions) (*PublisherPage, error)
+	Update(*model.Publisher, string) error
+	Delete(string) error
+	GetDeleted(pagination.ListOptions) (*PublisherPage, error)
+	GetDeletedById(string) (*model.Publisher, error)
+	Restore(string) error
+	Purgeable(time.Time, string, int) ([]string, error)
+	PurgeIds(time.Time, []string) (int64, error)
+}
+
+type PublisherRepository struct {
+	db  *gorm.DB
+	log *zap.Logger
+}
+
+type PublisherPage struct {
+	Publishers    []*model.Publisher
+	NextPageToken string
+	TotalCount    int64
+}
+
+var publisherSortColumns = map[string]pagination.SortKind{
+	"name":       pagination.SortString,
+	"created_at": pagination.SortTime,
+	"updated_at": pagination.SortTime,
+}
+
+var deletedPublisherSortColumns = map[string]pagination.SortKind{
+	"name":       pagination.SortString,
+	"created_at": pagination.SortTime,
+	"updated_at": pagination.SortTime,
+	"deleted_at": pagination.SortTime,
+}
+
+func NewPublisherRepository(db *gorm.DB, log *zap.Logger) PublisherRepositoryInterface {
+	return &PublisherRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+func (r *PublisherRepository) Create(data *model.Publisher) error {
+	if err := r.db.Create(&data).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *PublisherRepository) GetById(id string) (*model.Publisher, error) {
+	var publisher model.Publisher
+	if err := r.db.Where("id = ? AND deleted_at IS NULL", id).First(&publisher).Error; err != nil {
+		return nil, err
+	}
+
+	return &publisher, nil
+}
+
+func (r *PublisherRepository) GetByIds(ids []string) ([]*model.Publisher, error) {
+	var publishers []*model.Publisher
+	if err := r.db.Where("id IN ? AND deleted_at IS NULL", ids).Find(&publishers).Error; err != nil {
+		return nil, err
+	}
+
+	return publishers, nil
+}
+
+func (r *PublisherRepository) Get(opts pagination.ListOptions) (*PublisherPage, error) {
+	if err := opts.Normalize(publisherSortColumns, "created_at", "asc"); err != nil {
+		return nil, err
+	}
+
+	base := r.db.Model(&model.Publisher{}).Where("deleted_at IS NULL")
+
+	if opts.Search != "" {
+		base.Where("name ILIKE ?", "%"+opts.Search+"%")
+	}
+
+	return r.page(base, opts, publisherSortColumns)
+}
+
+func (r *PublisherRepository) Update(data *model.Publisher, id string) error {
+	if err := r.db.Model(&model.Publisher{}).Where("id = ? AND deleted_at IS NULL", id).Update("name", data.Name).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *PublisherRepository) Delete(id string) error {
+	if err := r.db.Model(&model.Publisher{}).Where("id = ? AND deleted_at IS NULL", id).Update("deleted_at", time.Now()).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *PublisherRepository) GetDeleted(opts pagination.ListOptions) (*PublisherPage, error) {
+	if err := opts.Normalize(deletedPublisherSortColumns, "deleted_at", "desc"); err != nil {
+		return nil, err
+	}
+
+	base := r.db.Model(&model.Publisher{}).Where("deleted_at IS NOT NULL")
+
+	if opts.Search != "" {
+		base.Where("name ILIKE ?", "%"+opts.Search+"%")
+	}
+
+	return r.page(base, opts, deletedPublisherSortColumns)
+}
+
+func (r *PublisherRepository) GetDeletedById(id string) (*model.Publisher, error) {
+	var publisher model.Publisher
+	if err := r.db.Where("id = ? AND deleted_at IS NOT NULL", id).First(&publisher).Error; err != nil {
+		return nil, err
+	}
+
+	return &publisher, nil
+}
+
+func (r *PublisherRepository) Restore(id string) error {
+	if err := r.db.Model(&model.Publisher{}).Where("id = ? AND deleted_at IS NOT NULL", id).Update("deleted_at", nil).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// Purgeable lists publishers that have been in the trash since before the
+// given time, for the purge job to check against book-service.
+func (r *PublisherRepository) Purgeable(before time.Time, after string, limit int) ([]string, error) {
+	var ids []string
+	if err := r.db.Model(&model.Publisher{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ? AND id::text > ?", before, after).
+		Order("id::text ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (r *PublisherRepository) PurgeIds(before time.Time, ids []string) (int64, error) {
+	result := r.db.Where("id IN ? AND deleted_at IS NOT NULL AND deleted_at < ?", ids, before).Delete(&model.Publisher{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+func (r *PublisherRepository) page(base *gorm.DB, opts pagination.ListOptions, columns map[string]pagination.SortKind) (*PublisherPage, error) {
+	page := &PublisherPage{}
+
+	if opts.IncludeTotal {
+		if err := base.Session(&gorm.Session{}).Count(&page.TotalCount).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	query, err := pagination.Paginate(base.Session(&gorm.Session{}), opts, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := query.Find(&page.Publishers).Error; err != nil {
+		return nil, err
+	}
+
+	if len(page.Publishers) > opts.PageSize {
+		page.Publishers = page.Publishers[:opts.PageSize]
+		last := page.Publishers[len(page.Publishers)-1]
+		page.NextPageToken = pagination.EncodePageToken(opts, publisherSortValue(last, opts.SortBy), last.ID)
+	}
+
+	return page, nil
+}
+
+func publisherSortValue(data *model.Publisher, sortBy string) interface{} {
+	switch sortBy {
+	case "name":
+		return data.Name
+	case "updated_at":
+		return data.UpdatedAt
+	case "deleted_at":
+		if data.DeletedAt != nil {
+			return *data.DeletedAt
+		}
+		return nil
+	default:
+		return data.CreatedAt
+	}
+}
@@ -0,0 +1,368 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/shafaalafghany/publisher-service/model"
+	"github.com/shafaalafghany/publisher-service/repository"
+	"gitlab.com/shafaalafghany/synapsis-common/pagination"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/publisher"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/user"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"gorm.io/gorm"
+)
+
+const (
+	roleAdmin    = "admin"
+	maxBatchSize = 100
+)
+
+type PublisherServiceInterface interface {
+	CreatePublisher(context.Context, *publisher.Publisher) (*publisher.CommonPublisherResponse, error)
+	GetPublisher(context.Context, *publisher.Publisher) (*publisher.Publisher, error)
+	GetPublishers(context.Context, *publisher.PublisherRequest) (*publisher.PublishersResponse, error)
+	BatchGetPublishers(context.Context, *publisher.BatchGetPublishersRequest) (*publisher.BatchGetPublishersResponse, error)
+	UpdatePublisher(context.Context, *publisher.Publisher) (*publisher.CommonPublisherResponse, error)
+	DeletePublisher(context.Context, *publisher.Publisher) (*publisher.CommonPublisherResponse, error)
+	ListDeletedPublishers(context.Context, *publisher.PublisherRequest) (*publisher.PublishersResponse, error)
+	RestorePublisher(context.Context, *publisher.Publisher) (*publisher.CommonPublisherResponse, error)
+}
+
+type PublisherService struct {
+	repo        repository.PublisherRepositoryInterface
+	log         *zap.Logger
+	userService user.UserServiceClient
+}
+
+func NewPublisherService(repo repository.PublisherRepositoryInterface, log *zap.Logger, userService user.UserServiceClient) PublisherServiceInterface {
+	return &PublisherService{
+		repo:        repo,
+		log:         log,
+		userService: userService,
+	}
+}
+
+func (s *PublisherService) CreatePublisher(ctx context.Context, body *publisher.Publisher) (*publisher.CommonPublisherResponse, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Internal, "missing outgoing metadata")
+	}
+	outbondCtx := metadata.NewOutgoingContext(ctx, md)
+
+	user, err := s.userService.GetUser(outbondCtx, &emptypb.Empty{})
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid user")
+	}
+
+	id := uuid.NewString()
+	data := &model.Publisher{
+		ID:        id,
+		Name:      body.Name,
+		CreatedBy: user.Id,
+	}
+
+	if err := s.repo.Create(data); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	response := fmt.Sprintf("create new publisher successfully with id %v", id)
+
+	return &publisher.CommonPublisherResponse{Message: response}, nil
+}
+
+func (s *PublisherService) GetPublisher(ctx context.Context, body *publisher.Publisher) (*publisher.Publisher, error) {
+	if body.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id cannot be empty")
+	}
+
+	data, err := s.repo.GetById(body.Id)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	res := &publisher.Publisher{
+		Id:        data.ID,
+		Name:      data.Name,
+		CreatedBy: data.CreatedBy,
+		CreatedAt: data.CreatedAt.String(),
+		UpdatedAt: data.UpdatedAt.String(),
+	}
+
+	return res, nil
+}
+
+func (s *PublisherService) BatchGetPublishers(ctx context.Context, body *publisher.BatchGetPublishersRequest) (*publisher.BatchGetPublishersResponse, error) {
+	ids, invalid, err := batchIds(body.GetIds())
+	if err != nil {
+		return nil, err
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Internal, "missing outgoing metadata")
+	}
+	outbondCtx := metadata.NewOutgoingContext(ctx, md)
+
+	_, err = s.userService.GetUser(outbondCtx, &emptypb.Empty{})
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid user")
+	}
+
+	if len(ids) == 0 {
+		return &publisher.BatchGetPublishersResponse{Publishers: []*publisher.Publisher{}, MissingIds: invalid}, nil
+	}
+
+	data, err := s.repo.GetByIds(ids)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	found := map[string]*model.Publisher{}
+	for _, v := range data {
+		found[v.ID] = v
+	}
+
+	publishers := []*publisher.Publisher{}
+	missing := invalid
+	for _, id := range ids {
+		v, ok := found[id]
+		if !ok {
+			missing = append(missing, id)
+			continue
+		}
+
+		publishers = append(publishers, &publisher.Publisher{
+			Id:        v.ID,
+			Name:      v.Name,
+			CreatedBy: v.CreatedBy,
+			CreatedAt: v.CreatedAt.String(),
+			UpdatedAt: v.UpdatedAt.String(),
+		})
+	}
+
+	return &publisher.BatchGetPublishersResponse{
+		Publishers: publishers,
+		MissingIds: missing,
+	}, nil
+}
+
+func (s *PublisherService) GetPublishers(ctx context.Context, body *publisher.PublisherRequest) (*publisher.PublishersResponse, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Internal, "missing outgoing metadata")
+	}
+	outbondCtx := metadata.NewOutgoingContext(ctx, md)
+
+	_, err := s.userService.GetUser(outbondCtx, &emptypb.Empty{})
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid user")
+	}
+
+	data, err := s.repo.Get(listOptions(body))
+	if err != nil {
+		return nil, listError(err)
+	}
+
+	publishers := []*publisher.Publisher{}
+
+	if len(data.Publishers) > 0 {
+		for _, v := range data.Publishers {
+			temp := &publisher.Publisher{
+				Id:        v.ID,
+				Name:      v.Name,
+				CreatedBy: v.CreatedBy,
+				CreatedAt: v.CreatedAt.String(),
+				UpdatedAt: v.UpdatedAt.String(),
+			}
+
+			publishers = append(publishers, temp)
+		}
+	}
+
+	return &publisher.PublishersResponse{
+		Publishers:    publishers,
+		NextPageToken: data.NextPageToken,
+		TotalCount:    data.TotalCount,
+	}, nil
+}
+
+func (s *PublisherService) UpdatePublisher(ctx context.Context, body *publisher.Publisher) (*publisher.CommonPublisherResponse, error) {
+	if body.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id cannot be empty")
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Internal, "missing outgoing metadata")
+	}
+	outbondCtx := metadata.NewOutgoingContext(ctx, md)
+
+	_, err := s.userService.GetUser(outbondCtx, &emptypb.Empty{})
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid user")
+	}
+
+	_, err = s.repo.GetById(body.Id)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	updateData := &model.Publisher{Name: body.Name}
+	if err := s.repo.Update(updateData, body.Id); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &publisher.CommonPublisherResponse{Message: "update publisher successfully"}, nil
+}
+
+func (s *PublisherService) DeletePublisher(ctx context.Context, body *publisher.Publisher) (*publisher.CommonPublisherResponse, error) {
+	if body.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id cannot be empty")
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Internal, "missing outgoing metadata")
+	}
+	outbondCtx := metadata.NewOutgoingContext(ctx, md)
+
+	_, err := s.userService.GetUser(outbondCtx, &emptypb.Empty{})
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid user")
+	}
+
+	_, err = s.repo.GetById(body.Id)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if err := s.repo.Delete(body.Id); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &publisher.CommonPublisherResponse{Message: "delete publisher successfully"}, nil
+}
+
+func (s *PublisherService) ListDeletedPublishers(ctx context.Context, body *publisher.PublisherRequest) (*publisher.PublishersResponse, error) {
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	data, err := s.repo.GetDeleted(listOptions(body))
+	if err != nil {
+		return nil, listError(err)
+	}
+
+	publishers := []*publisher.Publisher{}
+	for _, v := range data.Publishers {
+		publishers = append(publishers, &publisher.Publisher{
+			Id:        v.ID,
+			Name:      v.Name,
+			CreatedBy: v.CreatedBy,
+			CreatedAt: v.CreatedAt.String(),
+			UpdatedAt: v.UpdatedAt.String(),
+			DeletedAt: v.DeletedAt.String(),
+		})
+	}
+
+	return &publisher.PublishersResponse{
+		Publishers:    publishers,
+		NextPageToken: data.NextPageToken,
+		TotalCount:    data.TotalCount,
+	}, nil
+}
+
+func (s *PublisherService) RestorePublisher(ctx context.Context, body *publisher.Publisher) (*publisher.CommonPublisherResponse, error) {
+	if body.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id cannot be empty")
+	}
+
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.GetDeletedById(body.Id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Error(codes.NotFound, "deleted publisher not found")
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if err := s.repo.Restore(body.Id); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &publisher.CommonPublisherResponse{Message: "restore publisher successfully"}, nil
+}
+
+func (s *PublisherService) requireAdmin(ctx context.Context) error {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return status.Error(codes.Internal, "missing outgoing metadata")
+	}
+	outbondCtx := metadata.NewOutgoingContext(ctx, md)
+
+	user, err := s.userService.GetUser(outbondCtx, &emptypb.Empty{})
+	if err != nil {
+		return status.Error(codes.Unauthenticated, "invalid user")
+	}
+
+	if user.GetRole() != roleAdmin {
+		return status.Error(codes.PermissionDenied, "admin role required")
+	}
+
+	return nil
+}
+
+// batchIds dedupes the requested ids and splits off the ones that are not
+// UUIDs, which cannot match a row and would fail the query on the uuid column.
+func batchIds(ids []string) ([]string, []string, error) {
+	seen := map[string]bool{}
+	result := []string{}
+	invalid := []string{}
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		if _, err := uuid.Parse(id); err != nil {
+			invalid = append(invalid, id)
+			continue
+		}
+		result = append(result, id)
+	}
+
+	if len(result)+len(invalid) > maxBatchSize {
+		return nil, nil, status.Error(codes.InvalidArgument, fmt.Sprintf("at most %d ids can be requested at once", maxBatchSize))
+	}
+
+	return result, invalid, nil
+}
+
+func listOptions(body *publisher.PublisherRequest) pagination.ListOptions {
+	return pagination.ListOptions{
+		Search:       body.GetSearch(),
+		PageSize:     int(body.GetPageSize()),
+		PageToken:    body.GetPageToken(),
+		SortBy:       body.GetSortBy(),
+		SortOrder:    body.GetSortOrder(),
+		IncludeTotal: body.GetIncludeTotal(),
+	}
+}
+
+func listError(err error) error {
+	if errors.Is(err, pagination.ErrInvalidPageToken) || errors.Is(err, pagination.ErrInvalidSort) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return status.Error(codes.Internal, err.Error())
+}
@@ -0,0 +1,22 @@
+module github.com/shafaalafghany/search-service
+
+go 1.23.3
+
+require (
+	github.com/dgrijalva/jwt-go v3.2.0+incompatible
+	github.com/joho/godotenv v1.5.1
+	gitlab.com/shafaalafghany/synapsis-proto v0.0.0-20241206001001-703dfc2710e5
+	go.uber.org/zap v1.27.0
+	google.golang.org/grpc v1.68.1
+	google.golang.org/protobuf v1.35.2
+)
+
+require (
+	go.uber.org/multierr v1.10.0 // indirect
+	golang.org/x/net v0.29.0 // indirect
+	golang.org/x/sys v0.25.0 // indirect
+	golang.org/x/text v0.18.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240903143218-8af14fe29dc1 // indirect
+)
+
+replace gitlab.com/shafaalafghany/synapsis-proto => ../proto
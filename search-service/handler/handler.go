@@ -0,0 +1,26 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/shafaalafghany/search-service/service"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/search"
+	"go.uber.org/zap"
+)
+
+type SearchHandler struct {
+	search.UnimplementedSearchServiceServer
+	s   service.SearchServiceInterface
+	log *zap.Logger
+}
+
+func NewSearchHandler(s service.SearchServiceInterface, log *zap.Logger) *SearchHandler {
+	return &SearchHandler{
+		s:   s,
+		log: log,
+	}
+}
+
+func (h *SearchHandler) Search(ctx context.Context, body *search.SearchRequest) (*search.SearchResponse, error) {
+	return h.s.Search(ctx, body)
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/shafaalafghany/search-service/handler"
+	"github.com/shafaalafghany/search-service/middleware"
+	"github.com/shafaalafghany/search-service/service"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/author"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/book"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/category"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/search"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/user"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/reflection"
+)
+
+type Config struct {
+	JwtSecret       string
+	AppPort         string
+	UserService     string
+	BookService     string
+	AuthorService   string
+	CategoryService string
+
+	SearchTimeout string
+}
+
+func main() {
+	_ = godotenv.Load()
+
+	config := Config{
+		JwtSecret:       os.Getenv("SECRET_KEY"),
+		AppPort:         os.Getenv("APP_PORT"),
+		UserService:     os.Getenv("USER_SERVICE"),
+		BookService:     os.Getenv("BOOK_SERVICE"),
+		AuthorService:   os.Getenv("AUTHOR_SERVICE"),
+		CategoryService: os.Getenv("CATEGORY_SERVICE"),
+
+		SearchTimeout: os.Getenv("SEARCH_TIMEOUT"),
+	}
+
+	logConfig := zap.NewDevelopmentConfig()
+	logConfig.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	logger, err := logConfig.Build()
+	if err != nil {
+		panic(err)
+	}
+
+	timeout := 2 * time.Second
+	if config.SearchTimeout != "" {
+		if timeout, err = time.ParseDuration(config.SearchTimeout); err != nil {
+			log.Fatalf("invalid SEARCH_TIMEOUT %v", err)
+		}
+	}
+
+	userConn, err := grpc.NewClient(config.UserService, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatalf("failed to connect user service %v", err)
+	}
+	defer userConn.Close()
+
+	bookConn, err := grpc.NewClient(config.BookService, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatalf("failed to connect book service %v", err)
+	}
+	defer bookConn.Close()
+
+	authorConn, err := grpc.NewClient(config.AuthorService, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatalf("failed to connect author service %v", err)
+	}
+	defer authorConn.Close()
+
+	categoryConn, err := grpc.NewClient(config.CategoryService, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatalf("failed to connect category service %v", err)
+	}
+	defer categoryConn.Close()
+
+	userClient := user.NewUserServiceClient(userConn)
+	bookClient := book.NewBookServiceClient(bookConn)
+	authorClient := author.NewAuthorServiceClient(authorConn)
+	categoryClient := category.NewCategoryServiceClient(categoryConn)
+
+	searchService := service.NewSearchService(logger, userClient, bookClient, authorClient, categoryClient, timeout)
+	searchHandler := handler.NewSearchHandler(searchService, logger)
+
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(middleware.JWTAuthInterceptor(config.JwtSecret)),
+	)
+	search.RegisterSearchServiceServer(server, searchHandler)
+	reflection.Register(server)
+
+	listen, err := net.Listen("tcp", ":"+config.AppPort)
+	if err != nil {
+		log.Fatalf("failed to listen: %v", err)
+	}
+
+	if err := server.Serve(listen); err != nil {
+		log.Fatalf("failed to serve: %v", err)
+	}
+	fmt.Println("listened at ", config.AppPort)
+
+}
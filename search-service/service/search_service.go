@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gitlab.com/shafaalafghany/synapsis-proto/go/author"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/book"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/category"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/search"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/user"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const (
+	defaultLimit = 10
+	maxLimit     = 50
+)
+
+var defaultTypes = []search.EntityType{
+	search.EntityType_BOOK,
+	search.EntityType_AUTHOR,
+	search.EntityType_CATEGORY,
+}
+
+type SearchServiceInterface interface {
+	Search(context.Context, *search.SearchRequest) (*search.SearchResponse, error)
+}
+
+type SearchService struct {
+	log         *zap.Logger
+	userSvc     user.UserServiceClient
+	bookSvc     book.BookServiceClient
+	authorSvc   author.AuthorServiceClient
+	categorySvc category.CategoryServiceClient
+	timeout     time.Duration
+}
+
+func NewSearchService(log *zap.Logger, userSvc user.UserServiceClient, bookSvc book.BookServiceClient, authorSvc author.AuthorServiceClient, categorySvc category.CategoryServiceClient, timeout time.Duration) SearchServiceInterface {
+	return &SearchService{
+		log:         log,
+		userSvc:     userSvc,
+		bookSvc:     bookSvc,
+		authorSvc:   authorSvc,
+		categorySvc: categorySvc,
+		timeout:     timeout,
+	}
+}
+
+func (s *SearchService) Search(ctx context.Context, body *search.SearchRequest) (*search.SearchResponse, error) {
+	query := strings.TrimSpace(body.GetQuery())
+	if query == "" {
+		return nil, status.Error(codes.InvalidArgument, "query cannot be empty")
+	}
+
+	limit := int32(defaultLimit)
+	if body.GetLimit() > 0 {
+		limit = min(body.GetLimit(), maxLimit)
+	}
+
+	types, err := entityTypes(body.GetTypes())
+	if err != nil {
+		return nil, err
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Internal, "missing outgoing metadata")
+	}
+	outbondCtx := metadata.NewOutgoingContext(ctx, md)
+
+	_, err = s.userSvc.GetUser(outbondCtx, &emptypb.Empty{})
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid user")
+	}
+
+	groups := make([]*search.SearchGroup, len(types))
+	var wg sync.WaitGroup
+	for i, t := range types {
+		wg.Add(1)
+		go func(i int, t search.EntityType) {
+			defer wg.Done()
+
+			callCtx, cancel := context.WithTimeout(outbondCtx, s.timeout)
+			defer cancel()
+
+			hits, err := s.fetch(callCtx, t, query, limit)
+			if err != nil {
+				s.log.Warn("search backend failed", zap.String("type", t.String()), zap.Error(err))
+				groups[i] = &search.SearchGroup{Type: t, Hits: []*search.SearchHit{}, Error: status.Convert(err).Message()}
+				return
+			}
+
+			rank(query, hits)
+			groups[i] = &search.SearchGroup{Type: t, Hits: hits, Available: true}
+		}(i, t)
+	}
+	wg.Wait()
+
+	available := 0
+	for _, g := range groups {
+		if g.Available {
+			available++
+		}
+	}
+
+	if available == 0 {
+		return nil, status.Error(codes.Unavailable, "search backends unavailable")
+	}
+
+	return &search.SearchResponse{
+		Groups:  groups,
+		Partial: available < len(groups),
+	}, nil
+}
+
+func (s *SearchService) fetch(ctx context.Context, t search.EntityType, query string, limit int32) ([]*search.SearchHit, error) {
+	hits := []*search.SearchHit{}
+
+	switch t {
+	case search.EntityType_BOOK:
+		data, err := s.bookSvc.Getlist(ctx, &book.BookRequest{Search: query, PageSize: limit})
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range data.GetBooks() {
+			hits = append(hits, &search.SearchHit{Type: t, Id: v.GetId(), Title: v.GetName(), Subtitle: v.GetAuthorName()})
+		}
+	case search.EntityType_AUTHOR:
+		data, err := s.authorSvc.GetList(ctx, &author.AuthorRequest{Search: query, PageSize: limit})
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range data.GetAuthors() {
+			hits = append(hits, &search.SearchHit{Type: t, Id: v.GetId(), Title: v.GetName()})
+		}
+	case search.EntityType_CATEGORY:
+		data, err := s.categorySvc.GetList(ctx, &category.CategoryRequest{Search: query, PageSize: limit})
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range data.GetCategories() {
+			hits = append(hits, &search.SearchHit{Type: t, Id: v.GetId(), Title: v.GetName(), Subtitle: v.GetSlug()})
+		}
+	}
+
+	return hits, nil
+}
+
+func entityTypes(types []search.EntityType) ([]search.EntityType, error) {
+	if len(types) == 0 {
+		return defaultTypes, nil
+	}
+
+	seen := map[search.EntityType]bool{}
+	result := []search.EntityType{}
+	for _, t := range types {
+		if t == search.EntityType_ENTITY_TYPE_UNSPECIFIED || search.EntityType_name[int32(t)] == "" {
+			return nil, status.Error(codes.InvalidArgument, "invalid entity type")
+		}
+		if !seen[t] {
+			seen[t] = true
+			result = append(result, t)
+		}
+	}
+
+	return result, nil
+}
+
+func rank(query string, hits []*search.SearchHit) {
+	q := strings.ToLower(query)
+	for _, h := range hits {
+		h.Score = score(q, strings.ToLower(h.Title))
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return strings.ToLower(hits[i].Title) < strings.ToLower(hits[j].Title)
+	})
+}
+
+func score(query, title string) float64 {
+	switch {
+	case title == query:
+		return 1
+	case strings.HasPrefix(title, query):
+		return 0.8
+	case strings.Contains(" "+title, " "+query):
+		return 0.6
+	case strings.Contains(title, query):
+		return 0.4
+	default:
+		return 0.2
+	}
+}
@@ -23,6 +23,7 @@ require (
 	github.com/jackc/puddle/v2 v2.2.1 // indirect
 	github.com/jinzhu/inflection v1.0.0 // indirect
 	github.com/jinzhu/now v1.1.5 // indirect
+	gitlab.com/shafaalafghany/synapsis-common v0.0.0-00010101000000-000000000000
 	go.uber.org/multierr v1.10.0 // indirect
 	golang.org/x/net v0.29.0 // indirect
 	golang.org/x/sync v0.9.0 // indirect
@@ -31,3 +32,7 @@ require (
 	google.golang.org/genproto v0.0.0-20200526211855-cb27e3aa2013 // indirect
 	google.golang.org/protobuf v1.35.2
 )
+
+replace gitlab.com/shafaalafghany/synapsis-proto => ../proto
+
+replace gitlab.com/shafaalafghany/synapsis-common => ../common
@@ -79,6 +79,33 @@ func (h *UserHandler) DeleteUser(ctx context.Context, empty *emptypb.Empty) (*us
 	return res, nil
 }
 
+func (h *UserHandler) ListDeleted(ctx context.Context, body *user.UserRequest) (*user.UsersResponse, error) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.us.ListDeleted(ctx, body, userId)
+}
+
+func (h *UserHandler) Restore(ctx context.Context, body *user.User) (*user.CommonUserResponse, error) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.us.Restore(ctx, body, userId)
+}
+
+func (h *UserHandler) BatchGet(ctx context.Context, body *user.BatchGetUsersRequest) (*user.BatchGetUsersResponse, error) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return h.us.BatchGet(ctx, body, userId)
+}
+
 func getUserIDFromContext(ctx context.Context) (string, error) {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
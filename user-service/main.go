@@ -1,10 +1,12 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net"
 	"os"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/shafaalafghany/user-service/handler"
@@ -12,10 +14,13 @@ import (
 	"github.com/shafaalafghany/user-service/model"
 	"github.com/shafaalafghany/user-service/repository"
 	"github.com/shafaalafghany/user-service/service"
+	"gitlab.com/shafaalafghany/synapsis-common/purge"
+	"gitlab.com/shafaalafghany/synapsis-proto/go/book"
 	"gitlab.com/shafaalafghany/synapsis-proto/go/user"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/reflection"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -29,6 +34,11 @@ type Config struct {
 	DBName     string
 	JwtSecret  string
 	AppPort    string
+
+	BookService string
+
+	PurgeRetention string
+	PurgeInterval  string
 }
 
 func main() {
@@ -42,6 +52,11 @@ func main() {
 		DBUser:     os.Getenv("DB_USER"),
 		DBPassword: os.Getenv("DB_PASS"),
 		DBName:     os.Getenv("DB_NAME"),
+
+		BookService: os.Getenv("BOOK_SERVICE"),
+
+		PurgeRetention: os.Getenv("PURGE_RETENTION"),
+		PurgeInterval:  os.Getenv("PURGE_INTERVAL"),
 	}
 
 	logConfig := zap.NewDevelopmentConfig()
@@ -63,6 +78,32 @@ func main() {
 	userRepo := repository.NewUserRepository(db, logger)
 	userService := service.NewUserService(userRepo, logger)
 
+	if config.PurgeRetention != "" {
+		retention, err := time.ParseDuration(config.PurgeRetention)
+		if err != nil {
+			log.Fatalf("invalid PURGE_RETENTION %v", err)
+		}
+
+		interval := 24 * time.Hour
+		if config.PurgeInterval != "" {
+			if interval, err = time.ParseDuration(config.PurgeInterval); err != nil {
+				log.Fatalf("invalid PURGE_INTERVAL %v", err)
+			}
+		}
+
+		if config.BookService == "" {
+			log.Fatalf("PURGE_RETENTION requires BOOK_SERVICE to check which users books still refer to")
+		}
+		bookConn, err := grpc.NewClient(config.BookService, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			log.Fatalf("failed to connect book service %v", err)
+		}
+		defer bookConn.Close()
+
+		referenced := service.ReferencedByBooks(book.NewBookServiceClient(bookConn), config.JwtSecret)
+		go purge.NewJob(purge.SkipReferenced(userRepo, referenced), "users", logger, retention, interval).Run(context.Background())
+	}
+
 	server := grpc.NewServer(
 		grpc.ChainUnaryInterceptor(middleware.JWTAuthInterceptor(config.JwtSecret)),
 	)
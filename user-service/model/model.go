@@ -7,11 +7,18 @@ import (
 	"gorm.io/gorm"
 )
 
+const (
+	RoleMember    = "member"
+	RoleLibrarian = "librarian"
+	RoleAdmin     = "admin"
+)
+
 type User struct {
 	ID        string     `json:"id" gorm:"type:uuid;default:uuid_generate_v4();primary_key"`
 	Name      string     `json:"name" gorm:"not null"`
 	Email     string     `json:"email" gorm:"unique;not null"`
 	Password  string     `json:"-" gorm:"not null"`
+	Role      string     `json:"role" gorm:"not null;default:'member'"`
 	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
 	DeletedAt *time.Time `json:"deleted_at" gorm:"index"`
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/shafaalafghany/user-service/model"
+	"gitlab.com/shafaalafghany/synapsis-common/pagination"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
@@ -12,8 +13,14 @@ type UserRepositoryInterface interface {
 	Create(*model.User) error
 	GetUserByEmail(*model.User) (*model.User, error)
 	GetUserById(*model.User) (*model.User, error)
+	GetUsersByIds([]string) ([]*model.User, error)
 	UpdateUser(*model.User, string) error
 	DeleteUser(string) error
+	GetDeleted(pagination.ListOptions) (*UserPage, error)
+	GetDeletedById(string) (*model.User, error)
+	Restore(string) error
+	Purgeable(time.Time, string, int) ([]string, error)
+	PurgeIds(time.Time, []string) (int64, error)
 }
 
 type UserRepository struct {
@@ -21,6 +28,20 @@ type UserRepository struct {
 	log *zap.Logger
 }
 
+type UserPage struct {
+	Users         []*model.User
+	NextPageToken string
+	TotalCount    int64
+}
+
+var deletedUserSortColumns = map[string]pagination.SortKind{
+	"name":       pagination.SortString,
+	"email":      pagination.SortString,
+	"created_at": pagination.SortTime,
+	"updated_at": pagination.SortTime,
+	"deleted_at": pagination.SortTime,
+}
+
 func NewUserRepository(db *gorm.DB, log *zap.Logger) UserRepositoryInterface {
 	return &UserRepository{
 		db:  db,
@@ -54,6 +75,15 @@ func (r *UserRepository) GetUserById(data *model.User) (*model.User, error) {
 	return &user, nil
 }
 
+func (r *UserRepository) GetUsersByIds(ids []string) ([]*model.User, error) {
+	var users []*model.User
+	if err := r.db.Where("id IN ? AND deleted_at IS NULL", ids).Find(&users).Error; err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
 func (r *UserRepository) UpdateUser(data *model.User, id string) error {
 	updatedData := map[string]interface{}{
 		"name":  data.Name,
@@ -74,3 +104,99 @@ func (r *UserRepository) DeleteUser(id string) error {
 
 	return nil
 }
+
+func (r *UserRepository) GetDeleted(opts pagination.ListOptions) (*UserPage, error) {
+	if err := opts.Normalize(deletedUserSortColumns, "deleted_at", "desc"); err != nil {
+		return nil, err
+	}
+
+	base := r.db.Model(&model.User{}).Where("deleted_at IS NOT NULL")
+
+	if opts.Search != "" {
+		base.Where("(name ILIKE ? OR email ILIKE ?)", "%"+opts.Search+"%", "%"+opts.Search+"%")
+	}
+
+	page := &UserPage{}
+
+	if opts.IncludeTotal {
+		if err := base.Session(&gorm.Session{}).Count(&page.TotalCount).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	query, err := pagination.Paginate(base.Session(&gorm.Session{}), opts, deletedUserSortColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := query.Find(&page.Users).Error; err != nil {
+		return nil, err
+	}
+
+	if len(page.Users) > opts.PageSize {
+		page.Users = page.Users[:opts.PageSize]
+		last := page.Users[len(page.Users)-1]
+		page.NextPageToken = pagination.EncodePageToken(opts, userSortValue(last, opts.SortBy), last.ID)
+	}
+
+	return page, nil
+}
+
+func (r *UserRepository) GetDeletedById(id string) (*model.User, error) {
+	var user model.User
+	if err := r.db.Where("id = ? AND deleted_at IS NOT NULL", id).First(&user).Error; err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (r *UserRepository) Restore(id string) error {
+	if err := r.db.Model(&model.User{}).Where("id = ? AND deleted_at IS NOT NULL", id).Update("deleted_at", nil).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Purgeable lists users that have been in the trash since before the given
+// time, for the purge job to check against book-service.
+func (r *UserRepository) Purgeable(before time.Time, after string, limit int) ([]string, error) {
+	var ids []string
+	if err := r.db.Model(&model.User{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ? AND id::text > ?", before, after).
+		Order("id::text ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (r *UserRepository) PurgeIds(before time.Time, ids []string) (int64, error) {
+	result := r.db.Where("id IN ? AND deleted_at IS NOT NULL AND deleted_at < ?", ids, before).Delete(&model.User{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+func userSortValue(data *model.User, sortBy string) interface{} {
+	switch sortBy {
+	case "name":
+		return data.Name
+	case "email":
+		return data.Email
+	case "created_at":
+		return data.CreatedAt
+	case "updated_at":
+		return data.UpdatedAt
+	default:
+		if data.DeletedAt != nil {
+			return *data.DeletedAt
+		}
+		return nil
+	}
+}
@@ -2,19 +2,23 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/google/uuid"
 	"github.com/shafaalafghany/user-service/model"
 	"github.com/shafaalafghany/user-service/repository"
+	"gitlab.com/shafaalafghany/synapsis-common/pagination"
 	"gitlab.com/shafaalafghany/synapsis-proto/go/user"
 	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
 )
 
 type UserServiceInterface interface {
@@ -23,8 +27,13 @@ type UserServiceInterface interface {
 	Get(context.Context, *user.User) (*user.User, error)
 	Update(context.Context, *user.User, string) (*user.CommonUserResponse, error)
 	Delete(context.Context, string) (*user.CommonUserResponse, error)
+	ListDeleted(context.Context, *user.UserRequest, string) (*user.UsersResponse, error)
+	Restore(context.Context, *user.User, string) (*user.CommonUserResponse, error)
+	BatchGet(context.Context, *user.BatchGetUsersRequest, string) (*user.BatchGetUsersResponse, error)
 }
 
+const maxBatchSize = 100
+
 type UserService struct {
 	repo repository.UserRepositoryInterface
 	log  *zap.Logger
@@ -92,6 +101,7 @@ func (s *UserService) Login(ctx context.Context, body *user.LoginRequest) (*user
 		Id:        existsData.ID,
 		Email:     body.Email,
 		Name:      existsData.Name,
+		Role:      existsData.Role,
 		CreatedAt: existsData.CreatedAt.String(),
 		UpdatedAt: existsData.UpdatedAt.String(),
 	}
@@ -120,6 +130,7 @@ func (s *UserService) Get(ctx context.Context, body *user.User) (*user.User, err
 		Id:        existsUser.ID,
 		Email:     existsUser.Email,
 		Name:      existsUser.Name,
+		Role:      existsUser.Role,
 		CreatedAt: existsUser.CreatedAt.String(),
 		UpdatedAt: existsUser.UpdatedAt.String(),
 	}
@@ -165,3 +176,166 @@ func (s *UserService) Delete(ctx context.Context, id string) (*user.CommonUserRe
 
 	return &user.CommonUserResponse{Message: "delete user successfully"}, nil
 }
+
+func (s *UserService) ListDeleted(ctx context.Context, body *user.UserRequest, id string) (*user.UsersResponse, error) {
+	if err := s.requireAdmin(id); err != nil {
+		return nil, err
+	}
+
+	data, err := s.repo.GetDeleted(pagination.ListOptions{
+		Search:       body.GetSearch(),
+		PageSize:     int(body.GetPageSize()),
+		PageToken:    body.GetPageToken(),
+		SortBy:       body.GetSortBy(),
+		SortOrder:    body.GetSortOrder(),
+		IncludeTotal: body.GetIncludeTotal(),
+	})
+	if err != nil {
+		if errors.Is(err, pagination.ErrInvalidPageToken) || errors.Is(err, pagination.ErrInvalidSort) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	users := []*user.User{}
+	for _, v := range data.Users {
+		users = append(users, &user.User{
+			Id:        v.ID,
+			Name:      v.Name,
+			Email:     v.Email,
+			Role:      v.Role,
+			CreatedAt: v.CreatedAt.String(),
+			UpdatedAt: v.UpdatedAt.String(),
+			DeletedAt: v.DeletedAt.String(),
+		})
+	}
+
+	return &user.UsersResponse{
+		Users:         users,
+		NextPageToken: data.NextPageToken,
+		TotalCount:    data.TotalCount,
+	}, nil
+}
+
+func (s *UserService) Restore(ctx context.Context, body *user.User, id string) (*user.CommonUserResponse, error) {
+	if body.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id cannot be empty")
+	}
+
+	if err := s.requireAdmin(id); err != nil {
+		return nil, err
+	}
+
+	deletedUser, err := s.repo.GetDeletedById(body.GetId())
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Error(codes.NotFound, "deleted user not found")
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	activeUser, err := s.repo.GetUserByEmail(&model.User{Email: deletedUser.Email})
+	if err == nil && activeUser.ID != deletedUser.ID {
+		return nil, status.Error(codes.AlreadyExists, fmt.Sprintf("email %s is already used by user %s", deletedUser.Email, activeUser.ID))
+	} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if err := s.repo.Restore(deletedUser.ID); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &user.CommonUserResponse{Message: "restore user successfully"}, nil
+}
+
+func (s *UserService) BatchGet(ctx context.Context, body *user.BatchGetUsersRequest, id string) (*user.BatchGetUsersResponse, error) {
+	ids, invalid, err := batchIds(body.GetIds())
+	if err != nil {
+		return nil, err
+	}
+
+	caller, err := s.repo.GetUserById(&model.User{ID: id})
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid user")
+	}
+
+	if len(ids) == 0 {
+		return &user.BatchGetUsersResponse{Users: []*user.User{}, MissingIds: invalid}, nil
+	}
+
+	data, err := s.repo.GetUsersByIds(ids)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	found := map[string]*model.User{}
+	for _, v := range data {
+		found[v.ID] = v
+	}
+
+	users := []*user.User{}
+	missing := invalid
+	for _, userId := range ids {
+		v, ok := found[userId]
+		if !ok {
+			missing = append(missing, userId)
+			continue
+		}
+
+		res := &user.User{
+			Id:        v.ID,
+			Name:      v.Name,
+			Role:      v.Role,
+			CreatedAt: v.CreatedAt.String(),
+			UpdatedAt: v.UpdatedAt.String(),
+		}
+		if caller.Role == model.RoleAdmin || caller.ID == v.ID {
+			res.Email = v.Email
+		}
+		users = append(users, res)
+	}
+
+	return &user.BatchGetUsersResponse{
+		Users:      users,
+		MissingIds: missing,
+	}, nil
+}
+
+// batchIds dedupes the requested ids and splits off the ones that are not
+// UUIDs, which cannot match a row and would fail the query on the uuid column.
+func batchIds(ids []string) ([]string, []string, error) {
+	seen := map[string]bool{}
+	result := []string{}
+	invalid := []string{}
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		if _, err := uuid.Parse(id); err != nil {
+			invalid = append(invalid, id)
+			continue
+		}
+		result = append(result, id)
+	}
+
+	if len(result)+len(invalid) > maxBatchSize {
+		return nil, nil, status.Error(codes.InvalidArgument, fmt.Sprintf("at most %d ids can be requested at once", maxBatchSize))
+	}
+
+	return result, invalid, nil
+}
+
+func (s *UserService) requireAdmin(id string) error {
+	existsUser, err := s.repo.GetUserById(&model.User{ID: id})
+	if err != nil {
+		return status.Error(codes.Unauthenticated, "invalid user")
+	}
+
+	if existsUser.Role != model.RoleAdmin {
+		return status.Error(codes.PermissionDenied, "admin role required")
+	}
+
+	return nil
+}